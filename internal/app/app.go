@@ -2,18 +2,29 @@ package app
 
 import (
 	"context"
-	"flag"
 	"fmt"
 	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"net/http"
 	"os"
 	"os/signal"
+	"product/internal/auth"
 	"product/internal/config"
+	"product/internal/domain/job"
 	"product/internal/handler"
+	"product/internal/health"
+	"product/internal/metrics"
+	"product/internal/outbox"
 	"product/internal/repository"
+	"product/internal/repository/cache"
 	"product/internal/service"
+	"product/internal/tracing"
+	"product/internal/webhook"
+	"product/internal/worker"
 	"product/pkg/log"
 	"product/pkg/server"
+	"product/pkg/store"
 	"syscall"
 	"time"
 )
@@ -24,36 +35,144 @@ const (
 	description = "product-service"
 )
 
-// Run initializes whole application.
-func Run() {
+// Run initializes whole application and serves it until SIGINT or SIGTERM.
+// Requests in flight get up to wait to finish.
+func Run(cfg config.Config, wait time.Duration) (err error) {
 	logger := log.New(version, description)
 
-	cfg, err := config.New()
+	tracer, err := tracing.New(
+		tracing.WithExporter(cfg.TRACING.Exporter, cfg.TRACING.Endpoint, cfg.TRACING.Insecure),
+		tracing.WithSampleRatio(cfg.TRACING.SampleRatio),
+		tracing.WithService(cfg.TRACING.ServiceName, version))
 	if err != nil {
-		logger.Error("ERR_INIT_CONFIG", zap.Error(err))
+		logger.Error("ERR_INIT_TRACING", zap.Error(err))
 		return
 	}
 
-	repositories, err := repository.New(
-		repository.WithPostgresStore(schema, cfg.POSTGRES.DSN))
+	if cfg.POSTGRES.InMemory {
+		logger.Warn("the catalog is kept in memory, every change is lost on shutdown")
+	}
+
+	repositoryConfigs := []repository.Configuration{
+		newStore(cfg.POSTGRES),
+	}
+
+	cacheBackend, err := newCacheBackend(cfg.CACHE)
+	if err != nil {
+		logger.Error("ERR_INIT_CACHE", zap.Error(err))
+		return
+	}
+	if cacheBackend != nil {
+		repositoryConfigs = append(repositoryConfigs, repository.WithCache(cache.New(cacheBackend, cfg.CACHE.TTL)))
+	}
+
+	repositories, err := repository.New(repositoryConfigs...)
 	if err != nil {
 		logger.Error("ERR_INIT_REPOSITORY", zap.Error(err))
 		return
 	}
 	defer repositories.Close()
 
-	productService, err := service.New(
-		service.WithCategoryRepository(repositories.Category),
-		service.WithProductRepository(repositories.Product),
-	)
+	productService, err := newService(cfg, repositories)
 	if err != nil {
 		logger.Error("ERR_INIT_SERVICE", zap.Error(err))
 		return
 	}
 
+	metricsConfigs := []metrics.Configuration{
+		metrics.WithDatabase(repositories.DB(), "postgres"),
+		metrics.WithCatalog(productService.CountCatalog),
+	}
+	for i, replica := range repositories.Replicas() {
+		metricsConfigs = append(metricsConfigs, metrics.WithDatabase(replica, fmt.Sprintf("postgres_replica_%d", i)))
+	}
+
+	appMetrics, err := metrics.New(metricsConfigs...)
+	if err != nil {
+		logger.Error("ERR_INIT_METRICS", zap.Error(err))
+		return
+	}
+	store.ObserveQueries(appMetrics.ObserveQuery)
+
+	workers, err := worker.New(repositories.Job,
+		worker.WithHandlers(productService.JobHandlers()),
+		worker.WithConcurrency(cfg.JOBS.Workers),
+		worker.WithPollInterval(cfg.JOBS.PollInterval),
+		worker.WithSchedule(job.TypeSnapshot, cfg.SNAPSHOTS.Interval, func(ctx context.Context) error {
+			_, err := productService.QueueSnapshot(ctx)
+			return err
+		}),
+		worker.WithLogger(logger))
+	if err != nil {
+		logger.Error("ERR_INIT_WORKERS", zap.Error(err))
+		return
+	}
+	workers.Start()
+
+	publisher, err := newEventPublisher(cfg.OUTBOX, logger)
+	if err != nil {
+		logger.Error("ERR_INIT_PUBLISHER", zap.Error(err))
+		return
+	}
+
+	healthConfigs := []health.Configuration{
+		health.WithCheck("postgres", repositories.Ping),
+		health.WithCheck("migrations", repositories.CheckMigrations),
+	}
+	if cacheBackend != nil {
+		healthConfigs = append(healthConfigs, health.WithCheck("cache", repositories.PingCache))
+	}
+	if pinger, ok := publisher.(outbox.Pinger); ok {
+		healthConfigs = append(healthConfigs, health.WithCheck("publisher", pinger.Ping))
+	}
+
+	probes, err := health.New(healthConfigs...)
+	if err != nil {
+		logger.Error("ERR_INIT_HEALTH", zap.Error(err))
+		return
+	}
+
+	// Webhook deliveries are queued in the same transaction that marks an event as published.
+	publisher = outbox.MultiPublisher{webhook.NewPublisher(repositories.Webhook), publisher}
+
+	relay, err := outbox.New(repositories.Event, repositories.Transactor, publisher,
+		outbox.WithBatchSize(cfg.OUTBOX.BatchSize),
+		outbox.WithPollInterval(cfg.OUTBOX.PollInterval),
+		outbox.WithLogger(logger))
+	if err != nil {
+		logger.Error("ERR_INIT_RELAY", zap.Error(err))
+		return
+	}
+	relay.Start()
+
+	dispatcher, err := webhook.New(repositories.Webhook,
+		webhook.WithConcurrency(cfg.WEBHOOKS.Workers),
+		webhook.WithPollInterval(cfg.WEBHOOKS.PollInterval),
+		webhook.WithMaxAttempts(cfg.WEBHOOKS.MaxAttempts),
+		webhook.WithTimeout(cfg.WEBHOOKS.Timeout),
+		webhook.WithLogger(logger))
+	if err != nil {
+		logger.Error("ERR_INIT_DISPATCHER", zap.Error(err))
+		return
+	}
+	dispatcher.Start()
+
+	verifier, err := newVerifier(cfg.AUTH)
+	if err != nil {
+		logger.Error("ERR_INIT_AUTH", zap.Error(err))
+		return
+	}
+	if verifier == nil {
+		logger.Warn("authentication is disabled, anyone reaching the port may change the catalog")
+	}
+
 	handlers, err := handler.New(
 		handler.Dependencies{
-			Service: productService,
+			Service:  productService,
+			Configs:  cfg,
+			Verifier: verifier,
+			Metrics:  appMetrics,
+			Health:   probes,
 		},
 		handler.WithHTTPHandler())
 	if err != nil {
@@ -61,8 +180,18 @@ func Run() {
 		return
 	}
 
-	servers, err := server.New(
-		server.WithHTTPServer(handlers.HTTP, cfg.HTTP.Port))
+	serverConfigs := []server.Configuration{
+		server.WithHTTPServer(handlers.HTTP, cfg.HTTP.Port),
+	}
+	if cfg.ADMIN.Port != "" {
+		admin := http.NewServeMux()
+		admin.Handle("/metrics", appMetrics.Handler())
+		admin.HandleFunc("/healthz", probes.Live)
+		admin.HandleFunc("/readyz", probes.Ready)
+		serverConfigs = append(serverConfigs, server.WithAdminServer(admin, cfg.ADMIN.Port))
+	}
+
+	servers, err := server.New(serverConfigs...)
 	if err != nil {
 		logger.Error("ERR_INIT_SERVER", zap.Error(err))
 		return
@@ -75,10 +204,6 @@ func Run() {
 	}
 
 	// Graceful Shutdown
-	var wait time.Duration
-	flag.DurationVar(&wait, "graceful-timeout", time.Second*15, "the duration for which the httpServer gracefully wait for existing connections to finish - e.g. 15s or 1m")
-	flag.Parse()
-
 	quit := make(chan os.Signal, 1) // create channel to signify a signal being sent
 
 	// We'll accept graceful shutdowns when quit via SIGINT (Ctrl+C)
@@ -88,6 +213,11 @@ func Run() {
 	<-quit                                             // This blocks the main thread until an interrupt is received
 	fmt.Println("Gracefully shutting down...")
 
+	// Fail readiness first, so load balancers stop routing to this instance
+	// before it stops accepting requests.
+	probes.Drain()
+	time.Sleep(cfg.HTTP.DrainDelay)
+
 	// create a deadline to wait for.
 	ctx, cancel := context.WithTimeout(context.Background(), wait)
 	defer cancel()
@@ -95,11 +225,118 @@ func Run() {
 	// Doesn't block if no connections, but will otherwise wait
 	// until the timeout deadline.
 	if err = servers.Stop(ctx); err != nil {
-		panic(err) // failure/timeout shutting down the httpServer gracefully
+		logger.Error("ERR_STOP_SERVER", zap.Error(err)) // failure/timeout shutting down the httpServer gracefully
+		return
 	}
 
 	fmt.Println("Running cleanup tasks...")
-	// Your cleanup tasks go here
+	// Running jobs are interrupted and put back in the queue from their last checkpoint.
+	if err = workers.Stop(ctx); err != nil {
+		logger.Error("ERR_STOP_WORKERS", zap.Error(err))
+	}
+	if err = relay.Stop(ctx); err != nil {
+		logger.Error("ERR_STOP_RELAY", zap.Error(err))
+	}
+	if err = dispatcher.Stop(ctx); err != nil {
+		logger.Error("ERR_STOP_DISPATCHER", zap.Error(err))
+	}
+	if err = tracer.Shutdown(ctx); err != nil {
+		logger.Error("ERR_STOP_TRACING", zap.Error(err))
+	}
 
 	fmt.Println("Server was successful shutdown.")
+
+	return nil
+}
+
+// newService creates the service on top of repositories.
+func newService(cfg config.Config, repositories *repository.Repository) (*service.Service, error) {
+	return service.New(
+		service.WithCategoryRepository(repositories.Category),
+		service.WithProductRepository(repositories.Product),
+		service.WithModifierRepository(repositories.Modifier),
+		service.WithBrandRepository(repositories.Brand),
+		service.WithSupplierRepository(repositories.Supplier),
+		service.WithImportRepository(repositories.Import),
+		service.WithJobRepository(repositories.Job),
+		service.WithAuditRepository(repositories.Audit),
+		service.WithRevisionRepository(repositories.Revision),
+		service.WithEventRepository(repositories.Event),
+		service.WithWebhookRepository(repositories.Webhook),
+		service.WithSnapshotRepository(repositories.Snapshot),
+		service.WithAPIKeyRepository(repositories.APIKey),
+		service.WithSnapshotRetention(cfg.SNAPSHOTS.Retention),
+		service.WithTransactor(repositories.Transactor),
+	)
+}
+
+// newStore selects where the catalog is kept: in memory or in the database.
+func newStore(cfg config.DatabaseConfig) repository.Configuration {
+	if cfg.InMemory {
+		return repository.WithMemoryStore()
+	}
+
+	return repository.WithPostgresStore(schema, cfg.DSN, cfg.Migrate, newDatabaseConfigs(cfg)...)
+}
+
+// newDatabaseConfigs sets up the connection pools and the read replicas of the database.
+func newDatabaseConfigs(cfg config.DatabaseConfig) []store.Configuration {
+	return []store.Configuration{
+		store.WithPool(store.PoolConfig{
+			MaxOpenConns:     cfg.MaxOpenConns,
+			MaxIdleConns:     cfg.MaxIdleConns,
+			ConnMaxLifetime:  cfg.ConnMaxLifetime,
+			ConnMaxIdleTime:  cfg.ConnMaxIdleTime,
+			StatementTimeout: cfg.StatementTimeout,
+		}),
+		store.WithReplicas(cfg.Replicas, cfg.ReplicaCheckInterval, cfg.ReplicaMaxLag),
+	}
+}
+
+// newEventPublisher creates the publisher the outbox relay delivers domain events to.
+func newEventPublisher(cfg config.OutboxConfig, logger *zap.Logger) (outbox.Publisher, error) {
+	switch cfg.Publisher {
+	case "log":
+		return outbox.NewLogPublisher(logger), nil
+	case "file":
+		return outbox.NewFilePublisher(cfg.File)
+	case "nats":
+		return outbox.NewNATSPublisher(cfg.NATSURL, cfg.Subject)
+	default:
+		return nil, fmt.Errorf("unknown event publisher %q", cfg.Publisher)
+	}
+}
+
+// newCacheBackend creates the backend catalog reads are cached in, or nil when caching is off.
+func newCacheBackend(cfg config.CacheConfig) (cache.Backend, error) {
+	switch cfg.Backend {
+	case "none":
+		return nil, nil
+	case "lru":
+		return cache.NewLRU(cfg.Size), nil
+	case "redis":
+		options, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		remote := cache.NewRedis(redis.NewClient(options), cfg.Prefix)
+		return cache.NewTiered(cache.NewLRU(cfg.Size), remote, cfg.LocalTTL), nil
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", cfg.Backend)
+	}
+}
+
+// newVerifier creates the verifier of bearer tokens, or nil when authentication is disabled.
+func newVerifier(cfg config.AuthConfig) (*auth.Verifier, error) {
+	if cfg.Disabled {
+		return nil, nil
+	}
+
+	return auth.New(
+		auth.WithHMACSecret(cfg.Secret),
+		auth.WithRSAPublicKeyFile(cfg.PublicKeyFile),
+		auth.WithJWKSFile(cfg.JWKSFile),
+		auth.WithIssuer(cfg.Issuer),
+		auth.WithAudience(cfg.Audience),
+		auth.WithRolesClaim(cfg.RolesClaim))
 }
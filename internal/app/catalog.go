@@ -0,0 +1,215 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"product/internal/domain/fixture"
+	"product/internal/domain/imports"
+	"product/internal/domain/product"
+	"product/pkg/store"
+)
+
+const defaultFixture = "fixtures/catalog.json"
+
+// commandContext is canceled on SIGINT or SIGTERM, so a long import stops
+// between batches instead of being killed halfway. The command reads its own
+// writes, see store.WithSession.
+func commandContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(store.WithSession(context.Background()), os.Interrupt, syscall.SIGTERM)
+}
+
+func seedCommand(args []string) int {
+	flags := newFlagSet("seed", "")
+	file := flags.String("file", defaultFixture, "JSON fixture of categories and their products")
+	if code, stop := parseFlags(flags, args); stop {
+		return code
+	}
+
+	data, err := os.Open(*file)
+	if err != nil {
+		return fail("seed", ExitFailure, err)
+	}
+	defer data.Close()
+
+	catalog, err := fixture.Read(data)
+	if err != nil {
+		return fail("seed", ExitFailure, fmt.Errorf("%s: %w", *file, err))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail("seed", ExitConfig, err)
+	}
+
+	productService, repositories, err := openService(cfg)
+	if err != nil {
+		return fail("seed", ExitFailure, err)
+	}
+	defer repositories.Close()
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	res, err := productService.SeedCatalog(ctx, catalog)
+	if err != nil {
+		return fail("seed", ExitFailure, err)
+	}
+
+	fmt.Printf("categories created: %d, products upserted: %d\n", res.CategoriesCreated, res.ProductsUpserted)
+	return ExitOK
+}
+
+// importCommand imports a file right away rather than queuing a job. It
+// exits with ExitFailure when the import fails or any row is rejected; the
+// import itself, with the errors of every row, is printed as JSON.
+func importCommand(args []string) int {
+	flags := newFlagSet("import", "<file>")
+	format := flags.String("format", "", "csv or xlsx; guessed from the file name by default")
+	mapping := flags.String("mapping", "", `JSON object of product field to column header, e.g. {"name":"Title"}`)
+	dryRun := flags.Bool("dry-run", false, "validate the file without saving any product")
+	batchSize := flags.Int("batch-size", 0, "rows written per transaction (default 500)")
+
+	// The file may come before the flags as well as after them.
+	var path string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		path, args = args[0], args[1:]
+	}
+	if code, stop := parseFlags(flags, args); stop {
+		return code
+	}
+
+	if path == "" && flags.NArg() == 1 {
+		path = flags.Arg(0)
+	} else if path == "" || flags.NArg() != 0 {
+		return usageError(flags, errors.New("expected a single file"))
+	}
+
+	req := imports.Request{
+		Filename:  filepath.Base(path),
+		Format:    *format,
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	}
+	if *mapping != "" {
+		if err := json.Unmarshal([]byte(*mapping), &req.Mapping); err != nil {
+			return usageError(flags, errors.New("mapping: must be a JSON object"))
+		}
+	}
+
+	var err error
+	if req.File, err = os.ReadFile(path); err != nil {
+		return fail("import", ExitFailure, err)
+	}
+	if err = req.Validate(); err != nil {
+		return usageError(flags, err)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail("import", ExitConfig, err)
+	}
+
+	productService, repositories, err := openService(cfg)
+	if err != nil {
+		return fail("import", ExitFailure, err)
+	}
+	defer repositories.Close()
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	res, err := productService.ImportFile(ctx, req, func(processed, total int) error {
+		fmt.Fprintf(os.Stderr, "processed %d rows\n", processed)
+		return nil
+	})
+	if res.ID != "" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(res)
+	}
+	if err != nil {
+		return fail("import", ExitFailure, err)
+	}
+	if res.FailedRows > 0 {
+		return fail("import", ExitFailure, fmt.Errorf("%d rows rejected", res.FailedRows))
+	}
+
+	return ExitOK
+}
+
+// exportCommand streams the products matching the filters to a file, or to
+// the standard output.
+func exportCommand(args []string) int {
+	flags := newFlagSet("export", "")
+	output := flags.String("o", "-", "file to write, - for the standard output")
+
+	var req product.ExportRequest
+	flags.StringVar(&req.Format, "format", product.ExportFormatCSV, "csv, jsonl or xlsx")
+	flags.BoolVar(&req.Gzip, "gzip", false, "compress the output with gzip")
+	flags.StringVar(&req.Filters.Search, "search", "", "only products whose name contains the text")
+	flags.StringVar(&req.Filters.BrandID, "brand-id", "", "only products of the brand")
+	flags.StringVar(&req.Filters.SupplierID, "supplier-id", "", "only products of the supplier")
+	flags.StringVar(&req.Filters.ProducerCountry, "producer-country", "", "only products made in the country")
+	flags.Func("cost-gte", "only products costing at least this much", intFilter(&req.Filters.CostGTE))
+	flags.Func("cost-lte", "only products costing at most this much", intFilter(&req.Filters.CostLTE))
+	if code, stop := parseFlags(flags, args); stop {
+		return code
+	}
+
+	req.Filters.Search = strings.ToLower(req.Filters.Search)
+	req.Filters.ProducerCountry = strings.ToUpper(strings.TrimSpace(req.Filters.ProducerCountry))
+	switch req.Format {
+	case product.ExportFormatCSV, product.ExportFormatJSONL, product.ExportFormatXLSX:
+	default:
+		return usageError(flags, errors.New("format: must be csv, jsonl or xlsx"))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail("export", ExitConfig, err)
+	}
+
+	productService, repositories, err := openService(cfg)
+	if err != nil {
+		return fail("export", ExitFailure, err)
+	}
+	defer repositories.Close()
+
+	w := os.Stdout
+	if *output != "-" {
+		if w, err = os.Create(*output); err != nil {
+			return fail("export", ExitFailure, err)
+		}
+		defer w.Close()
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	if err = productService.ExportProducts(ctx, req, w); err != nil {
+		return fail("export", ExitFailure, err)
+	}
+
+	return ExitOK
+}
+
+// intFilter parses an optional integer flag into dest.
+func intFilter(dest **int) func(string) error {
+	return func(value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New("must be an integer")
+		}
+		*dest = &parsed
+		return nil
+	}
+}
@@ -0,0 +1,256 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"product/internal/config"
+	"product/internal/repository"
+	"product/internal/service"
+	"product/internal/tracing"
+	"product/pkg/store"
+)
+
+// Exit codes of the commands.
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+	ExitConfig  = 3
+)
+
+// command is a subcommand of the binary.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) int
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "run the HTTP server, the job workers and the outbox relay (default)", serveCommand},
+		{"migrate", "apply, roll back or inspect database migrations", migrateCommand},
+		{"seed", "load a fixture catalog of categories and products", seedCommand},
+		{"import", "import products from a CSV or XLSX file", importCommand},
+		{"export", "export products to a CSV, JSONL or XLSX file", exportCommand},
+		{"check-config", "validate the configuration read from the environment", checkConfigCommand},
+	}
+}
+
+// Main runs the command named by args[0] and returns its exit code. Without a
+// command, or when args start with a flag, the server is started, so
+// existing deployments keep working.
+func Main(args []string) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "help", "-h", "-help", "--help":
+			usage(os.Stdout)
+			return ExitOK
+		}
+	}
+
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return serveCommand(args)
+	}
+
+	name := args[0]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(args[1:])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage(os.Stderr)
+	return ExitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", description)
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for the flags of a command.\n", description)
+	fmt.Fprintf(w, "\nExit codes: %d success, %d failure, %d invalid usage, %d invalid configuration.\n",
+		ExitOK, ExitFailure, ExitUsage, ExitConfig)
+}
+
+// newFlagSet returns the flags of a command, reporting errors instead of exiting.
+func newFlagSet(name, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s %s %s [flags]\n\nFlags:\n", description, name, arguments)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses args and returns the exit code to stop with, if any.
+func parseFlags(flags *flag.FlagSet, args []string) (code int, stop bool) {
+	err := flags.Parse(args)
+	switch {
+	case err == nil:
+		return ExitOK, false
+	case errors.Is(err, flag.ErrHelp):
+		return ExitOK, true
+	default:
+		return ExitUsage, true
+	}
+}
+
+// fail reports err on the standard error and returns code.
+func fail(name string, code int, err error) int {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+	return code
+}
+
+// loadConfig reads the configuration and checks it, see validateConfig.
+func loadConfig() (cfg config.Config, err error) {
+	if cfg, err = config.New(); err != nil {
+		return
+	}
+
+	err = validateConfig(cfg)
+
+	return
+}
+
+// openService connects to the database for the commands that work on the
+// catalog directly, without starting the server.
+func openService(cfg config.Config) (*service.Service, *repository.Repository, error) {
+	repositories, err := repository.New(newStore(cfg.POSTGRES))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	productService, err := newService(cfg, repositories)
+	if err != nil {
+		repositories.Close()
+		return nil, nil, err
+	}
+
+	return productService, repositories, nil
+}
+
+func serveCommand(args []string) int {
+	flags := newFlagSet("serve", "")
+	wait := flags.Duration("graceful-timeout", 15*time.Second, "how long requests in flight may take to finish on shutdown, e.g. 15s or 1m")
+	if code, stop := parseFlags(flags, args); stop {
+		return code
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail("serve", ExitConfig, err)
+	}
+
+	if err = Run(cfg, *wait); err != nil {
+		return fail("serve", ExitFailure, err)
+	}
+
+	return ExitOK
+}
+
+func checkConfigCommand(args []string) int {
+	flags := newFlagSet("check-config", "")
+	if code, stop := parseFlags(flags, args); stop {
+		return code
+	}
+
+	if _, err := loadConfig(); err != nil {
+		return fail("check-config", ExitConfig, err)
+	}
+
+	fmt.Println("configuration is valid")
+	return ExitOK
+}
+
+// validateConfig reports every setting that would make the server fail to
+// start, without connecting to anything.
+func validateConfig(cfg config.Config) error {
+	var errs []error
+
+	switch source, err := url.Parse(cfg.POSTGRES.DSN); {
+	case cfg.POSTGRES.InMemory:
+	case cfg.POSTGRES.DSN == "":
+		errs = append(errs, errors.New("POSTGRES_DSN: cannot be blank"))
+	case err != nil || source.Scheme != "postgres":
+		errs = append(errs, errors.New("POSTGRES_DSN: must be a postgres:// URL"))
+	}
+
+	for i, replica := range cfg.POSTGRES.Replicas {
+		if source, err := url.Parse(replica); err != nil || source.Scheme != "postgres" {
+			errs = append(errs, fmt.Errorf("POSTGRES_REPLICAS: replica %d must be a postgres:// URL", i+1))
+		}
+	}
+	if cfg.POSTGRES.MaxOpenConns < 0 || cfg.POSTGRES.MaxIdleConns < 0 {
+		errs = append(errs, errors.New("POSTGRES_MAX_OPEN_CONNS, POSTGRES_MAX_IDLE_CONNS: cannot be negative"))
+	}
+	if cfg.POSTGRES.StatementTimeout < 0 || cfg.POSTGRES.ReplicaMaxLag < 0 {
+		errs = append(errs, errors.New("POSTGRES_STATEMENT_TIMEOUT, POSTGRES_REPLICA_MAX_LAG: cannot be negative"))
+	}
+
+	switch cfg.POSTGRES.Migrate {
+	case store.MigrateAuto, store.MigrateVerify, store.MigrateOff:
+	default:
+		errs = append(errs, fmt.Errorf("POSTGRES_MIGRATE: unknown migration mode %q", cfg.POSTGRES.Migrate))
+	}
+
+	if cfg.HTTP.Port == "" {
+		errs = append(errs, errors.New("HTTP_PORT: cannot be blank"))
+	}
+
+	switch cfg.OUTBOX.Publisher {
+	case "log", "file", "nats":
+	default:
+		errs = append(errs, fmt.Errorf("OUTBOX_PUBLISHER: unknown event publisher %q", cfg.OUTBOX.Publisher))
+	}
+
+	switch cfg.CACHE.Backend {
+	case "none":
+	case "lru":
+		// Replicas are there for a deployment of several instances, whose lru
+		// caches never learn of the writes of the others.
+		if len(cfg.POSTGRES.Replicas) > 0 {
+			errs = append(errs, errors.New("CACHE_BACKEND: lru cannot be used with replicas, use redis"))
+		}
+	case "redis":
+		if _, err := redis.ParseURL(cfg.CACHE.RedisURL); err != nil {
+			errs = append(errs, fmt.Errorf("CACHE_REDIS_URL: %w", err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("CACHE_BACKEND: unknown cache backend %q", cfg.CACHE.Backend))
+	}
+
+	switch cfg.TRACING.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER: unknown trace exporter %q", cfg.TRACING.Exporter))
+	}
+	if cfg.TRACING.SampleRatio < 0 || cfg.TRACING.SampleRatio > 1 {
+		errs = append(errs, errors.New("TRACING_SAMPLE_RATIO: must be between 0 and 1"))
+	}
+
+	if _, err := newVerifier(cfg.AUTH); err != nil {
+		errs = append(errs, fmt.Errorf("AUTH: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// usageError reports err along with the usage of the command.
+func usageError(flags *flag.FlagSet, err error) int {
+	fmt.Fprintf(flags.Output(), "%s: %v\n", flags.Name(), err)
+	flags.Usage()
+	return ExitUsage
+}
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+
+	"product/migrations"
+	"product/pkg/store"
+)
+
+const migrateArguments = "up|down|goto <version>|force <version>|status"
+
+// migrateCommand moves the schema with golang-migrate. status exits with
+// ExitFailure when migrations are pending or the schema is dirty, so scripts
+// can wait on it.
+func migrateCommand(args []string) int {
+	flags := newFlagSet("migrate", migrateArguments)
+	steps := flags.Int("steps", 0, "number of migrations to apply with up or roll back with down; up applies all by default")
+	all := flags.Bool("all", false, "roll back every migration with down")
+
+	// The action comes first, e.g. "migrate down -steps 1", and the flags after
+	// it. The version of goto and force follows the action, so that the -1 of
+	// "migrate force -1" is not taken for a flag.
+	var action string
+	var arguments []string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		action, args = args[0], args[1:]
+		if (action == "goto" || action == "force") && len(args) > 0 {
+			if _, err := strconv.Atoi(args[0]); err == nil {
+				arguments, args = args[:1], args[1:]
+			}
+		}
+	}
+	if code, stop := parseFlags(flags, args); stop {
+		return code
+	}
+
+	arguments = append(arguments, flags.Args()...)
+	if action == "" && len(arguments) > 0 {
+		action, arguments = arguments[0], arguments[1:]
+	}
+
+	var version int
+	switch action {
+	case "up", "status":
+		if len(arguments) != 0 {
+			return usageError(flags, fmt.Errorf("%s takes no argument", action))
+		}
+	case "down":
+		if len(arguments) != 0 {
+			return usageError(flags, errors.New("down takes no argument"))
+		}
+		if (*steps > 0) == *all {
+			return usageError(flags, errors.New("down needs either -steps or -all"))
+		}
+	case "goto", "force":
+		if len(arguments) != 1 {
+			return usageError(flags, fmt.Errorf("%s takes a version", action))
+		}
+		// Only force takes -1, which marks the schema as having no migration.
+		minVersion := 0
+		if action == "force" {
+			minVersion = -1
+		}
+		var err error
+		if version, err = strconv.Atoi(arguments[0]); err != nil || version < minVersion {
+			return usageError(flags, fmt.Errorf("invalid version %q", arguments[0]))
+		}
+	default:
+		return usageError(flags, fmt.Errorf("unknown action %q", action))
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return fail("migrate", ExitConfig, err)
+	}
+
+	database, err := store.NewDatabase(schema, cfg.POSTGRES.DSN)
+	if err != nil {
+		return fail("migrate", ExitFailure, err)
+	}
+	defer database.Close()
+
+	migrator, err := database.Migrator(migrations.FS)
+	if err != nil {
+		return fail("migrate", ExitFailure, err)
+	}
+	defer migrator.Close()
+
+	if action == "status" {
+		return migrateStatus(migrator)
+	}
+
+	ctx, cancel := commandContext()
+	defer cancel()
+
+	// Instances starting meanwhile wait for the schema instead of migrating it too.
+	err = database.WithMigrationLock(ctx, func() error {
+		switch action {
+		case "up":
+			if *steps > 0 {
+				return migrator.Steps(*steps)
+			}
+			return migrator.Up()
+		case "down":
+			if *all {
+				return migrator.Down()
+			}
+			return migrator.Steps(-*steps)
+		case "goto":
+			return migrator.Migrate(uint(version))
+		default:
+			return migrator.Force(version)
+		}
+	})
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Println("no change")
+		err = nil
+	}
+	if err != nil {
+		return fail("migrate", ExitFailure, err)
+	}
+
+	return migrateStatus(migrator)
+}
+
+// migrateStatus prints the version of the schema and of the newest migration.
+func migrateStatus(migrator *migrate.Migrate) int {
+	latest, err := store.LatestMigration(migrations.FS)
+	if err != nil {
+		return fail("migrate", ExitFailure, err)
+	}
+
+	version, dirty, err := migrator.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Printf("version: none, latest: %d\n", latest)
+		return ExitFailure
+	}
+	if err != nil {
+		return fail("migrate", ExitFailure, err)
+	}
+
+	fmt.Printf("version: %d, latest: %d, dirty: %t\n", version, latest, dirty)
+	if dirty || version != latest {
+		return ExitFailure
+	}
+
+	return ExitOK
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+type jwks struct {
+	hmacKeys map[string][]byte
+	rsaKeys  map[string]*rsa.PublicKey
+}
+
+// parseJWKS reads the RSA and symmetric signing keys of a JSON Web Key Set.
+// Keys meant for encryption and of other types are skipped.
+func parseJWKS(data []byte) (set jwks, err error) {
+	var document struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.Unmarshal(data, &document); err != nil {
+		return
+	}
+
+	set = jwks{
+		hmacKeys: make(map[string][]byte),
+		rsaKeys:  make(map[string]*rsa.PublicKey),
+	}
+
+	for _, key := range document.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		switch key.Kty {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(key.N)
+			if err != nil {
+				return set, errors.New("jwks: invalid modulus of key " + key.Kid)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(key.E)
+			if err != nil || len(e) == 0 || len(e) > 4 {
+				return set, errors.New("jwks: invalid exponent of key " + key.Kid)
+			}
+			set.rsaKeys[key.Kid] = &rsa.PublicKey{
+				N: new(big.Int).SetBytes(n),
+				E: int(new(big.Int).SetBytes(e).Int64()),
+			}
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil || len(secret) == 0 {
+				return set, errors.New("jwks: invalid secret of key " + key.Kid)
+			}
+			set.hmacKeys[key.Kid] = secret
+		}
+	}
+
+	if len(set.hmacKeys) == 0 && len(set.rsaKeys) == 0 {
+		err = errors.New("jwks: no signing keys")
+	}
+
+	return
+}
@@ -0,0 +1,221 @@
+// Package auth validates the bearer tokens of API callers.
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"product/internal/domain/auth"
+)
+
+const (
+	defaultRolesClaim = "roles"
+	leeway            = 30 * time.Second
+)
+
+var (
+	ErrNoKeys       = errors.New("auth: no signing key configured")
+	ErrUnknownKey   = errors.New("auth: token signed with an unknown key")
+	ErrInvalidToken = errors.New("auth: invalid token")
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Verifier and modify it
+type Configuration func(v *Verifier) error
+
+// Verifier checks JWTs signed with HS256 or RS256 and turns their claims into
+// an auth.Identity. Keys are matched on the kid header when the token has one.
+type Verifier struct {
+	hmacKeys   map[string][]byte
+	rsaKeys    map[string]*rsa.PublicKey
+	issuer     string
+	audience   string
+	rolesClaim string
+}
+
+// New takes a variable amount of Configuration functions and returns a new Verifier
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (v *Verifier, err error) {
+	v = &Verifier{
+		hmacKeys:   make(map[string][]byte),
+		rsaKeys:    make(map[string]*rsa.PublicKey),
+		rolesClaim: defaultRolesClaim,
+	}
+
+	for _, cfg := range configs {
+		if err = cfg(v); err != nil {
+			return
+		}
+	}
+
+	if len(v.hmacKeys) == 0 && len(v.rsaKeys) == 0 {
+		err = ErrNoKeys
+	}
+
+	return
+}
+
+// WithHMACSecret accepts HS256 tokens signed with secret.
+func WithHMACSecret(secret string) Configuration {
+	return func(v *Verifier) error {
+		if secret != "" {
+			v.hmacKeys[""] = []byte(secret)
+		}
+		return nil
+	}
+}
+
+// WithRSAPublicKeyFile accepts RS256 tokens signed with the private half of
+// the PEM encoded public key at path.
+func WithRSAPublicKeyFile(path string) Configuration {
+	return func(v *Verifier) error {
+		if path == "" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return fmt.Errorf("auth: %s: %w", path, err)
+		}
+		v.rsaKeys[""] = key
+
+		return nil
+	}
+}
+
+// WithJWKSFile accepts tokens signed with any key of the JSON Web Key Set at path.
+func WithJWKSFile(path string) Configuration {
+	return func(v *Verifier) error {
+		if path == "" {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		set, err := parseJWKS(data)
+		if err != nil {
+			return fmt.Errorf("auth: %s: %w", path, err)
+		}
+		for kid, key := range set.hmacKeys {
+			v.hmacKeys[kid] = key
+		}
+		for kid, key := range set.rsaKeys {
+			v.rsaKeys[kid] = key
+		}
+
+		return nil
+	}
+}
+
+// WithIssuer requires the iss claim to equal issuer.
+func WithIssuer(issuer string) Configuration {
+	return func(v *Verifier) error {
+		v.issuer = issuer
+		return nil
+	}
+}
+
+// WithAudience requires the aud claim to contain audience.
+func WithAudience(audience string) Configuration {
+	return func(v *Verifier) error {
+		v.audience = audience
+		return nil
+	}
+}
+
+// WithRolesClaim sets the claim holding the roles, either a list or a
+// space separated string.
+func WithRolesClaim(claim string) Configuration {
+	return func(v *Verifier) error {
+		if claim != "" {
+			v.rolesClaim = claim
+		}
+		return nil
+	}
+}
+
+// Verify checks the signature and the registered claims of token and
+// returns the identity it carries.
+func (v *Verifier) Verify(token string) (identity auth.Identity, err error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err = jwt.ParseWithClaims(token, claims, v.key, options...); err != nil {
+		if errors.Is(err, ErrUnknownKey) {
+			return identity, ErrUnknownKey
+		}
+		return identity, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	if identity.Subject, err = claims.GetSubject(); err != nil || identity.Subject == "" {
+		return auth.Identity{}, fmt.Errorf("%w: missing subject", ErrInvalidToken)
+	}
+	for _, claim := range []string{"name", "preferred_username"} {
+		if name, ok := claims[claim].(string); ok && name != "" {
+			identity.Name = name
+			break
+		}
+	}
+	identity.Roles = parseRoles(claims[v.rolesClaim])
+
+	return identity, nil
+}
+
+// key picks the verification key of a token. The key type must match the
+// algorithm, so an RSA public key can never be used as an HMAC secret.
+func (v *Verifier) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	// A key configured without a kid is used for any token the set has no key for.
+	for _, id := range []string{kid, ""} {
+		switch token.Method.(type) {
+		case *jwt.SigningMethodHMAC:
+			if key, ok := v.hmacKeys[id]; ok {
+				return key, nil
+			}
+		case *jwt.SigningMethodRSA:
+			if key, ok := v.rsaKeys[id]; ok {
+				return key, nil
+			}
+		}
+	}
+
+	return nil, ErrUnknownKey
+}
+
+func parseRoles(value any) (roles []string) {
+	switch value := value.(type) {
+	case string:
+		return strings.Fields(value)
+	case []any:
+		for _, role := range value {
+			if role, ok := role.(string); ok {
+				roles = append(roles, role)
+			}
+		}
+	}
+	return
+}
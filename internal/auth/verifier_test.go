@@ -0,0 +1,396 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"maps"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testSecret   = "0123456789abcdef0123456789abcdef"
+	testIssuer   = "https://id.example.com"
+	testAudience = "catalog"
+)
+
+// keys are the signing keys of the tests and the files a Verifier reads them from.
+type keys struct {
+	rsa       *rsa.PrivateKey
+	jwksRSA   *rsa.PrivateKey
+	publicPEM []byte
+	pemPath   string
+	jwksPath  string
+}
+
+func newKeys(t *testing.T) keys {
+	t.Helper()
+
+	k := keys{rsa: generateKey(t), jwksRSA: generateKey(t)}
+
+	der, err := x509.MarshalPKIXPublicKey(&k.rsa.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.publicPEM = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+	k.pemPath = writeFile(t, "key.pem", k.publicPEM)
+
+	set := map[string]any{"keys": []map[string]string{
+		{
+			"kty": "RSA",
+			"kid": "rsa-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(k.jwksRSA.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.jwksRSA.E)).Bytes()),
+		},
+		{"kty": "oct", "kid": "hmac-1", "k": base64.RawURLEncoding.EncodeToString([]byte(testSecret + "-jwks"))},
+		{"kty": "oct", "kid": "enc-1", "use": "enc", "k": base64.RawURLEncoding.EncodeToString([]byte("encryption"))},
+		{"kty": "EC", "kid": "ec-1"},
+	}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	k.jwksPath = writeFile(t, "jwks.json", data)
+
+	return k
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// validClaims returns claims every verifier of the tests accepts.
+func validClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "user-1",
+		"name":  "Ada",
+		"iss":   testIssuer,
+		"aud":   testAudience,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"roles": []string{"catalog-manager", "kiosk-readonly"},
+	}
+}
+
+// with returns the valid claims changed by fn.
+func with(fn func(claims jwt.MapClaims)) jwt.MapClaims {
+	claims := validClaims()
+	fn(claims)
+	return claims
+}
+
+func TestVerify(t *testing.T) {
+	k := newKeys(t)
+
+	verifier, err := New(
+		WithHMACSecret(testSecret),
+		WithRSAPublicKeyFile(k.pemPath),
+		WithJWKSFile(k.jwksPath),
+		WithIssuer(testIssuer),
+		WithAudience(testAudience),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaOnly, err := New(WithRSAPublicKeyFile(k.pemPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		verifier  *Verifier
+		token     string
+		wantErr   error
+		wantRoles []string
+	}{
+		{
+			name:      "HS256",
+			token:     sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()),
+			wantRoles: []string{"catalog-manager", "kiosk-readonly"},
+		},
+		{
+			name:      "RS256",
+			token:     sign(t, jwt.SigningMethodRS256, "", k.rsa, validClaims()),
+			wantRoles: []string{"catalog-manager", "kiosk-readonly"},
+		},
+		{
+			name:      "RS256 from the key set",
+			token:     sign(t, jwt.SigningMethodRS256, "rsa-1", k.jwksRSA, validClaims()),
+			wantRoles: []string{"catalog-manager", "kiosk-readonly"},
+		},
+		{
+			name:      "HS256 from the key set",
+			token:     sign(t, jwt.SigningMethodHS256, "hmac-1", []byte(testSecret+"-jwks"), validClaims()),
+			wantRoles: []string{"catalog-manager", "kiosk-readonly"},
+		},
+		{
+			name:      "unknown kid falls back to the key without one",
+			token:     sign(t, jwt.SigningMethodRS256, "rsa-9", k.rsa, validClaims()),
+			wantRoles: []string{"catalog-manager", "kiosk-readonly"},
+		},
+		{
+			name:    "unknown kid signed with another key",
+			token:   sign(t, jwt.SigningMethodRS256, "rsa-9", generateKey(t), validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:     "unknown kid without a fallback key",
+			verifier: rsaOnly,
+			token:    sign(t, jwt.SigningMethodHS256, "hmac-9", []byte(testSecret), validClaims()),
+			wantErr:  ErrUnknownKey,
+		},
+		{
+			name:     "HS256 signed with the RSA public key",
+			verifier: rsaOnly,
+			token:    sign(t, jwt.SigningMethodHS256, "", k.publicPEM, validClaims()),
+			wantErr:  ErrUnknownKey,
+		},
+		{
+			name:    "HS256 signed with the RSA public key when a secret is configured",
+			token:   sign(t, jwt.SigningMethodHS256, "", k.publicPEM, validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "encryption key of the key set",
+			token:   sign(t, jwt.SigningMethodHS256, "enc-1", []byte("encryption"), validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "none algorithm",
+			token:   sign(t, jwt.SigningMethodNone, "", jwt.UnsafeAllowNoneSignatureType, validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "HS512",
+			token:   sign(t, jwt.SigningMethodHS512, "", []byte(testSecret), validClaims()),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "expired",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				c["exp"] = time.Now().Add(-time.Minute).Unix()
+			})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "expired within the leeway",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				c["exp"] = time.Now().Add(-leeway / 2).Unix()
+			})),
+			wantRoles: []string{"catalog-manager", "kiosk-readonly"},
+		},
+		{
+			name: "missing exp",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				delete(c, "exp")
+			})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "wrong issuer",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				c["iss"] = "https://evil.example.com"
+			})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "wrong audience",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				c["aud"] = "billing"
+			})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "audience among others",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				c["aud"] = []string{"billing", testAudience}
+			})),
+			wantRoles: []string{"catalog-manager", "kiosk-readonly"},
+		},
+		{
+			name: "missing subject",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				delete(c, "sub")
+			})),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name: "roles as a space separated string",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				c["roles"] = "catalog-manager  kiosk-readonly"
+			})),
+			wantRoles: []string{"catalog-manager", "kiosk-readonly"},
+		},
+		{
+			name: "roles of another type",
+			token: sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+				c["roles"] = 42
+			})),
+		},
+		{
+			name:    "tampered payload",
+			token:   sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), validClaims()) + "x",
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := verifier
+			if tt.verifier != nil {
+				v = tt.verifier
+			}
+
+			identity, err := v.Verify(tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+
+			if identity.Subject != "user-1" || identity.Name != "Ada" {
+				t.Errorf("Verify() identity = %+v, want subject user-1 named Ada", identity)
+			}
+			if !slices.Equal(identity.Roles, tt.wantRoles) {
+				t.Errorf("Verify() roles = %q, want %q", identity.Roles, tt.wantRoles)
+			}
+		})
+	}
+}
+
+func TestRolesClaim(t *testing.T) {
+	verifier, err := New(WithHMACSecret(testSecret), WithRolesClaim("groups"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := sign(t, jwt.SigningMethodHS256, "", []byte(testSecret), with(func(c jwt.MapClaims) {
+		c["groups"] = []string{"catalog-admin"}
+	}))
+	identity, err := verifier.Verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(identity.Roles, []string{"catalog-admin"}) {
+		t.Errorf("Verify() roles = %q, want the groups claim", identity.Roles)
+	}
+}
+
+func TestNewWithoutKeys(t *testing.T) {
+	if _, err := New(WithHMACSecret(""), WithRSAPublicKeyFile("")); !errors.Is(err, ErrNoKeys) {
+		t.Errorf("New() error = %v, want %v", err, ErrNoKeys)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantHMAC []string
+		wantRSA  []string
+		wantErr  bool
+	}{
+		{
+			name:     "signing keys",
+			document: `{"keys":[{"kty":"RSA","kid":"a","n":"AQAB","e":"AQAB"},{"kty":"oct","kid":"b","use":"sig","k":"c2VjcmV0"}]}`,
+			wantHMAC: []string{"b"},
+			wantRSA:  []string{"a"},
+		},
+		{
+			name:     "skips encryption keys and other types",
+			document: `{"keys":[{"kty":"oct","kid":"a","k":"c2VjcmV0"},{"kty":"oct","kid":"b","use":"enc","k":"c2VjcmV0"},{"kty":"EC","kid":"c"}]}`,
+			wantHMAC: []string{"a"},
+		},
+		{
+			name:     "no signing keys",
+			document: `{"keys":[{"kty":"EC","kid":"c"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid modulus",
+			document: `{"keys":[{"kty":"RSA","kid":"a","n":"***","e":"AQAB"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "exponent too large",
+			document: `{"keys":[{"kty":"RSA","kid":"a","n":"AQAB","e":"AQIDBAU"}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "empty secret",
+			document: `{"keys":[{"kty":"oct","kid":"a","k":""}]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "not JSON",
+			document: `keys`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := parseJWKS([]byte(tt.document))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJWKS() error = %v, want error %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got := slices.Sorted(maps.Keys(set.hmacKeys)); !slices.Equal(got, tt.wantHMAC) {
+				t.Errorf("parseJWKS() HMAC keys = %q, want %q", got, tt.wantHMAC)
+			}
+			if got := slices.Sorted(maps.Keys(set.rsaKeys)); !slices.Equal(got, tt.wantRSA) {
+				t.Errorf("parseJWKS() RSA keys = %q, want %q", got, tt.wantRSA)
+			}
+		})
+	}
+}
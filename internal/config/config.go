@@ -15,12 +15,58 @@ const (
 	defaultHTTPWriteTimeout       = 15 * time.Second
 	defaultHTTPIdleTimeout        = 60 * time.Second
 	defaultHTTPMaxHeaderMegabytes = 1
+	defaultHTTPDrainDelay         = 5 * time.Second
+
+	defaultAdminPort = "9090"
+
+	defaultPostgresMigrate              = "auto"
+	defaultPostgresMaxOpenConns         = 20
+	defaultPostgresMaxIdleConns         = 10
+	defaultPostgresConnMaxLifetime      = 30 * time.Minute
+	defaultPostgresConnMaxIdleTime      = 5 * time.Minute
+	defaultPostgresReplicaCheckInterval = 5 * time.Second
+
+	defaultJobsWorkers      = 2
+	defaultJobsPollInterval = time.Second
+
+	defaultOutboxPublisher    = "log"
+	defaultOutboxFile         = "events.jsonl"
+	defaultOutboxNATSURL      = "nats://localhost:4222"
+	defaultOutboxSubject      = "catalog"
+	defaultOutboxBatchSize    = 100
+	defaultOutboxPollInterval = time.Second
+
+	defaultWebhooksWorkers      = 4
+	defaultWebhooksPollInterval = time.Second
+	defaultWebhooksMaxAttempts  = 8
+	defaultWebhooksTimeout      = 10 * time.Second
+
+	defaultSnapshotsRetention = 10
+
+	defaultCacheBackend  = "none"
+	defaultCacheSize     = 10000
+	defaultCacheTTL      = time.Minute
+	defaultCacheLocalTTL = 5 * time.Second
+	defaultCacheRedisURL = "redis://localhost:6379/0"
+	defaultCachePrefix   = "catalog:"
+
+	defaultTracingExporter    = "none"
+	defaultTracingSampleRatio = 1.0
+	defaultTracingServiceName = "product-service"
 )
 
 type (
 	Config struct {
-		HTTP     HTTPConfig
-		POSTGRES DatabaseConfig
+		HTTP      HTTPConfig
+		ADMIN     AdminConfig
+		POSTGRES  DatabaseConfig
+		JOBS      JobsConfig
+		OUTBOX    OutboxConfig
+		WEBHOOKS  WebhooksConfig
+		SNAPSHOTS SnapshotsConfig
+		CACHE     CacheConfig
+		AUTH      AuthConfig
+		TRACING   TracingConfig
 	}
 
 	HTTPConfig struct {
@@ -31,6 +77,16 @@ type (
 		IdleTimeout        time.Duration
 		MaxHeaderMegabytes int
 		Schema             string
+		CORSOrigins        []string `split_words:"true"`
+		// DrainDelay is how long /readyz fails before the server stops
+		// accepting requests on shutdown, for load balancers to notice.
+		DrainDelay time.Duration `split_words:"true"`
+	}
+
+	// AdminConfig sets the port of the admin server, which serves /metrics
+	// apart from the API. An empty Port turns it off.
+	AdminConfig struct {
+		Port string
 	}
 
 	ClientConfig struct {
@@ -39,8 +95,101 @@ type (
 		Password string
 	}
 
+	// DatabaseConfig sets the database and what happens to its schema on
+	// startup: Migrate is auto to apply pending migrations, verify to refuse
+	// to start while any is pending or the schema is dirty, or off.
+	//
+	// Reads are spread over the Replicas, a comma-separated list of DSNs of
+	// read-only copies of the database, while they answer their health check
+	// every ReplicaCheckInterval and, when ReplicaMaxLag is set, lag no further
+	// behind. The pool settings apply to the primary and to each replica; a
+	// zero StatementTimeout lets statements run for as long as they take.
+	//
+	// InMemory keeps the catalog in memory instead of the database, for
+	// development only: nothing survives a restart and jobs, webhooks,
+	// modifier groups, snapshots and API keys are not available.
 	DatabaseConfig struct {
-		DSN string
+		InMemory             bool `split_words:"true"`
+		DSN                  string
+		Migrate              string
+		Replicas             []string
+		ReplicaCheckInterval time.Duration `split_words:"true"`
+		ReplicaMaxLag        time.Duration `split_words:"true"`
+		MaxOpenConns         int           `split_words:"true"`
+		MaxIdleConns         int           `split_words:"true"`
+		ConnMaxLifetime      time.Duration `split_words:"true"`
+		ConnMaxIdleTime      time.Duration `split_words:"true"`
+		StatementTimeout     time.Duration `split_words:"true"`
+	}
+
+	JobsConfig struct {
+		Workers      int
+		PollInterval time.Duration `split_words:"true"`
+	}
+
+	// OutboxConfig selects where domain events are published: log, file or nats.
+	OutboxConfig struct {
+		Publisher    string
+		File         string
+		NATSURL      string `envconfig:"NATS_URL"`
+		Subject      string
+		BatchSize    int           `split_words:"true"`
+		PollInterval time.Duration `split_words:"true"`
+	}
+
+	// WebhooksConfig tunes how deliveries are sent; a delivery is dead-lettered
+	// after MaxAttempts failures.
+	WebhooksConfig struct {
+		Workers      int
+		PollInterval time.Duration `split_words:"true"`
+		MaxAttempts  int           `split_words:"true"`
+		Timeout      time.Duration
+	}
+
+	// SnapshotsConfig sets how many catalog snapshots are kept in the
+	// database. A new one is generated every Interval, when set, besides those
+	// requested through the API.
+	SnapshotsConfig struct {
+		Interval  time.Duration
+		Retention int
+	}
+
+	// CacheConfig selects the cache of catalog reads: none, lru or redis. An
+	// lru cache is only dropped on the instance that wrote, so it suits a
+	// single instance without replicas; several instances share redis, and
+	// each also keeps up to Size values locally for LocalTTL.
+	CacheConfig struct {
+		Backend  string
+		Size     int
+		TTL      time.Duration
+		LocalTTL time.Duration `split_words:"true"`
+		RedisURL string        `split_words:"true"`
+		Prefix   string
+	}
+
+	// AuthConfig sets the keys bearer tokens are checked with: an HS256
+	// Secret, an RS256 PublicKeyFile in PEM and/or a JWKSFile. Disabled turns
+	// authentication off, for local development only.
+	AuthConfig struct {
+		Disabled      bool
+		Secret        string
+		PublicKeyFile string `split_words:"true"`
+		JWKSFile      string `envconfig:"JWKS_FILE"`
+		Issuer        string
+		Audience      string
+		RolesClaim    string `split_words:"true"`
+	}
+
+	// TracingConfig selects where spans are exported: none, otlp or stdout.
+	// Endpoint is the URL of the OTLP/HTTP collector, e.g.
+	// http://localhost:4318/v1/traces; the OTEL_EXPORTER_OTLP_* variables
+	// apply when it is empty.
+	TracingConfig struct {
+		Exporter    string
+		Endpoint    string
+		Insecure    bool
+		SampleRatio float64 `split_words:"true"`
+		ServiceName string  `split_words:"true"`
 	}
 )
 
@@ -58,9 +207,63 @@ func New() (cfg Config, err error) {
 		WriteTimeout:       defaultHTTPWriteTimeout,
 		IdleTimeout:        defaultHTTPIdleTimeout,
 		MaxHeaderMegabytes: defaultHTTPMaxHeaderMegabytes,
+		DrainDelay:         defaultHTTPDrainDelay,
 	}
 	cfg.HTTP = httpConfig
 
+	cfg.ADMIN = AdminConfig{
+		Port: defaultAdminPort,
+	}
+
+	cfg.POSTGRES = DatabaseConfig{
+		Migrate:              defaultPostgresMigrate,
+		ReplicaCheckInterval: defaultPostgresReplicaCheckInterval,
+		MaxOpenConns:         defaultPostgresMaxOpenConns,
+		MaxIdleConns:         defaultPostgresMaxIdleConns,
+		ConnMaxLifetime:      defaultPostgresConnMaxLifetime,
+		ConnMaxIdleTime:      defaultPostgresConnMaxIdleTime,
+	}
+
+	cfg.JOBS = JobsConfig{
+		Workers:      defaultJobsWorkers,
+		PollInterval: defaultJobsPollInterval,
+	}
+
+	cfg.OUTBOX = OutboxConfig{
+		Publisher:    defaultOutboxPublisher,
+		File:         defaultOutboxFile,
+		NATSURL:      defaultOutboxNATSURL,
+		Subject:      defaultOutboxSubject,
+		BatchSize:    defaultOutboxBatchSize,
+		PollInterval: defaultOutboxPollInterval,
+	}
+
+	cfg.WEBHOOKS = WebhooksConfig{
+		Workers:      defaultWebhooksWorkers,
+		PollInterval: defaultWebhooksPollInterval,
+		MaxAttempts:  defaultWebhooksMaxAttempts,
+		Timeout:      defaultWebhooksTimeout,
+	}
+
+	cfg.SNAPSHOTS = SnapshotsConfig{
+		Retention: defaultSnapshotsRetention,
+	}
+
+	cfg.CACHE = CacheConfig{
+		Backend:  defaultCacheBackend,
+		Size:     defaultCacheSize,
+		TTL:      defaultCacheTTL,
+		LocalTTL: defaultCacheLocalTTL,
+		RedisURL: defaultCacheRedisURL,
+		Prefix:   defaultCachePrefix,
+	}
+
+	cfg.TRACING = TracingConfig{
+		Exporter:    defaultTracingExporter,
+		SampleRatio: defaultTracingSampleRatio,
+		ServiceName: defaultTracingServiceName,
+	}
+
 	godotenv.Load(filepath.Join(root, ".env"))
 
 	err = envconfig.Process("HTTP", &cfg.HTTP)
@@ -68,10 +271,50 @@ func New() (cfg Config, err error) {
 		return
 	}
 
+	err = envconfig.Process("ADMIN", &cfg.ADMIN)
+	if err != nil {
+		return
+	}
+
 	err = envconfig.Process("POSTGRES", &cfg.POSTGRES)
 	if err != nil {
 		return
 	}
 
+	err = envconfig.Process("JOBS", &cfg.JOBS)
+	if err != nil {
+		return
+	}
+
+	err = envconfig.Process("OUTBOX", &cfg.OUTBOX)
+	if err != nil {
+		return
+	}
+
+	err = envconfig.Process("WEBHOOKS", &cfg.WEBHOOKS)
+	if err != nil {
+		return
+	}
+
+	err = envconfig.Process("SNAPSHOTS", &cfg.SNAPSHOTS)
+	if err != nil {
+		return
+	}
+
+	err = envconfig.Process("CACHE", &cfg.CACHE)
+	if err != nil {
+		return
+	}
+
+	err = envconfig.Process("AUTH", &cfg.AUTH)
+	if err != nil {
+		return
+	}
+
+	err = envconfig.Process("TRACING", &cfg.TRACING)
+	if err != nil {
+		return
+	}
+
 	return
 }
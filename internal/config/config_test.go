@@ -0,0 +1,38 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEnvironmentNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		got   func(cfg Config) any
+		want  any
+	}{
+		{"JOBS_POLL_INTERVAL", "3s", func(cfg Config) any { return cfg.JOBS.PollInterval }, 3 * time.Second},
+		{"OUTBOX_NATS_URL", "nats://nats:4222", func(cfg Config) any { return cfg.OUTBOX.NATSURL }, "nats://nats:4222"},
+		{"OUTBOX_BATCH_SIZE", "50", func(cfg Config) any { return cfg.OUTBOX.BatchSize }, 50},
+		{"OUTBOX_POLL_INTERVAL", "2s", func(cfg Config) any { return cfg.OUTBOX.PollInterval }, 2 * time.Second},
+		{"WEBHOOKS_POLL_INTERVAL", "4s", func(cfg Config) any { return cfg.WEBHOOKS.PollInterval }, 4 * time.Second},
+		{"WEBHOOKS_MAX_ATTEMPTS", "7", func(cfg Config) any { return cfg.WEBHOOKS.MaxAttempts }, 7},
+		{"CACHE_LOCAL_TTL", "1s", func(cfg Config) any { return cfg.CACHE.LocalTTL }, time.Second},
+		{"CACHE_REDIS_URL", "redis://cache:6379/1", func(cfg Config) any { return cfg.CACHE.RedisURL }, "redis://cache:6379/1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.name, tt.value)
+
+			cfg, err := New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tt.got(cfg); got != tt.want {
+				t.Errorf("%s=%s read as %v, want %v", tt.name, tt.value, got, tt.want)
+			}
+		})
+	}
+}
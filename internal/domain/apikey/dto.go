@@ -0,0 +1,115 @@
+package apikey
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"product/pkg/validate"
+)
+
+// maxGracePeriod bounds how long a rotated key keeps working.
+const maxGracePeriod = 30 * 24 * time.Hour
+
+func init() {
+	validate.Register(&Request{})
+}
+
+type Request struct {
+	Name      string     `json:"name" validate:"required"`
+	Scopes    []string   `json:"scopes"`
+	StoreID   string     `json:"store_id"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Bind reports every invalid field at once, as validate.Errors.
+func (s *Request) Bind(r *http.Request) error {
+	errs := validate.Struct(s)
+
+	if len(s.Scopes) == 0 {
+		errs.Add("scopes", validate.CodeRequired, "cannot be empty")
+	}
+	for i, scope := range s.Scopes {
+		if !ValidScope(scope) {
+			errs.Add(fmt.Sprintf("scopes[%d]", i), validate.CodeChoice, fmt.Sprintf("unknown scope %q", scope))
+		}
+	}
+
+	if s.ExpiresAt != nil && !s.ExpiresAt.After(time.Now()) {
+		errs.Add("expires_at", validate.CodeTooSmall, "must be in the future")
+	}
+
+	return errs.Err()
+}
+
+// RotateRequest sets for how many seconds the rotated key keeps working, so
+// devices can switch over; by default it stops at once.
+type RotateRequest struct {
+	GracePeriod int `json:"grace_period"`
+}
+
+// Bind reports an invalid grace period as validate.Errors.
+func (s *RotateRequest) Bind(r *http.Request) error {
+	var errs validate.Errors
+	if s.GracePeriod < 0 {
+		errs.Add("grace_period", validate.CodeTooSmall, "must be at least 0")
+	}
+	if maxSeconds := int(maxGracePeriod.Seconds()); s.GracePeriod > maxSeconds {
+		errs.Add("grace_period", validate.CodeTooLarge, fmt.Sprintf("must be at most %d seconds", maxSeconds))
+	}
+
+	return errs.Err()
+}
+
+type Response struct {
+	ID          string     `json:"id"`
+	CreatedAt   time.Time  `json:"created_at"`
+	Name        string     `json:"name"`
+	Prefix      string     `json:"prefix"`
+	Scopes      []string   `json:"scopes"`
+	StoreID     string     `json:"store_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	RotatedFrom string     `json:"rotated_from,omitempty"`
+}
+
+// IssuedResponse is returned once, when a key is issued or rotated. The key
+// cannot be recovered afterwards.
+type IssuedResponse struct {
+	Response
+	Key string `json:"key"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:         data.ID,
+		Name:       *data.Name,
+		Prefix:     *data.Prefix,
+		Scopes:     []string(data.Scopes),
+		ExpiresAt:  data.ExpiresAt,
+		LastUsedAt: data.LastUsedAt,
+		RevokedAt:  data.RevokedAt,
+	}
+	if data.CreatedAt != nil {
+		res.CreatedAt = *data.CreatedAt
+	}
+	if data.StoreID != nil {
+		res.StoreID = *data.StoreID
+	}
+	if data.RotatedFrom != nil {
+		res.RotatedFrom = *data.RotatedFrom
+	}
+	if res.Scopes == nil {
+		res.Scopes = make([]string, 0)
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
@@ -0,0 +1,70 @@
+package apikey
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"product/pkg/validate"
+)
+
+func codes(t *testing.T, err error) (res []string) {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want validate.Errors", err)
+	}
+	for _, fieldError := range errs {
+		res = append(res, fieldError.Field+"="+fieldError.Code)
+	}
+	return
+}
+
+func TestRequestBind(t *testing.T) {
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name string
+		req  Request
+		want []string
+	}{
+		{"valid", Request{Name: "till 1", Scopes: []string{ScopeCatalogRead}, ExpiresAt: &future}, nil},
+		{"empty", Request{}, []string{"name=required", "scopes=required"}},
+		{"unknown scopes", Request{Name: "till 1", Scopes: []string{ScopeCatalogRead, "catalog:delete", "admin"}}, []string{"scopes[1]=invalid_choice", "scopes[2]=invalid_choice"}},
+		{"expired", Request{Name: "till 1", Scopes: []string{ScopeCatalogRead}, ExpiresAt: &past}, []string{"expires_at=too_small"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(t, tt.req.Bind(nil)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bind() errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotateRequestBind(t *testing.T) {
+	maxSeconds := int(maxGracePeriod.Seconds())
+
+	tests := []struct {
+		gracePeriod int
+		want        []string
+	}{
+		{0, nil},
+		{maxSeconds, nil},
+		{-1, []string{"grace_period=too_small"}},
+		{maxSeconds + 1, []string{"grace_period=too_large"}},
+	}
+
+	for _, tt := range tests {
+		req := RotateRequest{GracePeriod: tt.gracePeriod}
+		if got := codes(t, req.Bind(nil)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Bind() errors = %v for %d seconds, want %v", got, tt.gracePeriod, tt.want)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Entity is a device API key. Only the SHA-256 of the secret part is stored;
+// Prefix is the public part used to find the key.
+type Entity struct {
+	ID          string         `db:"id"`
+	CreatedAt   *time.Time     `db:"created_at"`
+	Name        *string        `db:"name"`
+	Prefix      *string        `db:"prefix"`
+	Hash        *string        `db:"hash"`
+	Scopes      pq.StringArray `db:"scopes"`
+	StoreID     *string        `db:"store_id"`
+	ExpiresAt   *time.Time     `db:"expires_at"`
+	LastUsedAt  *time.Time     `db:"last_used_at"`
+	RevokedAt   *time.Time     `db:"revoked_at"`
+	RotatedFrom *string        `db:"rotated_from"`
+}
@@ -0,0 +1,56 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// keyPrefix starts every key, so leaked keys are easy to scan for.
+const keyPrefix = "pk_"
+
+var ErrMalformedKey = errors.New("apikey: malformed key")
+
+// Generate returns a new key "pk_<prefix>_<secret>" and its public prefix.
+func Generate() (key, prefix string, err error) {
+	random := make([]byte, 4+32)
+	if _, err = rand.Read(random); err != nil {
+		return
+	}
+
+	prefix = hex.EncodeToString(random[:4])
+	key = keyPrefix + prefix + "_" + base64.RawURLEncoding.EncodeToString(random[4:])
+
+	return
+}
+
+// Split returns the public prefix of key.
+func Split(key string) (prefix string, err error) {
+	rest, ok := strings.CutPrefix(key, keyPrefix)
+	if !ok {
+		return "", ErrMalformedKey
+	}
+
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 8 || secret == "" {
+		return "", ErrMalformedKey
+	}
+
+	return
+}
+
+// Hash returns what is stored of key. Keys are long and random, so a plain
+// SHA-256 is enough to make a leaked table useless.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Matches compares key with a stored hash in constant time.
+func Matches(key, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(Hash(key)), []byte(hash)) == 1
+}
@@ -0,0 +1,77 @@
+package apikey
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"product/internal/domain/auth"
+)
+
+func TestGenerate(t *testing.T) {
+	key, prefix, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(key, keyPrefix+prefix+"_") {
+		t.Errorf("key %q does not start with its prefix %q", key, prefix)
+	}
+
+	split, err := Split(key)
+	if err != nil || split != prefix {
+		t.Errorf("Split(%q) = %q, %v, want %q", key, split, err, prefix)
+	}
+
+	other, _, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == key {
+		t.Error("two keys are alike")
+	}
+}
+
+func TestSplitMalformed(t *testing.T) {
+	for _, key := range []string{"", "pk_", "sk_0123abcd_secret", "pk_0123abcd", "pk_0123abcd_", "pk_0123abc_secret"} {
+		if _, err := Split(key); !errors.Is(err, ErrMalformedKey) {
+			t.Errorf("Split(%q) error = %v, want %v", key, err, ErrMalformedKey)
+		}
+	}
+}
+
+func TestMatches(t *testing.T) {
+	key, _, err := Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := Hash(key)
+
+	if strings.Contains(hash, key) || len(hash) != 64 {
+		t.Errorf("Hash(%q) = %q, want a hex SHA-256", key, hash)
+	}
+	if !Matches(key, hash) {
+		t.Error("a key does not match its own hash")
+	}
+	if Matches(key+"x", hash) {
+		t.Error("another key matches the hash")
+	}
+}
+
+func TestRoles(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		want   []string
+	}{
+		{[]string{ScopeCatalogRead}, []string{auth.RoleKioskReadOnly}},
+		{[]string{ScopeCatalogRead, ScopeCatalogWrite, ScopeCatalogRead}, []string{auth.RoleKioskReadOnly, auth.RoleCatalogManager}},
+		{[]string{ScopeReservationsCreate}, nil},
+		{[]string{"admin"}, nil},
+	}
+
+	for _, tt := range tests {
+		if got := Roles(tt.scopes); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Roles(%v) = %v, want %v", tt.scopes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package apikey
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Select(ctx context.Context) (dest []Entity, err error)
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (dest Entity, err error)
+	// GetActive returns the key with the given prefix unless it was revoked or expired.
+	GetActive(ctx context.Context, prefix string) (dest Entity, err error)
+	// Touch records that the key was just used, at most once every interval.
+	Touch(ctx context.Context, id string, interval time.Duration) (err error)
+	// Expire makes a key stop working after the given delay, unless it expires sooner.
+	Expire(ctx context.Context, id string, after time.Duration) (err error)
+	Revoke(ctx context.Context, id string) (err error)
+}
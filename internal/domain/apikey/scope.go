@@ -0,0 +1,38 @@
+package apikey
+
+import (
+	"slices"
+
+	"product/internal/domain/auth"
+)
+
+const (
+	ScopeCatalogRead  = "catalog:read"
+	ScopeCatalogWrite = "catalog:write"
+	// ScopeReservationsCreate lets a kiosk place reservations with the services
+	// that take them; it grants nothing in this one.
+	ScopeReservationsCreate = "reservations:create"
+)
+
+// scopeRoles maps every scope to the role it grants on the API routes.
+var scopeRoles = map[string]string{
+	ScopeCatalogRead:        auth.RoleKioskReadOnly,
+	ScopeCatalogWrite:       auth.RoleCatalogManager,
+	ScopeReservationsCreate: "",
+}
+
+// ValidScope reports whether scope is one a key can carry.
+func ValidScope(scope string) bool {
+	_, ok := scopeRoles[scope]
+	return ok
+}
+
+// Roles returns the roles granted by scopes.
+func Roles(scopes []string) (roles []string) {
+	for _, scope := range scopes {
+		if role := scopeRoles[scope]; role != "" && !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return
+}
@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"context"
+)
+
+// SystemActor is recorded for changes made outside of a request.
+const SystemActor = "system"
+
+// Metadata identifies who made a change and within which request.
+type Metadata struct {
+	Actor     string `json:"actor,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+type metadataKey struct{}
+
+// WithMetadata returns a copy of ctx carrying m.
+func WithMetadata(ctx context.Context, m Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, m)
+}
+
+// FromContext returns the metadata carried by ctx. The actor defaults to SystemActor.
+func FromContext(ctx context.Context) (m Metadata) {
+	m, _ = ctx.Value(metadataKey{}).(Metadata)
+	if m.Actor == "" {
+		m.Actor = SystemActor
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Change is the value of a single field before and after a change.
+// Before is omitted for creates and After for deletes.
+type Change struct {
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+}
+
+// Diff compares the JSON representation of two snapshots field by field and
+// returns the fields whose value differs. A nil snapshot has no fields, so a
+// create or delete lists every field. The id field is left out.
+func Diff(before, after any) (changes map[string]Change, err error) {
+	left, err := fields(before)
+	if err != nil {
+		return
+	}
+
+	right, err := fields(after)
+	if err != nil {
+		return
+	}
+
+	changes = make(map[string]Change)
+	for name, value := range left {
+		if other, ok := right[name]; !ok || !bytes.Equal(value, other) {
+			changes[name] = Change{Before: value, After: right[name]}
+		}
+	}
+	for name, value := range right {
+		if _, ok := left[name]; !ok {
+			changes[name] = Change{After: value}
+		}
+	}
+	delete(changes, "id")
+
+	return
+}
+
+func fields(snapshot any) (dest map[string]json.RawMessage, err error) {
+	if snapshot == nil {
+		return
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &dest)
+
+	return
+}
@@ -0,0 +1,49 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type snapshot struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Cost int    `json:"cost"`
+}
+
+func TestDiff(t *testing.T) {
+	before := snapshot{ID: "1", Name: "Apple", Cost: 100}
+
+	tests := []struct {
+		name   string
+		before any
+		after  any
+		want   map[string]Change
+	}{
+		{"create", nil, before, map[string]Change{
+			"name": {After: json.RawMessage(`"Apple"`)},
+			"cost": {After: json.RawMessage(`100`)},
+		}},
+		{"update", before, snapshot{ID: "2", Name: "Apple", Cost: 120}, map[string]Change{
+			"cost": {Before: json.RawMessage(`100`), After: json.RawMessage(`120`)},
+		}},
+		{"no change", before, before, map[string]Change{}},
+		{"delete", before, nil, map[string]Change{
+			"name": {Before: json.RawMessage(`"Apple"`)},
+			"cost": {Before: json.RawMessage(`100`)},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.before, tt.after)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
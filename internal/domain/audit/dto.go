@@ -0,0 +1,48 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Response struct {
+	ID         int64             `json:"id"`
+	CreatedAt  time.Time         `json:"created_at"`
+	Actor      string            `json:"actor"`
+	RequestID  string            `json:"request_id,omitempty"`
+	EntityType string            `json:"entity_type"`
+	EntityID   string            `json:"entity_id"`
+	Operation  string            `json:"operation"`
+	Changes    map[string]Change `json:"changes"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:         data.ID,
+		Actor:      *data.Actor,
+		EntityType: *data.EntityType,
+		EntityID:   *data.EntityID,
+		Operation:  *data.Operation,
+	}
+	if data.CreatedAt != nil {
+		res.CreatedAt = *data.CreatedAt
+	}
+	if data.RequestID != nil {
+		res.RequestID = *data.RequestID
+	}
+	if data.Changes != nil {
+		json.Unmarshal([]byte(*data.Changes), &res.Changes)
+	}
+	if res.Changes == nil {
+		res.Changes = make(map[string]Change)
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"time"
+)
+
+const (
+	EntityProduct       = "product"
+	EntityCategory      = "category"
+	EntityModifierGroup = "modifier_group"
+	EntityBrand         = "brand"
+	EntitySupplier      = "supplier"
+)
+
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+type Entity struct {
+	ID         int64      `db:"id"`
+	CreatedAt  *time.Time `db:"created_at"`
+	Actor      *string    `db:"actor"`
+	RequestID  *string    `db:"request_id"`
+	EntityType *string    `db:"entity_type"`
+	EntityID   *string    `db:"entity_id"`
+	Operation  *string    `db:"operation"`
+	Changes    *string    `db:"changes"`
+}
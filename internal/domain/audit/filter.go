@@ -0,0 +1,58 @@
+package audit
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// Filters narrows down the audit log. Records are returned newest first.
+type Filters struct {
+	Actor      string
+	EntityType string
+	EntityID   string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+// ParseFilters reads the filters from the query string. from and to are
+// RFC 3339 timestamps.
+func ParseFilters(r *http.Request) (filters Filters, err error) {
+	query := r.URL.Query()
+
+	filters.Actor = query.Get("actor")
+	filters.EntityType = query.Get("entity")
+	filters.EntityID = query.Get("entity_id")
+
+	if value := query.Get("from"); value != "" {
+		from, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filters, errors.New("from: must be an RFC 3339 timestamp")
+		}
+		filters.From = &from
+	}
+
+	if value := query.Get("to"); value != "" {
+		to, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return filters, errors.New("to: must be an RFC 3339 timestamp")
+		}
+		filters.To = &to
+	}
+
+	filters.Limit = defaultLimit
+	if value := query.Get("limit"); value != "" {
+		if filters.Limit, err = strconv.Atoi(value); err != nil || filters.Limit <= 0 || filters.Limit > maxLimit {
+			return filters, errors.New("limit: must be between 1 and " + strconv.Itoa(maxLimit))
+		}
+	}
+
+	return
+}
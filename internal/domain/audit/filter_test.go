@@ -0,0 +1,51 @@
+package audit
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseFilters(t *testing.T) {
+	r := httptest.NewRequest("GET", "/audit?actor=alice&entity=product&entity_id=1&from=2026-01-01T00:00:00Z&to=2026-02-01T00:00:00Z&limit=10", nil)
+
+	filters, err := ParseFilters(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filters.Actor != "alice" || filters.EntityType != EntityProduct || filters.EntityID != "1" || filters.Limit != 10 {
+		t.Errorf("got %+v", filters)
+	}
+	if want := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC); filters.From == nil || !filters.From.Equal(want) {
+		t.Errorf("got from %v, want %v", filters.From, want)
+	}
+	if want := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC); filters.To == nil || !filters.To.Equal(want) {
+		t.Errorf("got to %v, want %v", filters.To, want)
+	}
+}
+
+func TestParseFiltersDefaults(t *testing.T) {
+	filters, err := ParseFilters(httptest.NewRequest("GET", "/audit", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filters != (Filters{Limit: defaultLimit}) {
+		t.Errorf("got %+v, want only the default limit", filters)
+	}
+}
+
+func TestParseFiltersInvalid(t *testing.T) {
+	tests := map[string]string{
+		"from=yesterday": "from: must be an RFC 3339 timestamp",
+		"to=2026-02-01":  "to: must be an RFC 3339 timestamp",
+		"limit=0":        "limit: must be between 1 and 1000",
+		"limit=1001":     "limit: must be between 1 and 1000",
+		"limit=ten":      "limit: must be between 1 and 1000",
+	}
+
+	for query, want := range tests {
+		if _, err := ParseFilters(httptest.NewRequest("GET", "/audit?"+query, nil)); err == nil || err.Error() != want {
+			t.Errorf("ParseFilters(%q) = %v, want %q", query, err, want)
+		}
+	}
+}
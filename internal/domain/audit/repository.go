@@ -0,0 +1,10 @@
+package audit
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id int64, err error)
+	Select(ctx context.Context, filters Filters) (dest []Entity, err error)
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"slices"
+)
+
+const (
+	// RoleAdmin may do anything, including managing webhooks and reading the audit log.
+	RoleAdmin = "admin"
+	// RoleCatalogManager may read and change the catalog.
+	RoleCatalogManager = "catalog-manager"
+	// RoleKioskReadOnly may only read the catalog.
+	RoleKioskReadOnly = "kiosk-readonly"
+)
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	Subject string
+	Name    string
+	Roles   []string
+	// Scopes and StoreID are set for device API keys only.
+	Scopes  []string
+	StoreID string
+}
+
+// HasRole reports whether the identity holds any of roles. Admins hold every role.
+func (i Identity) HasRole(roles ...string) bool {
+	if slices.Contains(i.Roles, RoleAdmin) {
+		return true
+	}
+	for _, role := range roles {
+		if slices.Contains(i.Roles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying identity.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity carried by ctx, if any.
+func FromContext(ctx context.Context) (identity Identity, ok bool) {
+	identity, ok = ctx.Value(identityKey{}).(Identity)
+	return
+}
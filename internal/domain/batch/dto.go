@@ -0,0 +1,90 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// ModeAtomic applies every operation in one transaction or none of them.
+	ModeAtomic = "atomic"
+	// ModeBestEffort applies every operation on its own and keeps the ones that succeed.
+	ModeBestEffort = "best_effort"
+)
+
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+)
+
+const (
+	StatusOK         = "ok"
+	StatusFailed     = "failed"
+	StatusRolledBack = "rolled_back"
+	StatusSkipped    = "skipped"
+)
+
+// MaxOperations caps the size of a single batch.
+const MaxOperations = 1000
+
+// Operation is the part of a batch item shared by every entity type.
+type Operation struct {
+	Op string `json:"op"`
+	ID string `json:"id"`
+}
+
+// Validate checks the mode and the shape of every operation. hasData reports
+// whether the i-th operation carries a payload.
+func Validate(mode string, ops []Operation, hasData func(i int) bool) error {
+	if mode != ModeAtomic && mode != ModeBestEffort {
+		return errors.New("mode: must be atomic or best_effort")
+	}
+
+	if len(ops) == 0 {
+		return errors.New("operations: cannot be empty")
+	}
+
+	if len(ops) > MaxOperations {
+		return fmt.Errorf("operations: at most %d per batch", MaxOperations)
+	}
+
+	for i, op := range ops {
+		switch op.Op {
+		case OpCreate:
+			if !hasData(i) {
+				return fmt.Errorf("operations[%d].data: cannot be blank", i)
+			}
+		case OpUpdate:
+			if op.ID == "" {
+				return fmt.Errorf("operations[%d].id: cannot be blank", i)
+			}
+			if !hasData(i) {
+				return fmt.Errorf("operations[%d].data: cannot be blank", i)
+			}
+		case OpDelete:
+			if op.ID == "" {
+				return fmt.Errorf("operations[%d].id: cannot be blank", i)
+			}
+		default:
+			return fmt.Errorf("operations[%d].op: must be create, update or delete", i)
+		}
+	}
+
+	return nil
+}
+
+type Result struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type Response struct {
+	Mode      string   `json:"mode"`
+	Succeeded int      `json:"succeeded"`
+	Failed    int      `json:"failed"`
+	Results   []Result `json:"results"`
+}
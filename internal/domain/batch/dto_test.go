@@ -0,0 +1,40 @@
+package batch
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	create, update, remove := Operation{Op: OpCreate}, Operation{Op: OpUpdate, ID: "1"}, Operation{Op: OpDelete, ID: "1"}
+	withData := func(int) bool { return true }
+	withoutData := func(int) bool { return false }
+
+	tests := []struct {
+		name    string
+		mode    string
+		ops     []Operation
+		hasData func(int) bool
+		want    string
+	}{
+		{"atomic", ModeAtomic, []Operation{create, update, remove}, withData, ""},
+		{"best effort", ModeBestEffort, []Operation{remove}, withoutData, ""},
+		{"unknown mode", "all", []Operation{remove}, withData, "mode: must be atomic or best_effort"},
+		{"no operations", ModeAtomic, nil, withData, "operations: cannot be empty"},
+		{"too many operations", ModeAtomic, make([]Operation, MaxOperations+1), withData, "operations: at most 1000 per batch"},
+		{"create without data", ModeAtomic, []Operation{remove, create}, withoutData, "operations[1].data: cannot be blank"},
+		{"update without id", ModeAtomic, []Operation{{Op: OpUpdate}}, withData, "operations[0].id: cannot be blank"},
+		{"update without data", ModeAtomic, []Operation{update}, withoutData, "operations[0].data: cannot be blank"},
+		{"delete without id", ModeAtomic, []Operation{{Op: OpDelete}}, withData, "operations[0].id: cannot be blank"},
+		{"unknown op", ModeAtomic, []Operation{{Op: "upsert"}}, withData, "operations[0].op: must be create, update or delete"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.mode, tt.ops, tt.hasData)
+			if tt.want == "" && err != nil || tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)) {
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package brand
+
+import (
+	"net/http"
+	"strings"
+
+	"product/pkg/validate"
+)
+
+func init() {
+	validate.Register(&Request{})
+}
+
+type Request struct {
+	Name    string `json:"name" validate:"required"`
+	Country string `json:"country" validate:"country"`
+}
+
+// Bind reports every invalid field at once, as validate.Errors.
+func (s *Request) Bind(r *http.Request) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if err := validate.Struct(s).Err(); err != nil {
+		return err
+	}
+
+	s.Country = strings.ToUpper(strings.TrimSpace(s.Country))
+	return nil
+}
+
+type Response struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:   data.ID,
+		Name: *data.Name,
+	}
+	if data.Country != nil {
+		res.Country = *data.Country
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
@@ -0,0 +1,56 @@
+package brand
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"product/pkg/validate"
+)
+
+func codes(t *testing.T, err error) (res []string) {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want validate.Errors", err)
+	}
+	for _, fieldError := range errs {
+		res = append(res, fieldError.Field+"="+fieldError.Code)
+	}
+	return
+}
+
+func TestRequestBind(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want []string
+	}{
+		{"valid", Request{Name: "Acme", Country: "DE"}, nil},
+		{"no country", Request{Name: "Acme"}, nil},
+		{"blank name", Request{Name: "  ", Country: "DE"}, []string{"name=required"}},
+		{"unknown country", Request{Name: "Acme", Country: "Germany"}, []string{"country=invalid_country"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(t, tt.req.Bind(nil)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bind() errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequestBindNormalizes(t *testing.T) {
+	req := Request{Name: " Acme ", Country: " de "}
+	if err := req.Bind(nil); err != nil {
+		t.Fatal(err)
+	}
+	if req.Name != "Acme" || req.Country != "DE" {
+		t.Errorf("got name %q and country %q, want \"Acme\" and \"DE\"", req.Name, req.Country)
+	}
+}
@@ -0,0 +1,7 @@
+package brand
+
+type Entity struct {
+	ID      string  `db:"id"`
+	Name    *string `db:"name"`
+	Country *string `db:"country"`
+}
@@ -2,20 +2,37 @@ package category
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/batch"
+	"product/pkg/validate"
 )
 
+func init() {
+	validate.Register(&Request{})
+}
+
 type Request struct {
-	Name     string `json:"name"`
-	ParentId string `json:"parent_id"`
+	Name     string `json:"name" validate:"required,max=255"`
+	ParentId string `json:"parent_id" validate:"uuid"`
+	// ProductIDs are moved into the category in the same transaction it is
+	// saved in.
+	ProductIDs []string `json:"product_ids,omitempty"`
 }
 
+// Bind reports every invalid field at once, as validate.Errors. Whether the
+// parent and the products exist is checked when the category is saved.
 func (s *Request) Bind(r *http.Request) error {
-	if s.Name == "" {
-		return errors.New("name: cannot be blank")
+	errs := validate.Struct(s)
+	for i, id := range s.ProductIDs {
+		if _, err := uuid.Parse(id); err != nil || len(id) != 36 {
+			errs.Add(fmt.Sprintf("product_ids[%d]", i), validate.CodeUUID, "must be a UUID")
+		}
 	}
-
-	return nil
+	return errs.Err()
 }
 
 type Response struct {
@@ -41,3 +58,47 @@ func ParseFromEntities(data []Entity) (res []Response) {
 	}
 	return
 }
+
+type BatchRequest struct {
+	Mode       string           `json:"mode"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+type BatchOperation struct {
+	batch.Operation
+	Data *Request `json:"data"`
+}
+
+func (s *BatchRequest) Bind(r *http.Request) error {
+	if s.Mode == "" {
+		s.Mode = batch.ModeAtomic
+	}
+
+	ops := make([]batch.Operation, len(s.Operations))
+	for i, op := range s.Operations {
+		ops[i] = op.Operation
+	}
+
+	err := batch.Validate(s.Mode, ops, func(i int) bool {
+		return s.Operations[i].Data != nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs validate.Errors
+	for i, op := range s.Operations {
+		if op.Data == nil {
+			continue
+		}
+		if err = op.Data.Bind(r); err != nil {
+			var fieldErrs validate.Errors
+			if !errors.As(err, &fieldErrs) {
+				return err
+			}
+			errs = append(errs, fieldErrs.Prefix(fmt.Sprintf("operations[%d].data.", i))...)
+		}
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,57 @@
+package category
+
+import (
+	"strings"
+)
+
+// PathSeparator joins category names into a path such as "Drinks/Juice".
+const PathSeparator = "/"
+
+// Paths maps every category ID to the names of its ancestors and itself
+// joined with PathSeparator. Categories whose parent is unknown are roots.
+func Paths(data []Entity) map[string]string {
+	byID := make(map[string]Entity, len(data))
+	for _, object := range data {
+		byID[object.ID] = object
+	}
+
+	paths := make(map[string]string, len(data))
+	for _, object := range data {
+		var names []string
+		seen := make(map[string]bool)
+		for current, ok := object, true; ok && !seen[current.ID]; {
+			seen[current.ID] = true
+			if current.Name != nil {
+				names = append([]string{*current.Name}, names...)
+			}
+			if current.ParentId == nil {
+				break
+			}
+			current, ok = byID[*current.ParentId]
+		}
+		paths[object.ID] = strings.Join(names, PathSeparator)
+	}
+
+	return paths
+}
+
+// PathIndex maps lower-cased category paths back to category IDs.
+func PathIndex(data []Entity) map[string]string {
+	index := make(map[string]string, len(data))
+	for id, path := range Paths(data) {
+		index[NormalizePath(path)] = id
+	}
+	return index
+}
+
+// NormalizePath lower-cases a path and trims the spaces around each segment.
+func NormalizePath(path string) string {
+	segments := strings.Split(path, PathSeparator)
+	parts := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			parts = append(parts, strings.ToLower(segment))
+		}
+	}
+	return strings.Join(parts, PathSeparator)
+}
@@ -0,0 +1,59 @@
+package category
+
+import (
+	"reflect"
+	"testing"
+)
+
+func entity(id, parentID, name string) Entity {
+	return Entity{ID: id, ParentId: &parentID, Name: &name}
+}
+
+func TestPaths(t *testing.T) {
+	data := []Entity{
+		entity("1", "", "Drinks"),
+		entity("2", "1", "Juice"),
+		entity("3", "2", "Orange"),
+		entity("4", "missing", "Orphan"),
+		entity("5", "6", "Loop A"),
+		entity("6", "5", "Loop B"),
+	}
+
+	want := map[string]string{
+		"1": "Drinks",
+		"2": "Drinks/Juice",
+		"3": "Drinks/Juice/Orange",
+		"4": "Orphan",
+		"5": "Loop B/Loop A",
+		"6": "Loop A/Loop B",
+	}
+	if got := Paths(data); !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+}
+
+func TestPathIndex(t *testing.T) {
+	index := PathIndex([]Entity{entity("1", "", "Drinks"), entity("2", "1", "Fruit Juice")})
+
+	for _, path := range []string{"drinks/fruit juice", NormalizePath(" Drinks / Fruit Juice ")} {
+		if index[path] != "2" {
+			t.Errorf("index[%q] = %q, want \"2\"", path, index[path])
+		}
+	}
+}
+
+func TestNormalizePath(t *testing.T) {
+	tests := map[string]string{
+		"Drinks/Juice":       "drinks/juice",
+		" Drinks /  Juice ":  "drinks/juice",
+		"/Drinks//Juice/":    "drinks/juice",
+		"":                   "",
+		"Fruit Juice/Orange": "fruit juice/orange",
+	}
+
+	for path, want := range tests {
+		if got := NormalizePath(path); got != want {
+			t.Errorf("NormalizePath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
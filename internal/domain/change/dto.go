@@ -0,0 +1,81 @@
+package change
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const (
+	OperationUpsert = "upsert"
+	OperationDelete = "delete"
+
+	defaultLimit = 500
+	maxLimit     = 5000
+)
+
+// Token is a position in the change feed. Clients treat it as opaque and pass
+// the one returned with a page to get the changes after it.
+type Token struct {
+	TxID int64
+	ID   int64
+}
+
+func (t Token) String() string {
+	return fmt.Sprintf("%d.%d", t.TxID, t.ID)
+}
+
+// ParseToken reads a token; an empty one is the start of the feed.
+func ParseToken(value string) (token Token, err error) {
+	if value == "" {
+		return
+	}
+
+	if _, err = fmt.Sscanf(value, "%d.%d", &token.TxID, &token.ID); err != nil || token.String() != value {
+		return Token{}, errors.New("since: not a valid sync token")
+	}
+
+	return
+}
+
+type Request struct {
+	Since Token
+	Limit int
+}
+
+// ParseRequest reads the position to resume from the since query parameter
+// and the page size from limit.
+func ParseRequest(r *http.Request) (req Request, err error) {
+	query := r.URL.Query()
+
+	if req.Since, err = ParseToken(query.Get("since")); err != nil {
+		return
+	}
+
+	req.Limit = defaultLimit
+	if value := query.Get("limit"); value != "" {
+		if req.Limit, err = strconv.Atoi(value); err != nil || req.Limit <= 0 || req.Limit > maxLimit {
+			return req, errors.New("limit: must be between 1 and " + strconv.Itoa(maxLimit))
+		}
+	}
+
+	return
+}
+
+// Change is the latest state of an entity, or a tombstone when it was deleted.
+type Change struct {
+	Entity    string          `json:"entity"`
+	ID        string          `json:"id"`
+	Operation string          `json:"operation"`
+	Data      json.RawMessage `json:"data,omitempty" swaggertype:"object"`
+}
+
+// Response is a page of the feed. Every entity appears at most once per page
+// with its latest state; Next is the token to ask for the following page.
+type Response struct {
+	Changes []Change `json:"changes"`
+	Next    string   `json:"next"`
+	HasMore bool     `json:"has_more"`
+}
@@ -0,0 +1,64 @@
+package change
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseToken(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Token
+		ok    bool
+	}{
+		{"", Token{}, true},
+		{"12.345", Token{TxID: 12, ID: 345}, true},
+		{"0.0", Token{}, true},
+		{"12", Token{}, false},
+		{"12.345.6", Token{}, false},
+		{"012.345", Token{}, false},
+		{"a.b", Token{}, false},
+		{" 12.345", Token{}, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseToken(tt.value)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseToken(%q) = %v, %v, want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestTokenRoundTrip(t *testing.T) {
+	token := Token{TxID: 987654321, ID: 42}
+	if got, err := ParseToken(token.String()); err != nil || got != token {
+		t.Errorf("ParseToken(%q) = %v, %v, want %v", token.String(), got, err, token)
+	}
+}
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		query string
+		want  Request
+		err   string
+	}{
+		{"", Request{Limit: defaultLimit}, ""},
+		{"since=3.7&limit=50", Request{Since: Token{TxID: 3, ID: 7}, Limit: 50}, ""},
+		{"since=latest", Request{}, "since: not a valid sync token"},
+		{"limit=0", Request{}, "limit: must be between 1 and 5000"},
+		{"limit=5001", Request{}, "limit: must be between 1 and 5000"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRequest(httptest.NewRequest("GET", "/changes?"+tt.query, nil))
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("ParseRequest(%q) error = %v, want %q", tt.query, err, tt.err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRequest(%q) = %+v, %v, want %+v", tt.query, got, err, tt.want)
+		}
+	}
+}
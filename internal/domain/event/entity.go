@@ -0,0 +1,19 @@
+package event
+
+import (
+	"time"
+)
+
+// Entity is a domain event waiting in the outbox until it is published.
+type Entity struct {
+	ID            int64      `db:"id"`
+	CreatedAt     *time.Time `db:"created_at"`
+	Type          *string    `db:"event_type"`
+	AggregateType *string    `db:"aggregate_type"`
+	AggregateID   *string    `db:"aggregate_id"`
+	Payload       *string    `db:"payload"`
+	PublishedAt   *time.Time `db:"published_at"`
+	Attempts      *int       `db:"attempts"`
+	LastError     *string    `db:"last_error"`
+	TxID          *int64     `db:"txid"`
+}
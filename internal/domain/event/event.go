@@ -0,0 +1,93 @@
+package event
+
+import (
+	"encoding/json"
+	"time"
+
+	"product/internal/domain/audit"
+)
+
+// Event types are "<aggregate>.<what happened>". Every change emits one of
+// created, updated or deleted; some updates also emit a more specific event.
+const (
+	Created = "created"
+	Updated = "updated"
+	Deleted = "deleted"
+
+	ProductPriceChanged = "product.price_changed"
+	CategoryMoved       = "category.moved"
+)
+
+// specific maps a changed field of an aggregate to the event it emits.
+var specific = map[string]map[string]string{
+	audit.EntityProduct:  {"cost": ProductPriceChanged},
+	audit.EntityCategory: {"parent_id": CategoryMoved},
+}
+
+// Types returns the events emitted by a change of the given fields.
+func Types(aggregate, operation string, fields []string) (types []string) {
+	switch operation {
+	case audit.OperationCreate:
+		return []string{aggregate + "." + Created}
+	case audit.OperationDelete:
+		return []string{aggregate + "." + Deleted}
+	}
+
+	types = []string{aggregate + "." + Updated}
+	for _, field := range fields {
+		if eventType, ok := specific[aggregate][field]; ok {
+			types = append(types, eventType)
+		}
+	}
+
+	return
+}
+
+// Payload is the part of a message stored with the event in the outbox.
+type Payload struct {
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id,omitempty"`
+	Data      json.RawMessage `json:"data"`
+	Changes   json.RawMessage `json:"changes,omitempty"`
+}
+
+// Message is an event as delivered to subscribers. Delivery is at least once,
+// so consumers should skip IDs they have already seen.
+type Message struct {
+	ID            int64           `json:"id"`
+	Type          string          `json:"type"`
+	AggregateType string          `json:"aggregate_type"`
+	AggregateID   string          `json:"aggregate_id"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Actor         string          `json:"actor"`
+	RequestID     string          `json:"request_id,omitempty"`
+	Data          json.RawMessage `json:"data" swaggertype:"object"`
+	Changes       json.RawMessage `json:"changes,omitempty" swaggertype:"object"`
+}
+
+func ParseToMessage(data Entity) (res Message, err error) {
+	var payload Payload
+	if data.Payload != nil {
+		if err = json.Unmarshal([]byte(*data.Payload), &payload); err != nil {
+			return
+		}
+	}
+
+	res = Message{
+		ID:            data.ID,
+		Type:          *data.Type,
+		AggregateType: *data.AggregateType,
+		AggregateID:   *data.AggregateID,
+		Actor:         payload.Actor,
+		RequestID:     payload.RequestID,
+		Data:          payload.Data,
+		Changes:       payload.Changes,
+	}
+	if data.CreatedAt != nil {
+		res.OccurredAt = *data.CreatedAt
+	}
+	if res.Data == nil {
+		res.Data = json.RawMessage("null")
+	}
+	return
+}
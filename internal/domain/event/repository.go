@@ -0,0 +1,22 @@
+package event
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id int64, err error)
+	// SelectPending locks up to limit unpublished events in the order they were
+	// written. It must run inside a transaction, and returns none while the
+	// transaction of another relay holds the outbox.
+	SelectPending(ctx context.Context, limit int) (dest []Entity, err error)
+	MarkPublished(ctx context.Context, ids []int64) (err error)
+	MarkFailed(ctx context.Context, id int64, lastError string) (err error)
+	// SelectSince returns up to limit events of the given types written after
+	// the position (txid, id), in the order of the transactions that wrote
+	// them. Events of transactions that may still be running are left out.
+	SelectSince(ctx context.Context, txid, id int64, types []string, limit int) (dest []Entity, err error)
+	// Position returns the position of the last event SelectSince can return
+	// right now, or zeros when there is none.
+	Position(ctx context.Context) (txid, id int64, err error)
+}
@@ -0,0 +1,73 @@
+package fixture
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"product/internal/domain/product"
+)
+
+// Catalog is a tree of categories with their products, as loaded by the seed
+// command. Categories are matched by name under the same parent and products
+// by barcode, so loading the same fixture twice duplicates nothing.
+type Catalog struct {
+	Categories []Category `json:"categories"`
+}
+
+// Category is a fixture category. The category_id of its products is filled
+// in when it is saved.
+type Category struct {
+	Name     string            `json:"name"`
+	Children []Category        `json:"children"`
+	Products []product.Request `json:"products"`
+}
+
+// Result counts what loading a fixture changed.
+type Result struct {
+	CategoriesCreated int `json:"categories_created"`
+	ProductsUpserted  int `json:"products_upserted"`
+}
+
+// Read decodes a fixture and checks its shape. Unknown fields are rejected,
+// so a typo does not silently drop data.
+func Read(r io.Reader) (catalog Catalog, err error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&catalog); err != nil {
+		return
+	}
+
+	err = catalog.Validate()
+
+	return
+}
+
+// Validate checks that every category has a name and every product a
+// barcode to be matched by. The products themselves are validated once their
+// category is known.
+func (c Catalog) Validate() error {
+	return validateCategories(c.Categories, "categories")
+}
+
+func validateCategories(categories []Category, path string) error {
+	for i, category := range categories {
+		at := fmt.Sprintf("%s[%d]", path, i)
+		if strings.TrimSpace(category.Name) == "" {
+			return fmt.Errorf("%s.name: cannot be blank", at)
+		}
+
+		for j, req := range category.Products {
+			if strings.TrimSpace(req.Barcode) == "" {
+				return fmt.Errorf("%s.products[%d].barcode: cannot be blank", at, j)
+			}
+		}
+
+		if err := validateCategories(category.Children, at+".children"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package imports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"product/pkg/sheet"
+	"product/pkg/validate"
+)
+
+const (
+	defaultBatchSize = 500
+	maxBatchSize     = 5000
+	maxFileSize      = 64 << 20
+)
+
+// Request is a multipart/form-data upload with the fields
+// file, mapping (a JSON object of product field to column header),
+// dry_run and batch_size.
+type Request struct {
+	Filename  string  `json:"filename"`
+	Format    string  `json:"format"`
+	Mapping   Mapping `json:"mapping"`
+	DryRun    bool    `json:"dry_run"`
+	BatchSize int     `json:"batch_size"`
+	File      []byte  `json:"-"`
+}
+
+// Bind reports every invalid field at once, as validate.Errors.
+func (s *Request) Bind(r *http.Request) error {
+	var errs validate.Errors
+	if err := r.ParseMultipartForm(maxFileSize); err != nil {
+		errs.Add("file", validate.CodeRequired, "expected a multipart/form-data upload")
+		return errs.Err()
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		errs.Add("file", validate.CodeRequired, "cannot be blank")
+		return errs.Err()
+	}
+	defer file.Close()
+
+	if s.File, err = io.ReadAll(io.LimitReader(file, maxFileSize+1)); err != nil {
+		return err
+	}
+
+	s.Filename = header.Filename
+	s.Format = r.FormValue("format")
+
+	if value := r.FormValue("mapping"); value != "" {
+		if err = json.Unmarshal([]byte(value), &s.Mapping); err != nil {
+			errs.Add("mapping", validate.CodeInvalid, "must be a JSON object")
+		}
+	}
+
+	if value := r.FormValue("dry_run"); value != "" {
+		if s.DryRun, err = strconv.ParseBool(value); err != nil {
+			errs.Add("dry_run", validate.CodeInvalid, "must be a boolean")
+		}
+	}
+
+	if value := r.FormValue("batch_size"); value != "" {
+		if s.BatchSize, err = strconv.Atoi(value); err != nil {
+			errs.Add("batch_size", validate.CodeInvalid, "must be a number")
+		} else if s.BatchSize == 0 {
+			// An explicit zero is rejected; Validate takes it for the default.
+			errs.Add("batch_size", validate.CodeTooSmall, "must be at least 1")
+		}
+	}
+
+	return append(errs, s.check()...).Err()
+}
+
+// Validate checks a request and fills in the format from the file name and
+// the default batch size when they are missing. Bind calls it; requests built
+// elsewhere, e.g. by the command line, must call it themselves. It reports
+// every invalid field at once, as validate.Errors.
+func (s *Request) Validate() error {
+	return s.check().Err()
+}
+
+func (s *Request) check() (errs validate.Errors) {
+	if len(s.File) > maxFileSize {
+		errs.Add("file", validate.CodeTooLarge, fmt.Sprintf("must be at most %d bytes", maxFileSize))
+	}
+
+	if s.Format == "" {
+		s.Format = sheet.FormatFromFilename(s.Filename)
+	}
+	if s.Format != sheet.FormatCSV && s.Format != sheet.FormatXLSX {
+		errs.Add("format", validate.CodeChoice, "must be one of csv, xlsx")
+	}
+
+	errs = append(errs, s.Mapping.Validate()...)
+
+	if s.BatchSize == 0 {
+		s.BatchSize = defaultBatchSize
+	}
+	if s.BatchSize > maxBatchSize {
+		errs.Add("batch_size", validate.CodeTooLarge, "must be at most "+strconv.Itoa(maxBatchSize))
+	} else if s.BatchSize < 0 {
+		errs.Add("batch_size", validate.CodeTooSmall, "must be at least 1")
+	}
+
+	return
+}
+
+type Response struct {
+	ID            string     `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	JobID         string     `json:"job_id,omitempty"`
+	Status        string     `json:"status"`
+	Filename      string     `json:"filename"`
+	Format        string     `json:"format"`
+	DryRun        bool       `json:"dry_run"`
+	TotalRows     int        `json:"total_rows"`
+	ProcessedRows int        `json:"processed_rows"`
+	UpsertedRows  int        `json:"upserted_rows"`
+	FailedRows    int        `json:"failed_rows"`
+	Error         string     `json:"error,omitempty"`
+	Errors        []RowError `json:"errors"`
+}
+
+func ParseFromEntity(data Entity, errs []RowError) (res Response) {
+	res = Response{
+		ID:            data.ID,
+		Status:        *data.Status,
+		Filename:      *data.Filename,
+		Format:        *data.Format,
+		DryRun:        *data.DryRun,
+		TotalRows:     *data.TotalRows,
+		ProcessedRows: *data.ProcessedRows,
+		UpsertedRows:  *data.UpsertedRows,
+		FailedRows:    *data.FailedRows,
+		Errors:        errs,
+	}
+	if data.CreatedAt != nil {
+		res.CreatedAt = *data.CreatedAt
+	}
+	if data.JobID != nil {
+		res.JobID = *data.JobID
+	}
+	if data.Error != nil {
+		res.Error = *data.Error
+	}
+	if res.Errors == nil {
+		res.Errors = make([]RowError, 0)
+	}
+	return
+}
@@ -0,0 +1,103 @@
+package imports
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"product/pkg/validate"
+)
+
+// bind binds a multipart upload of a small CSV file, when file is set, and
+// the form values.
+func bind(t *testing.T, file bool, values map[string]string) (*Request, error) {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if file {
+		part, err := form.CreateFormFile("file", "products.csv")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("barcode,name,category\n"))
+	}
+	for name, value := range values {
+		form.WriteField(name, value)
+	}
+	form.Close()
+
+	r := httptest.NewRequest("POST", "/imports", &body)
+	r.Header.Set("Content-Type", form.FormDataContentType())
+
+	req := &Request{}
+	return req, req.Bind(r)
+}
+
+// fieldErrors lists the field and code of every validation error in err.
+func fieldErrors(t *testing.T, err error) (res []string) {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want validate.Errors", err)
+	}
+	for _, fieldError := range errs {
+		res = append(res, fieldError.Field+"="+fieldError.Code)
+	}
+	return
+}
+
+func TestBind(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   bool
+		values map[string]string
+		want   []string
+	}{
+		{"valid", true, map[string]string{"mapping": `{"name":"Title"}`, "dry_run": "true", "batch_size": "100"}, nil},
+		{"no file", false, nil, []string{"file=required"}},
+		{"malformed fields", true, map[string]string{"mapping": "[", "dry_run": "maybe", "batch_size": "many"}, []string{"mapping=invalid", "dry_run=invalid", "batch_size=invalid"}},
+		{"explicit zero batch", true, map[string]string{"batch_size": "0"}, []string{"batch_size=too_small"}},
+		{"batch too large", true, map[string]string{"batch_size": "5001"}, []string{"batch_size=too_large"}},
+		{"unknown format", true, map[string]string{"format": "ods"}, []string{"format=invalid_choice"}},
+		{
+			"invalid mapping", true, map[string]string{"mapping": `{"title":"Title","name":" ","barcode":"EAN"}`},
+			[]string{"mapping.name=required", "mapping.title=invalid_choice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := bind(t, tt.file, tt.values)
+			if got := fieldErrors(t, err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bind() errors = %v, want %v", got, tt.want)
+			}
+			if err == nil && (req.Format != "csv" || req.BatchSize != 100 || !req.DryRun || req.Mapping["name"] != "Title") {
+				t.Errorf("Bind() request = %+v", req)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	req := &Request{Filename: "products.xlsx"}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if req.Format != "xlsx" || req.BatchSize != defaultBatchSize {
+		t.Errorf("Validate() filled in format %q and batch size %d, want xlsx and %d", req.Format, req.BatchSize, defaultBatchSize)
+	}
+
+	req = &Request{Filename: "products.txt", BatchSize: -1, File: make([]byte, maxFileSize+1)}
+	want := []string{"file=too_large", "format=invalid_choice", "batch_size=too_small"}
+	if got := fieldErrors(t, req.Validate()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate() errors = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,39 @@
+package imports
+
+import (
+	"time"
+)
+
+const (
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusValidated = "validated"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+)
+
+type Entity struct {
+	ID            string     `db:"id"`
+	CreatedAt     *time.Time `db:"created_at"`
+	JobID         *string    `db:"job_id"`
+	Status        *string    `db:"status"`
+	Filename      *string    `db:"filename"`
+	Format        *string    `db:"format"`
+	Mapping       *string    `db:"mapping"`
+	DryRun        *bool      `db:"dry_run"`
+	BatchSize     *int       `db:"batch_size"`
+	TotalRows     *int       `db:"total_rows"`
+	ProcessedRows *int       `db:"processed_rows"`
+	UpsertedRows  *int       `db:"upserted_rows"`
+	FailedRows    *int       `db:"failed_rows"`
+	Error         *string    `db:"error"`
+	File          []byte     `db:"file"`
+}
+
+// RowError is a problem found in a single row of an imported file.
+// Row numbers are 1-based and count the header line.
+type RowError struct {
+	Row     int    `db:"row" json:"row"`
+	Field   string `db:"field" json:"field"`
+	Message string `db:"message" json:"message"`
+}
@@ -0,0 +1,13 @@
+package imports
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (dest Entity, err error)
+	Update(ctx context.Context, id string, data Entity) (err error)
+	AddErrors(ctx context.Context, id string, errs []RowError) (err error)
+	SelectErrors(ctx context.Context, id string) (dest []RowError, err error)
+}
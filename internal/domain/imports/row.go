@@ -0,0 +1,208 @@
+package imports
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"product/internal/domain/category"
+	"product/internal/domain/product"
+	"product/pkg/country"
+	"product/pkg/validate"
+)
+
+// Product fields that a column can be mapped to.
+const (
+	FieldBarcode         = "barcode"
+	FieldName            = "name"
+	FieldCategory        = "category"
+	FieldMeasure         = "measure"
+	FieldCost            = "cost"
+	FieldProducerCountry = "producer_country"
+	FieldBrand           = "brand"
+	FieldSupplier        = "supplier"
+	FieldDescription     = "description"
+	FieldImage           = "image"
+	FieldIsWeighted      = "is_weighted"
+)
+
+var fields = []string{
+	FieldBarcode, FieldName, FieldCategory, FieldMeasure, FieldCost, FieldProducerCountry,
+	FieldBrand, FieldSupplier, FieldDescription, FieldImage, FieldIsWeighted,
+}
+
+var requiredFields = []string{FieldBarcode, FieldName, FieldCategory}
+
+// Mapping maps product fields to column headers of the imported file.
+// Fields that are not mapped are looked up by their own name.
+type Mapping map[string]string
+
+// Validate lists the unknown fields and blank columns of the mapping, in the
+// order of the fields.
+func (m Mapping) Validate() (errs validate.Errors) {
+	for _, field := range slices.Sorted(maps.Keys(m)) {
+		if !slices.Contains(fields, field) {
+			errs.Add("mapping."+field, validate.CodeChoice, "is not a product field")
+			continue
+		}
+		if strings.TrimSpace(m[field]) == "" {
+			errs.Add("mapping."+field, validate.CodeRequired, "cannot be blank")
+		}
+	}
+
+	return
+}
+
+func (m Mapping) column(field string) string {
+	if column, ok := m[field]; ok {
+		return column
+	}
+	return field
+}
+
+// Columns finds the position of every mapped field in the header row.
+func (m Mapping) Columns(header []string) (columns map[string]int, errs []RowError) {
+	positions := make(map[string]int, len(header))
+	for i, name := range header {
+		positions[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns = make(map[string]int, len(fields))
+	for _, field := range fields {
+		if i, ok := positions[strings.ToLower(m.column(field))]; ok {
+			columns[field] = i
+		}
+	}
+
+	for _, field := range requiredFields {
+		if _, ok := columns[field]; !ok {
+			errs = append(errs, RowError{Row: 1, Field: field, Message: "column " + m.column(field) + " not found"})
+		}
+	}
+
+	return
+}
+
+// Resolver turns the raw values of a row into a product entity,
+// resolving categories by name path and brands and suppliers by name.
+type Resolver struct {
+	Columns    map[string]int
+	Categories map[string]string
+	Brands     map[string]string
+	Suppliers  map[string]string
+}
+
+func NewResolver(columns map[string]int, categories []category.Entity, brands, suppliers map[string]string) Resolver {
+	return Resolver{
+		Columns:    columns,
+		Categories: category.PathIndex(categories),
+		Brands:     brands,
+		Suppliers:  suppliers,
+	}
+}
+
+// Product validates a row and returns the product it describes.
+// The ID of the entity is left blank.
+func (s Resolver) Product(number int, row []string) (data product.Entity, errs []RowError) {
+	value := func(field string) (string, bool) {
+		i, ok := s.Columns[field]
+		if !ok || i >= len(row) {
+			return "", false
+		}
+		return strings.TrimSpace(row[i]), true
+	}
+	fail := func(field, message string) {
+		errs = append(errs, RowError{Row: number, Field: field, Message: message})
+	}
+
+	empty := ""
+	data = product.Entity{
+		Measure:         &empty,
+		ProducerCountry: &empty,
+		BrandID:         &empty,
+		SupplierID:      &empty,
+		Description:     &empty,
+		Image:           &empty,
+	}
+
+	for _, field := range requiredFields {
+		if v, _ := value(field); v == "" {
+			fail(field, "cannot be blank")
+		}
+	}
+
+	if v, _ := value(FieldBarcode); v != "" {
+		data.Barcode = &v
+	}
+
+	if v, _ := value(FieldName); v != "" {
+		data.Name = &v
+	}
+
+	if v, _ := value(FieldCategory); v != "" {
+		if id, ok := s.Categories[category.NormalizePath(v)]; ok {
+			data.CategoryID = &id
+		} else {
+			fail(FieldCategory, "unknown category "+v)
+		}
+	}
+
+	if v, ok := value(FieldMeasure); ok {
+		data.Measure = &v
+	}
+
+	cost := 0
+	if v, _ := value(FieldCost); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			fail(FieldCost, "must be a non-negative integer")
+		}
+		cost = parsed
+	}
+	data.Cost = &cost
+
+	if v, _ := value(FieldProducerCountry); v != "" {
+		if c, ok := country.Resolve(v); ok {
+			data.ProducerCountry = &c.Alpha2
+		} else {
+			fail(FieldProducerCountry, "unknown country "+v)
+		}
+	}
+
+	if v, _ := value(FieldBrand); v != "" {
+		if id, ok := s.Brands[strings.ToLower(v)]; ok {
+			data.BrandID = &id
+		} else {
+			fail(FieldBrand, "unknown brand "+v)
+		}
+	}
+
+	if v, _ := value(FieldSupplier); v != "" {
+		if id, ok := s.Suppliers[strings.ToLower(v)]; ok {
+			data.SupplierID = &id
+		} else {
+			fail(FieldSupplier, "unknown supplier "+v)
+		}
+	}
+
+	if v, ok := value(FieldDescription); ok {
+		data.Description = &v
+	}
+
+	if v, ok := value(FieldImage); ok {
+		data.Image = &v
+	}
+
+	isWeighted := false
+	if v, _ := value(FieldIsWeighted); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			fail(FieldIsWeighted, "must be a boolean")
+		}
+		isWeighted = parsed
+	}
+	data.IsWeighted = &isWeighted
+
+	return
+}
@@ -0,0 +1,73 @@
+package imports
+
+import (
+	"reflect"
+	"testing"
+
+	"product/internal/domain/category"
+)
+
+func TestColumns(t *testing.T) {
+	mapping := Mapping{FieldBarcode: "EAN", FieldCost: "Price"}
+
+	columns, errs := mapping.Columns([]string{" ean ", "Name", "price", "unused"})
+	if want := map[string]int{FieldBarcode: 0, FieldName: 1, FieldCost: 2}; !reflect.DeepEqual(columns, want) {
+		t.Errorf("got columns %v, want %v", columns, want)
+	}
+	if want := []RowError{{Row: 1, Field: FieldCategory, Message: "column category not found"}}; !reflect.DeepEqual(errs, want) {
+		t.Errorf("got errors %v, want %v", errs, want)
+	}
+}
+
+func TestResolverProduct(t *testing.T) {
+	drinks, juice := "Drinks", "Juice"
+	root := ""
+	categories := []category.Entity{
+		{ID: "c1", ParentId: &root, Name: &drinks},
+		{ID: "c2", ParentId: &drinks, Name: &juice},
+	}
+	categories[1].ParentId = &categories[0].ID
+
+	header := []string{"barcode", "name", "category", "cost", "producer_country", "brand", "supplier", "is_weighted"}
+	columns, _ := Mapping{}.Columns(header)
+	resolver := NewResolver(columns, categories, map[string]string{"acme": "b1"}, map[string]string{"wholesale": "s1"})
+
+	t.Run("valid", func(t *testing.T) {
+		data, errs := resolver.Product(2, []string{"123", " Orange juice ", "drinks / juice", "250", "Germany", "ACME", "Wholesale", "true"})
+		if errs != nil {
+			t.Fatalf("got errors %v", errs)
+		}
+		got := []string{*data.Barcode, *data.Name, *data.CategoryID, *data.ProducerCountry, *data.BrandID, *data.SupplierID}
+		if want := []string{"123", "Orange juice", "c2", "DE", "b1", "s1"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if *data.Cost != 250 || !*data.IsWeighted {
+			t.Errorf("got cost %d and is_weighted %t, want 250 and true", *data.Cost, *data.IsWeighted)
+		}
+	})
+
+	t.Run("short row", func(t *testing.T) {
+		data, errs := resolver.Product(3, []string{"123", "Apple", "Drinks"})
+		if errs != nil {
+			t.Fatalf("got errors %v", errs)
+		}
+		if *data.Cost != 0 || *data.ProducerCountry != "" || *data.IsWeighted {
+			t.Errorf("got cost %d, country %q and is_weighted %t, want the defaults", *data.Cost, *data.ProducerCountry, *data.IsWeighted)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, errs := resolver.Product(4, []string{"", "", "Food", "-1", "Atlantis", "Other", "Nobody", "maybe"})
+		var got []string
+		for _, err := range errs {
+			if err.Row != 4 {
+				t.Errorf("got row %d, want 4", err.Row)
+			}
+			got = append(got, err.Field)
+		}
+		want := []string{FieldBarcode, FieldName, FieldCategory, FieldCost, FieldProducerCountry, FieldBrand, FieldSupplier, FieldIsWeighted}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got errors for %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package job
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Response struct {
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Status          string          `json:"status"`
+	Progress        int             `json:"progress"`
+	Total           int             `json:"total"`
+	Attempts        int             `json:"attempts"`
+	MaxAttempts     int             `json:"max_attempts"`
+	LastError       string          `json:"last_error,omitempty"`
+	CancelRequested bool            `json:"cancel_requested"`
+	Result          json.RawMessage `json:"result,omitempty" swaggertype:"object"`
+	CreatedAt       *time.Time      `json:"created_at"`
+	StartedAt       *time.Time      `json:"started_at,omitempty"`
+	FinishedAt      *time.Time      `json:"finished_at,omitempty"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:              data.ID,
+		Type:            *data.Type,
+		Status:          *data.Status,
+		Progress:        *data.Progress,
+		Total:           *data.Total,
+		Attempts:        *data.Attempts,
+		MaxAttempts:     *data.MaxAttempts,
+		CancelRequested: *data.CancelRequested,
+		CreatedAt:       data.CreatedAt,
+		StartedAt:       data.StartedAt,
+		FinishedAt:      data.FinishedAt,
+	}
+	if data.LastError != nil {
+		res.LastError = *data.LastError
+	}
+	if data.Result != nil {
+		res.Result = json.RawMessage(*data.Result)
+	}
+	return
+}
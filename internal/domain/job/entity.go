@@ -0,0 +1,42 @@
+package job
+
+import (
+	"time"
+)
+
+const (
+	TypeImport      = "import"
+	TypeExport      = "export"
+	TypePriceUpdate = "price_update"
+	TypeSnapshot    = "snapshot"
+)
+
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+type Entity struct {
+	ID              string     `db:"id"`
+	CreatedAt       *time.Time `db:"created_at"`
+	Type            *string    `db:"type"`
+	Status          *string    `db:"status"`
+	Payload         *string    `db:"payload"`
+	Checkpoint      *string    `db:"checkpoint"`
+	Result          *string    `db:"result"`
+	Progress        *int       `db:"progress"`
+	Total           *int       `db:"total"`
+	Attempts        *int       `db:"attempts"`
+	MaxAttempts     *int       `db:"max_attempts"`
+	RunAt           *time.Time `db:"run_at"`
+	LastError       *string    `db:"last_error"`
+	CancelRequested *bool      `db:"cancel_requested"`
+	LockedBy        *string    `db:"locked_by"`
+	Actor           *string    `db:"actor"`
+	RequestID       *string    `db:"request_id"`
+	StartedAt       *time.Time `db:"started_at"`
+	FinishedAt      *time.Time `db:"finished_at"`
+}
@@ -0,0 +1,45 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"product/pkg/apperror"
+)
+
+// ErrLockLost is returned by the updates of a running job once its worker no
+// longer holds it, e.g. because the job was requeued as stale and claimed by
+// another worker.
+var ErrLockLost = apperror.New(apperror.Conflict, "job: the lock was lost to another worker")
+
+type Repository interface {
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (dest Entity, err error)
+
+	// Claim locks the oldest due job of one of the given types for worker.
+	// It returns store.ErrorNotFound when the queue is empty.
+	Claim(ctx context.Context, worker string, types []string) (dest Entity, err error)
+
+	// The updates of a running job below only apply while worker holds its
+	// lock and return ErrLockLost otherwise.
+
+	// Heartbeat refreshes the lock of a running job and reports whether a cancellation was requested.
+	Heartbeat(ctx context.Context, id, worker string) (cancelRequested bool, err error)
+	Progress(ctx context.Context, id, worker string, progress, total int, checkpoint *string) (err error)
+	Finish(ctx context.Context, id, worker, status string, result, lastError *string) (err error)
+	// Retry puts a failed job back in the queue to run again after delay.
+	Retry(ctx context.Context, id, worker string, delay time.Duration, lastError string) (err error)
+	// Release puts a running job back in the queue without using up an attempt.
+	Release(ctx context.Context, id, worker string) (err error)
+	// SaveFile stores content as the file of a running job, replacing the one
+	// of an earlier attempt.
+	SaveFile(ctx context.Context, id, worker string, content []byte) (err error)
+
+	// File returns the file saved by a job. It returns store.ErrorNotFound
+	// when the job saved none.
+	File(ctx context.Context, id string) (content []byte, err error)
+
+	Cancel(ctx context.Context, id string) (err error)
+	// RequeueStale releases running jobs whose lock was not refreshed for longer than timeout.
+	RequeueStale(ctx context.Context, timeout time.Duration) (count int64, err error)
+}
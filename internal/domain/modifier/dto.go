@@ -0,0 +1,232 @@
+package modifier
+
+import (
+	"fmt"
+	"net/http"
+
+	"product/pkg/validate"
+)
+
+func init() {
+	validate.Register(&Request{}, &OptionRequest{})
+}
+
+type Request struct {
+	Name      string          `json:"name" validate:"required"`
+	MinSelect int             `json:"min_select" validate:"min=0"`
+	MaxSelect int             `json:"max_select"`
+	Options   []OptionRequest `json:"options"`
+}
+
+// OptionRequest describes an option of a group. On update, an option keeps
+// its ID when the request names it by id or, failing that, by its name.
+type OptionRequest struct {
+	ID         string `json:"id" validate:"uuid"`
+	Name       string `json:"name" validate:"required"`
+	PriceDelta int    `json:"price_delta"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+// Bind reports every invalid field at once, as validate.Errors.
+func (s *Request) Bind(r *http.Request) error {
+	errs := validate.Struct(s)
+
+	if s.MaxSelect < s.MinSelect {
+		errs.Add("max_select", validate.CodeTooSmall, "cannot be less than min_select")
+	}
+
+	if len(s.Options) == 0 {
+		errs.Add("options", validate.CodeRequired, "cannot be empty")
+	} else if len(s.Options) < s.MinSelect {
+		errs.Add("min_select", validate.CodeTooLarge, "cannot exceed the number of options")
+	}
+
+	defaults := 0
+	for i, option := range s.Options {
+		errs = append(errs, validate.Struct(&option).Prefix(fmt.Sprintf("options[%d].", i))...)
+		if option.IsDefault {
+			defaults++
+		}
+	}
+
+	if defaults > s.MaxSelect {
+		errs.Add("options", validate.CodeTooLarge, "more defaults than max_select allows")
+	}
+
+	return errs.Err()
+}
+
+type Response struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	MinSelect int              `json:"min_select"`
+	MaxSelect int              `json:"max_select"`
+	Options   []OptionResponse `json:"options"`
+}
+
+type OptionResponse struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:        data.ID,
+		Name:      *data.Name,
+		MinSelect: *data.MinSelect,
+		MaxSelect: *data.MaxSelect,
+		Options:   make([]OptionResponse, 0),
+	}
+	for _, option := range data.Options {
+		res.Options = append(res.Options, OptionResponse{
+			ID:         option.ID,
+			Name:       *option.Name,
+			PriceDelta: *option.PriceDelta,
+			IsDefault:  *option.IsDefault,
+		})
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
+
+type AttachRequest struct {
+	GroupIDs []string `json:"group_ids"`
+}
+
+func (s *AttachRequest) Bind(r *http.Request) error {
+	var errs validate.Errors
+
+	seen := make(map[string]bool, len(s.GroupIDs))
+	for i, id := range s.GroupIDs {
+		field := fmt.Sprintf("group_ids[%d]", i)
+		switch {
+		case id == "":
+			errs.Add(field, validate.CodeRequired, "cannot be blank")
+		case seen[id]:
+			errs.Add(field, validate.CodeDuplicate, "duplicate group "+id)
+		}
+		seen[id] = true
+	}
+
+	return errs.Err()
+}
+
+type ConfigurationRequest struct {
+	Selections []Selection `json:"selections"`
+}
+
+type Selection struct {
+	GroupID   string   `json:"group_id"`
+	OptionIDs []string `json:"option_ids"`
+}
+
+func (s *ConfigurationRequest) Bind(r *http.Request) error {
+	var errs validate.Errors
+
+	for i, selection := range s.Selections {
+		if selection.GroupID == "" {
+			errs.Add(fmt.Sprintf("selections[%d].group_id", i), validate.CodeRequired, "cannot be blank")
+		}
+	}
+
+	return errs.Err()
+}
+
+type ConfigurationResponse struct {
+	ProductID string           `json:"product_id"`
+	BasePrice int              `json:"base_price"`
+	Price     int              `json:"price"`
+	Options   []SelectedOption `json:"options"`
+}
+
+type SelectedOption struct {
+	GroupID    string `json:"group_id"`
+	OptionID   string `json:"option_id"`
+	Name       string `json:"name"`
+	PriceDelta int    `json:"price_delta"`
+}
+
+// Configure checks the selections against the modifier groups attached to a product.
+// Groups without an explicit selection fall back to their default options.
+// It returns the chosen options or a validation error listing every violated rule.
+func Configure(groups []Entity, req ConfigurationRequest) (selected []SelectedOption, err error) {
+	var errs validate.Errors
+
+	selections := make(map[string][]string, len(req.Selections))
+	for i, selection := range req.Selections {
+		if _, ok := selections[selection.GroupID]; ok {
+			errs.Add(fmt.Sprintf("selections[%d].group_id", i), validate.CodeDuplicate, "group selected more than once")
+			continue
+		}
+		selections[selection.GroupID] = selection.OptionIDs
+	}
+
+	known := make(map[string]bool, len(groups))
+	selected = make([]SelectedOption, 0)
+	for _, group := range groups {
+		known[group.ID] = true
+		field := "groups." + group.ID
+
+		optionIDs, ok := selections[group.ID]
+		if !ok {
+			for _, option := range group.Options {
+				if *option.IsDefault {
+					optionIDs = append(optionIDs, option.ID)
+				}
+			}
+		}
+
+		options := make(map[string]Option, len(group.Options))
+		for _, option := range group.Options {
+			options[option.ID] = option
+		}
+
+		chosen := make(map[string]bool, len(optionIDs))
+		for _, optionID := range optionIDs {
+			option, ok := options[optionID]
+			if !ok {
+				errs.Add(field, validate.CodeChoice, "unknown option "+optionID)
+				continue
+			}
+			if chosen[optionID] {
+				errs.Add(field, validate.CodeDuplicate, "option "+optionID+" selected more than once")
+				continue
+			}
+			chosen[optionID] = true
+			selected = append(selected, SelectedOption{
+				GroupID:    group.ID,
+				OptionID:   option.ID,
+				Name:       *option.Name,
+				PriceDelta: *option.PriceDelta,
+			})
+		}
+
+		if len(chosen) < *group.MinSelect {
+			errs.Add(field, validate.CodeTooSmall, fmt.Sprintf("%s: choose at least %d", *group.Name, *group.MinSelect))
+		}
+		if len(chosen) > *group.MaxSelect {
+			errs.Add(field, validate.CodeTooLarge, fmt.Sprintf("%s: choose at most %d", *group.Name, *group.MaxSelect))
+		}
+	}
+
+	for i, selection := range req.Selections {
+		if !known[selection.GroupID] {
+			errs.Add(fmt.Sprintf("selections[%d].group_id", i), validate.CodeNotFound, "group is not attached to the product")
+		}
+	}
+
+	if err = errs.Err(); err != nil {
+		selected = nil
+	}
+
+	return
+}
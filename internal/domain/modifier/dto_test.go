@@ -0,0 +1,180 @@
+package modifier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"product/pkg/validate"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func option(id string, priceDelta int, isDefault bool) Option {
+	return Option{ID: id, Name: ptr("option " + id), PriceDelta: &priceDelta, IsDefault: &isDefault}
+}
+
+// groups are a size to pick exactly one of, defaulting to medium, and up to
+// two optional toppings.
+var groups = []Entity{
+	{
+		ID:        "size",
+		Name:      ptr("Size"),
+		MinSelect: ptr(1),
+		MaxSelect: ptr(1),
+		Options:   []Option{option("small", -20, false), option("medium", 0, true), option("large", 30, false)},
+	},
+	{
+		ID:        "toppings",
+		Name:      ptr("Toppings"),
+		MinSelect: ptr(0),
+		MaxSelect: ptr(2),
+		Options:   []Option{option("syrup", 15, false), option("cream", 25, false), option("cinnamon", 5, false)},
+	},
+}
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name       string
+		selections []Selection
+		// want lists the chosen options, or the field and code of every error.
+		want []string
+		// price is the sum of the price deltas of the chosen options.
+		price int
+	}{
+		{
+			name:  "defaults",
+			want:  []string{"size/medium"},
+			price: 0,
+		},
+		{
+			name:       "explicit choices",
+			selections: []Selection{{GroupID: "size", OptionIDs: []string{"small"}}, {GroupID: "toppings", OptionIDs: []string{"syrup", "cream"}}},
+			want:       []string{"size/small", "toppings/syrup", "toppings/cream"},
+			price:      20,
+		},
+		{
+			name:       "no option of an optional group",
+			selections: []Selection{{GroupID: "toppings", OptionIDs: []string{}}},
+			want:       []string{"size/medium"},
+		},
+		{
+			name:       "below the minimum",
+			selections: []Selection{{GroupID: "size", OptionIDs: nil}},
+			want:       []string{"groups.size=too_small"},
+		},
+		{
+			name:       "above the maximum",
+			selections: []Selection{{GroupID: "toppings", OptionIDs: []string{"syrup", "cream", "cinnamon"}}},
+			want:       []string{"groups.toppings=too_large"},
+		},
+		{
+			name:       "unknown option",
+			selections: []Selection{{GroupID: "size", OptionIDs: []string{"huge"}}},
+			want:       []string{"groups.size=invalid_choice", "groups.size=too_small"},
+		},
+		{
+			name:       "option of another group",
+			selections: []Selection{{GroupID: "toppings", OptionIDs: []string{"large"}}},
+			want:       []string{"groups.toppings=invalid_choice"},
+		},
+		{
+			name:       "duplicate option",
+			selections: []Selection{{GroupID: "toppings", OptionIDs: []string{"syrup", "syrup"}}},
+			want:       []string{"groups.toppings=duplicate"},
+		},
+		{
+			name:       "duplicate group",
+			selections: []Selection{{GroupID: "size", OptionIDs: []string{"large"}}, {GroupID: "size", OptionIDs: []string{"small"}}},
+			want:       []string{"selections[1].group_id=duplicate"},
+		},
+		{
+			name:       "group not attached",
+			selections: []Selection{{GroupID: "milk", OptionIDs: []string{"oat"}}},
+			want:       []string{"selections[0].group_id=not_found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selected, err := Configure(groups, ConfigurationRequest{Selections: tt.selections})
+
+			var got []string
+			if err != nil {
+				var errs validate.Errors
+				if !errors.As(err, &errs) {
+					t.Fatalf("Configure() error = %v, want validate.Errors", err)
+				}
+				for _, fieldError := range errs {
+					got = append(got, fieldError.Field+"="+fieldError.Code)
+				}
+				if selected != nil {
+					t.Errorf("Configure() selected %+v along with an error", selected)
+				}
+			}
+
+			price := 0
+			for _, option := range selected {
+				got = append(got, option.GroupID+"/"+option.OptionID)
+				price += option.PriceDelta
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Configure() = %v, want %v", got, tt.want)
+			}
+			if price != tt.price {
+				t.Errorf("Configure() price deltas add up to %d, want %d", price, tt.price)
+			}
+		})
+	}
+}
+
+func TestConfigureWithoutGroups(t *testing.T) {
+	selected, err := Configure(nil, ConfigurationRequest{})
+	if err != nil || selected == nil || len(selected) != 0 {
+		t.Errorf("Configure() = %v, %v, want no options and no error", selected, err)
+	}
+}
+
+func TestRequestBind(t *testing.T) {
+	options := func(defaults ...bool) (res []OptionRequest) {
+		for _, isDefault := range defaults {
+			res = append(res, OptionRequest{Name: "option", IsDefault: isDefault})
+		}
+		return
+	}
+
+	tests := []struct {
+		name string
+		req  Request
+		want []string
+	}{
+		{"valid", Request{Name: "Size", MinSelect: 1, MaxSelect: 1, Options: options(true, false)}, nil},
+		{"negative minimum", Request{Name: "Size", MinSelect: -1, MaxSelect: 1, Options: options(false)}, []string{"min_select=too_small"}},
+		{"maximum below minimum", Request{Name: "Size", MinSelect: 2, MaxSelect: 1, Options: options(false, false)}, []string{"max_select=too_small"}},
+		{"minimum above the options", Request{Name: "Size", MinSelect: 2, MaxSelect: 2, Options: options(false)}, []string{"min_select=too_large"}},
+		{"no options", Request{Name: "Size", MaxSelect: 1}, []string{"options=required"}},
+		{"too many defaults", Request{Name: "Size", MaxSelect: 1, Options: options(true, true)}, []string{"options=too_large"}},
+		{"blank names", Request{MaxSelect: 1, Options: []OptionRequest{{ID: "x"}}}, []string{"name=required", "options[0].id=invalid_uuid", "options[0].name=required"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			if err := tt.req.Bind(nil); err != nil {
+				var errs validate.Errors
+				if !errors.As(err, &errs) {
+					t.Fatalf("Bind() error = %v, want validate.Errors", err)
+				}
+				for _, fieldError := range errs {
+					got = append(got, fieldError.Field+"="+fieldError.Code)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bind() errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package modifier
+
+type Entity struct {
+	ID        string   `db:"id"`
+	Name      *string  `db:"name"`
+	MinSelect *int     `db:"min_select"`
+	MaxSelect *int     `db:"max_select"`
+	Options   []Option `db:"-"`
+}
+
+type Option struct {
+	ID         string  `db:"id"`
+	GroupID    *string `db:"group_id"`
+	Name       *string `db:"name"`
+	PriceDelta *int    `db:"price_delta"`
+	IsDefault  *bool   `db:"is_default"`
+}
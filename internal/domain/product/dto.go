@@ -2,31 +2,41 @@ package product
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+
+	"product/internal/domain/batch"
+	"product/pkg/validate"
 )
 
+func init() {
+	validate.Register(&Request{})
+}
+
 type Request struct {
-	ID              string `json:"id"`
-	CategoryID      string `json:"category_id"`
-	Barcode         string `json:"barcode"`
-	Name            string `json:"name"`
-	Measure         string `json:"measure"`
-	Cost            int    `json:"cost"`
-	ProducerCountry string `json:"producer_country"`
-	BrandName       string `json:"brand_name"`
-	Description     string `json:"description"`
-	Image           string `json:"image"`
+	ID              string `json:"id" validate:"uuid"`
+	CategoryID      string `json:"category_id" validate:"required,uuid"`
+	Barcode         string `json:"barcode" validate:"max=64"`
+	Name            string `json:"name" validate:"required,max=255"`
+	Measure         string `json:"measure" validate:"max=32"`
+	Cost            int    `json:"cost" validate:"min=0"`
+	ProducerCountry string `json:"producer_country" validate:"country"`
+	BrandID         string `json:"brand_id" validate:"uuid"`
+	SupplierID      string `json:"supplier_id" validate:"uuid"`
+	Description     string `json:"description" validate:"max=4000"`
+	Image           string `json:"image" validate:"url,max=2048"`
 	IsWeighted      bool   `json:"is_weighted"`
 }
 
+// Bind reports every invalid field at once, as validate.Errors. Whether the
+// category exists is checked when the product is saved.
 func (s *Request) Bind(r *http.Request) error {
-	if s.Name == "" {
-		return errors.New("name: cannot be blank")
+	if err := validate.Struct(s).Err(); err != nil {
+		return err
 	}
 
-	if s.CategoryID == "" {
-		return errors.New("category_id: cannot be blank")
-	}
+	s.ProducerCountry = strings.ToUpper(strings.TrimSpace(s.ProducerCountry))
 	return nil
 }
 
@@ -38,7 +48,9 @@ type Response struct {
 	Measure         string `json:"measure"`
 	Cost            int    `json:"cost"`
 	ProducerCountry string `json:"producer_country"`
-	BrandName       string `json:"brand_name"`
+	BrandID         string `json:"brand_id"`
+	BrandName       string `json:"brand_name,omitempty"`
+	SupplierID      string `json:"supplier_id"`
 	Description     string `json:"description"`
 	Image           string `json:"image"`
 	IsWeighted      bool   `json:"is_weighted"`
@@ -46,17 +58,27 @@ type Response struct {
 
 func ParseFromEntity(data Entity) (res Response) {
 	res = Response{
-		ID:              data.ID,
-		CategoryID:      *data.CategoryID,
-		Barcode:         *data.Barcode,
-		Name:            *data.Name,
-		Measure:         *data.Measure,
-		Cost:            *data.Cost,
-		ProducerCountry: *data.ProducerCountry,
-		BrandName:       *data.BrandName,
-		Description:     *data.Description,
-		Image:           *data.Image,
-		IsWeighted:      *data.IsWeighted,
+		ID:          data.ID,
+		CategoryID:  *data.CategoryID,
+		Barcode:     *data.Barcode,
+		Name:        *data.Name,
+		Measure:     *data.Measure,
+		Cost:        *data.Cost,
+		Description: *data.Description,
+		Image:       *data.Image,
+		IsWeighted:  *data.IsWeighted,
+	}
+	if data.ProducerCountry != nil {
+		res.ProducerCountry = *data.ProducerCountry
+	}
+	if data.BrandID != nil {
+		res.BrandID = *data.BrandID
+	}
+	if data.BrandName != nil {
+		res.BrandName = *data.BrandName
+	}
+	if data.SupplierID != nil {
+		res.SupplierID = *data.SupplierID
 	}
 	return
 }
@@ -68,3 +90,61 @@ func ParseFromEntities(data []Entity) (res []Response) {
 	}
 	return
 }
+
+type UnmatchedReferenceResponse struct {
+	ProductID string `json:"product_id"`
+	Field     string `json:"field"`
+	Value     string `json:"value"`
+}
+
+func ParseFromUnmatched(data []UnmatchedReference) (res []UnmatchedReferenceResponse) {
+	res = make([]UnmatchedReferenceResponse, 0)
+	for _, object := range data {
+		res = append(res, UnmatchedReferenceResponse(object))
+	}
+	return
+}
+
+type BatchRequest struct {
+	Mode       string           `json:"mode"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+type BatchOperation struct {
+	batch.Operation
+	Data *Request `json:"data"`
+}
+
+func (s *BatchRequest) Bind(r *http.Request) error {
+	if s.Mode == "" {
+		s.Mode = batch.ModeAtomic
+	}
+
+	ops := make([]batch.Operation, len(s.Operations))
+	for i, op := range s.Operations {
+		ops[i] = op.Operation
+	}
+
+	err := batch.Validate(s.Mode, ops, func(i int) bool {
+		return s.Operations[i].Data != nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var errs validate.Errors
+	for i, op := range s.Operations {
+		if op.Data == nil {
+			continue
+		}
+		if err = op.Data.Bind(r); err != nil {
+			var fieldErrs validate.Errors
+			if !errors.As(err, &fieldErrs) {
+				return err
+			}
+			errs = append(errs, fieldErrs.Prefix(fmt.Sprintf("operations[%d].data.", i))...)
+		}
+	}
+
+	return errs.Err()
+}
@@ -8,8 +8,26 @@ type Entity struct {
 	Measure         *string `db:"measure"`
 	Cost            *int    `db:"cost"`
 	ProducerCountry *string `db:"producer_country"`
+	BrandID         *string `db:"brand_id"`
 	BrandName       *string `db:"brand_name"`
+	SupplierID      *string `db:"supplier_id"`
+	SupplierName    *string `db:"supplier_name"`
 	Description     *string `db:"description"`
 	Image           *string `db:"image"`
 	IsWeighted      *bool   `db:"is_weighted"`
 }
+
+// UnmatchedReference is a free-text value left over from before brands and
+// countries became entities, that could not be mapped automatically.
+type UnmatchedReference struct {
+	ProductID string `db:"product_id"`
+	Field     string `db:"field"`
+	Value     string `db:"value"`
+}
+
+// CostChange is the cost of a product before and after a mass price update.
+type CostChange struct {
+	ID     string `db:"id"`
+	Before int    `db:"before"`
+	After  int    `db:"after"`
+}
@@ -0,0 +1,138 @@
+package product
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ExportColumns are the header of CSV and XLSX exports. They use the same
+// names as the import columns so an export can be imported back unchanged.
+var ExportColumns = []string{
+	"id", "barcode", "name", "category_id", "category", "measure", "cost",
+	"producer_country", "brand", "supplier", "description", "image", "is_weighted",
+}
+
+// ExportRecord is a single exported product. CategoryPath is the slash
+// separated chain of category names, e.g. "Drinks/Juice".
+type ExportRecord struct {
+	ID              string `json:"id"`
+	Barcode         string `json:"barcode"`
+	Name            string `json:"name"`
+	CategoryID      string `json:"category_id"`
+	CategoryPath    string `json:"category"`
+	Measure         string `json:"measure"`
+	Cost            int    `json:"cost"`
+	ProducerCountry string `json:"producer_country"`
+	Brand           string `json:"brand"`
+	Supplier        string `json:"supplier"`
+	Description     string `json:"description"`
+	Image           string `json:"image"`
+	IsWeighted      bool   `json:"is_weighted"`
+}
+
+func ParseToExport(data Entity, categoryPath string) (res ExportRecord) {
+	value := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+
+	res = ExportRecord{
+		ID:              data.ID,
+		Barcode:         value(data.Barcode),
+		Name:            value(data.Name),
+		CategoryID:      value(data.CategoryID),
+		CategoryPath:    categoryPath,
+		Measure:         value(data.Measure),
+		ProducerCountry: value(data.ProducerCountry),
+		Brand:           value(data.BrandName),
+		Supplier:        value(data.SupplierName),
+		Description:     value(data.Description),
+		Image:           value(data.Image),
+	}
+	if data.Cost != nil {
+		res.Cost = *data.Cost
+	}
+	if data.IsWeighted != nil {
+		res.IsWeighted = *data.IsWeighted
+	}
+	return
+}
+
+// Values returns the record in the order of ExportColumns.
+func (s ExportRecord) Values() []string {
+	return []string{
+		s.ID, s.Barcode, s.Name, s.CategoryID, s.CategoryPath, s.Measure, strconv.Itoa(s.Cost),
+		s.ProducerCountry, s.Brand, s.Supplier, s.Description, s.Image, strconv.FormatBool(s.IsWeighted),
+	}
+}
+
+const (
+	ExportFormatCSV   = "csv"
+	ExportFormatJSONL = "jsonl"
+	ExportFormatXLSX  = "xlsx"
+)
+
+var exportContentTypes = map[string]string{
+	ExportFormatCSV:   "text/csv",
+	ExportFormatJSONL: "application/x-ndjson",
+	ExportFormatXLSX:  "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+type ExportRequest struct {
+	Format  string  `json:"format"`
+	Gzip    bool    `json:"gzip"`
+	Filters Filters `json:"filters"`
+}
+
+// ParseExportRequest reads the format, gzip flag and list filters from the query string.
+func ParseExportRequest(r *http.Request) (req ExportRequest, err error) {
+	query := r.URL.Query()
+
+	req.Format = strings.ToLower(query.Get("format"))
+	if req.Format == "" {
+		req.Format = ExportFormatCSV
+	}
+	if _, ok := exportContentTypes[req.Format]; !ok {
+		err = errors.New("format: must be csv, jsonl or xlsx")
+		return
+	}
+
+	if value := query.Get("gzip"); value != "" {
+		if req.Gzip, err = strconv.ParseBool(value); err != nil {
+			err = errors.New("gzip: must be a boolean")
+			return
+		}
+	}
+
+	req.Filters = ParseFilters(r)
+
+	return
+}
+
+// ExportResult describes the file written by an export job.
+type ExportResult struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Rows        int    `json:"rows"`
+}
+
+// ContentType is the media type of the exported file, before compression.
+func (s ExportRequest) ContentType() string {
+	if s.Gzip {
+		return "application/gzip"
+	}
+	return exportContentTypes[s.Format]
+}
+
+// Filename is the suggested name of the exported file.
+func (s ExportRequest) Filename() string {
+	filename := "products." + s.Format
+	if s.Gzip {
+		filename += ".gz"
+	}
+	return filename
+}
@@ -0,0 +1,37 @@
+package product
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Filters narrows down product listings and exports.
+type Filters struct {
+	CostGTE         *int   `json:"cost_gte,omitempty"`
+	CostLTE         *int   `json:"cost_lte,omitempty"`
+	Search          string `json:"search,omitempty"`
+	BrandID         string `json:"brand_id,omitempty"`
+	SupplierID      string `json:"supplier_id,omitempty"`
+	ProducerCountry string `json:"producer_country,omitempty"`
+}
+
+// ParseFilters reads the filters from the query string. Malformed values are ignored.
+func ParseFilters(r *http.Request) (filters Filters) {
+	query := r.URL.Query()
+
+	if value, err := strconv.Atoi(query.Get("cost_gte")); err == nil {
+		filters.CostGTE = &value
+	}
+
+	if value, err := strconv.Atoi(query.Get("cost_lte")); err == nil {
+		filters.CostLTE = &value
+	}
+
+	filters.Search = strings.ToLower(query.Get("search"))
+	filters.BrandID = query.Get("brand_id")
+	filters.SupplierID = query.Get("supplier_id")
+	filters.ProducerCountry = strings.ToUpper(strings.TrimSpace(query.Get("producer_country")))
+
+	return
+}
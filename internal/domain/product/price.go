@@ -0,0 +1,30 @@
+package product
+
+import (
+	"net/http"
+
+	"product/pkg/validate"
+)
+
+// PriceUpdateRequest changes the cost of every product matching the filters.
+// The cost is first scaled by Percent and then shifted by Delta; the result
+// is rounded and never drops below zero.
+type PriceUpdateRequest struct {
+	Percent float64 `json:"percent"`
+	Delta   int     `json:"delta"`
+	Filters Filters `json:"filters"`
+}
+
+// Bind reports every invalid field at once, as validate.Errors.
+func (s *PriceUpdateRequest) Bind(r *http.Request) error {
+	var errs validate.Errors
+	if s.Percent == 0 && s.Delta == 0 {
+		errs.Add("percent", validate.CodeRequired, "either percent or delta must be set")
+	}
+
+	if s.Percent <= -100 {
+		errs.Add("percent", validate.CodeTooSmall, "must be greater than -100")
+	}
+
+	return errs.Err()
+}
@@ -0,0 +1,35 @@
+package product
+
+import (
+	"errors"
+	"testing"
+
+	"product/pkg/validate"
+)
+
+func TestPriceUpdateRequestBind(t *testing.T) {
+	tests := []struct {
+		name string
+		req  PriceUpdateRequest
+		want validate.Errors
+	}{
+		{"percent", PriceUpdateRequest{Percent: -10}, nil},
+		{"delta", PriceUpdateRequest{Delta: 5}, nil},
+		{"neither", PriceUpdateRequest{}, validate.Errors{{Field: "percent", Code: validate.CodeRequired, Message: "either percent or delta must be set"}}},
+		{"percent down to zero", PriceUpdateRequest{Percent: -100}, validate.Errors{{Field: "percent", Code: validate.CodeTooSmall, Message: "must be greater than -100"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Bind(nil)
+
+			var got validate.Errors
+			if err != nil && !errors.As(err, &got) {
+				t.Fatalf("Bind() error = %v, want validate.Errors", err)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("Bind() errors = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,13 +2,21 @@ package product
 
 import (
 	"context"
-	"net/http"
 )
 
 type Repository interface {
-	Select(ctx context.Context, r *http.Request) (dest []Entity, err error)
+	Select(ctx context.Context, filters Filters) (dest []Entity, err error)
+	Stream(ctx context.Context, filters Filters, fn func(Entity) error) (err error)
+	Count(ctx context.Context, filters Filters) (count int, err error)
+	// AdjustCosts rescales the cost of at most limit products matching the filters
+	// whose id sorts after afterID, and returns the changes ordered by id.
+	AdjustCosts(ctx context.Context, filters Filters, percent float64, delta int, afterID string, limit int) (dest []CostChange, err error)
 	Create(ctx context.Context, data Entity) (id string, err error)
 	Get(ctx context.Context, id string) (dest Entity, err error)
+	GetByBarcode(ctx context.Context, barcode string) (dest Entity, err error)
+	SelectByBarcodes(ctx context.Context, barcodes []string) (dest []Entity, err error)
 	Update(ctx context.Context, id string, data Entity) (err error)
 	Delete(ctx context.Context, id string) (err error)
+	Upsert(ctx context.Context, data []Entity) (err error)
+	SelectUnmatched(ctx context.Context) (dest []UnmatchedReference, err error)
 }
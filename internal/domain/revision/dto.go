@@ -0,0 +1,76 @@
+package revision
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"product/internal/domain/audit"
+)
+
+type Response struct {
+	Revision  int             `json:"revision"`
+	CreatedAt time.Time       `json:"created_at"`
+	Actor     string          `json:"actor"`
+	RequestID string          `json:"request_id,omitempty"`
+	Operation string          `json:"operation"`
+	Snapshot  json.RawMessage `json:"snapshot" swaggertype:"object"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		Revision:  data.Revision,
+		Actor:     *data.Actor,
+		Operation: *data.Operation,
+		Snapshot:  json.RawMessage("null"),
+	}
+	if data.CreatedAt != nil {
+		res.CreatedAt = *data.CreatedAt
+	}
+	if data.RequestID != nil {
+		res.RequestID = *data.RequestID
+	}
+	if data.Snapshot != nil {
+		res.Snapshot = json.RawMessage(*data.Snapshot)
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
+
+// DiffResponse lists the fields that differ between two revisions.
+type DiffResponse struct {
+	From    int                     `json:"from"`
+	To      int                     `json:"to"`
+	Changes map[string]audit.Change `json:"changes"`
+}
+
+// ParseNumber reads a revision number from a path or query parameter.
+func ParseNumber(name, value string) (revision int, err error) {
+	revision, err = strconv.Atoi(value)
+	if err != nil || revision <= 0 {
+		err = errors.New(name + ": must be a positive revision number")
+	}
+	return
+}
+
+// ParseDiffRequest reads the two revisions to compare from the from and to query parameters.
+func ParseDiffRequest(r *http.Request) (from, to int, err error) {
+	query := r.URL.Query()
+
+	if from, err = ParseNumber("from", query.Get("from")); err != nil {
+		return
+	}
+
+	to, err = ParseNumber("to", query.Get("to"))
+
+	return
+}
@@ -0,0 +1,54 @@
+package revision
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+		ok    bool
+	}{
+		{"1", 1, true},
+		{"42", 42, true},
+		{"0", 0, false},
+		{"-1", 0, false},
+		{"first", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseNumber("rev", tt.value)
+		if (err == nil) != tt.ok || tt.ok && got != tt.want {
+			t.Errorf("ParseNumber(%q) = %d, %v, want %d", tt.value, got, err, tt.want)
+		}
+		if err != nil && err.Error() != "rev: must be a positive revision number" {
+			t.Errorf("got error %q for %q", err, tt.value)
+		}
+	}
+}
+
+func TestParseDiffRequest(t *testing.T) {
+	tests := []struct {
+		query    string
+		from, to int
+		err      string
+	}{
+		{"from=1&to=3", 1, 3, ""},
+		{"to=3", 0, 0, "from: must be a positive revision number"},
+		{"from=1&to=latest", 1, 0, "to: must be a positive revision number"},
+	}
+
+	for _, tt := range tests {
+		from, to, err := ParseDiffRequest(httptest.NewRequest("GET", "/diff?"+tt.query, nil))
+		if tt.err == "" && err != nil || tt.err != "" && (err == nil || err.Error() != tt.err) {
+			t.Errorf("ParseDiffRequest(%q) error = %v, want %q", tt.query, err, tt.err)
+			continue
+		}
+		if tt.err == "" && (from != tt.from || to != tt.to) {
+			t.Errorf("ParseDiffRequest(%q) = %d, %d, want %d, %d", tt.query, from, to, tt.from, tt.to)
+		}
+	}
+}
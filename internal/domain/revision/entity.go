@@ -0,0 +1,18 @@
+package revision
+
+import (
+	"time"
+)
+
+// Entity is the full state of a product or category after a change.
+// The snapshot of a delete is null.
+type Entity struct {
+	EntityType *string    `db:"entity_type"`
+	EntityID   *string    `db:"entity_id"`
+	Revision   int        `db:"revision"`
+	CreatedAt  *time.Time `db:"created_at"`
+	Actor      *string    `db:"actor"`
+	RequestID  *string    `db:"request_id"`
+	Operation  *string    `db:"operation"`
+	Snapshot   *string    `db:"snapshot"`
+}
@@ -0,0 +1,12 @@
+package revision
+
+import (
+	"context"
+)
+
+type Repository interface {
+	// Create stores data as the next revision of its entity and returns its number.
+	Create(ctx context.Context, data Entity) (revision int, err error)
+	Select(ctx context.Context, entityType, entityID string) (dest []Entity, err error)
+	Get(ctx context.Context, entityType, entityID string, revision int) (dest Entity, err error)
+}
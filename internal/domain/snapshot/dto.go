@@ -0,0 +1,76 @@
+package snapshot
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"product/internal/domain/product"
+)
+
+// BundleFormat is bumped whenever the layout of the bundle changes.
+const BundleFormat = 1
+
+// Category is a category as stored in the bundle. Path lists the names from
+// the root down, so kiosks can rebuild the tree without following parent ids.
+type Category struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ParentID string `json:"parent_id"`
+	Path     string `json:"path"`
+}
+
+// Product is a product as stored in the bundle; Image is a reference kiosks
+// download separately.
+type Product = product.Response
+
+type Response struct {
+	Version        int64     `json:"version"`
+	CreatedAt      time.Time `json:"created_at"`
+	Hash           string    `json:"hash"`
+	Size           int64     `json:"size"`
+	CompressedSize int64     `json:"compressed_size"`
+	SyncToken      string    `json:"sync_token"`
+	Categories     int       `json:"categories"`
+	Products       int       `json:"products"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		Version:        data.Version,
+		Hash:           *data.Hash,
+		Size:           *data.Size,
+		CompressedSize: *data.CompressedSize,
+		SyncToken:      *data.SyncToken,
+		Categories:     *data.Categories,
+		Products:       *data.Products,
+	}
+	if data.CreatedAt != nil {
+		res.CreatedAt = *data.CreatedAt
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
+
+// Result is stored with a finished snapshot job. Unchanged is set when the
+// catalog matched the latest snapshot and no new version was written.
+type Result struct {
+	Version   int64 `json:"version"`
+	Unchanged bool  `json:"unchanged"`
+}
+
+// ParseVersion reads a snapshot version from a path or query parameter.
+func ParseVersion(name, value string) (version int64, err error) {
+	version, err = strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		err = errors.New(name + ": must be a positive snapshot version")
+	}
+	return
+}
@@ -0,0 +1,21 @@
+package snapshot
+
+import (
+	"time"
+)
+
+// Entity describes a catalog snapshot. Hash is the SHA-256 of the
+// uncompressed bundle and SyncToken the change feed position it is current to.
+// Bundle, the gzipped bundle itself, is only set to create a snapshot, see
+// Repository.Bundle.
+type Entity struct {
+	Version        int64      `db:"version"`
+	CreatedAt      *time.Time `db:"created_at"`
+	Hash           *string    `db:"hash"`
+	Size           *int64     `db:"size"`
+	CompressedSize *int64     `db:"compressed_size"`
+	SyncToken      *string    `db:"sync_token"`
+	Categories     *int       `db:"categories"`
+	Products       *int       `db:"products"`
+	Bundle         []byte     `db:"bundle"`
+}
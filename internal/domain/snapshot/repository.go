@@ -0,0 +1,22 @@
+package snapshot
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Select(ctx context.Context, limit int) (dest []Entity, err error)
+	Create(ctx context.Context, data Entity) (version int64, err error)
+	Get(ctx context.Context, version int64) (dest Entity, err error)
+	Latest(ctx context.Context) (dest Entity, err error)
+	// Bundle returns the gzipped bundle of a snapshot.
+	Bundle(ctx context.Context, version int64) (bundle []byte, err error)
+	// Delta returns the delta between two snapshots kept with CreateDelta.
+	Delta(ctx context.Context, from, to int64) (patch []byte, err error)
+	// CreateDelta keeps the delta between two snapshots. Keeping the same one
+	// twice, e.g. from two instances at once, is not an error.
+	CreateDelta(ctx context.Context, from, to int64, patch []byte) (err error)
+	// Prune deletes all but the keep latest snapshots, along with their deltas,
+	// and returns the versions deleted.
+	Prune(ctx context.Context, keep int) (versions []int64, err error)
+}
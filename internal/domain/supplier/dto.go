@@ -0,0 +1,63 @@
+package supplier
+
+import (
+	"net/http"
+	"strings"
+
+	"product/pkg/validate"
+)
+
+func init() {
+	validate.Register(&Request{})
+}
+
+type Request struct {
+	Name    string `json:"name" validate:"required"`
+	Country string `json:"country" validate:"country"`
+	Email   string `json:"email" validate:"email"`
+	Phone   string `json:"phone"`
+}
+
+// Bind reports every invalid field at once, as validate.Errors.
+func (s *Request) Bind(r *http.Request) error {
+	s.Name = strings.TrimSpace(s.Name)
+	if err := validate.Struct(s).Err(); err != nil {
+		return err
+	}
+
+	s.Country = strings.ToUpper(strings.TrimSpace(s.Country))
+	return nil
+}
+
+type Response struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Country string `json:"country"`
+	Email   string `json:"email"`
+	Phone   string `json:"phone"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:   data.ID,
+		Name: *data.Name,
+	}
+	if data.Country != nil {
+		res.Country = *data.Country
+	}
+	if data.Email != nil {
+		res.Email = *data.Email
+	}
+	if data.Phone != nil {
+		res.Phone = *data.Phone
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
@@ -0,0 +1,46 @@
+package supplier
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"product/pkg/validate"
+)
+
+func codes(t *testing.T, err error) (res []string) {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+	var errs validate.Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error = %v, want validate.Errors", err)
+	}
+	for _, fieldError := range errs {
+		res = append(res, fieldError.Field+"="+fieldError.Code)
+	}
+	return
+}
+
+func TestRequestBind(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want []string
+	}{
+		{"valid", Request{Name: "Wholesale Ltd", Country: "gb", Email: "orders@example.com"}, nil},
+		{"name only", Request{Name: "Wholesale Ltd"}, nil},
+		{"empty", Request{}, []string{"name=required"}},
+		{"invalid", Request{Name: "Wholesale Ltd", Country: "UK", Email: "orders"}, []string{"country=invalid_country", "email=invalid_email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(t, tt.req.Bind(nil)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Bind() errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+package supplier
+
+type Entity struct {
+	ID      string  `db:"id"`
+	Name    *string `db:"name"`
+	Country *string `db:"country"`
+	Email   *string `db:"email"`
+	Phone   *string `db:"phone"`
+}
@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"product/pkg/validate"
+)
+
+func init() {
+	validate.Register(&Request{})
+}
+
+type Request struct {
+	URL        string   `json:"url" validate:"required,url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+	Active     *bool    `json:"active"`
+}
+
+// Bind reports every invalid field at once, as validate.Errors.
+func (s *Request) Bind(r *http.Request) error {
+	errs := validate.Struct(s)
+	for i, eventType := range s.EventTypes {
+		s.EventTypes[i] = strings.TrimSpace(eventType)
+		if s.EventTypes[i] == "" {
+			errs.Add(fmt.Sprintf("event_types[%d]", i), validate.CodeRequired, "cannot be blank")
+		}
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	if s.EventTypes == nil {
+		s.EventTypes = make([]string, 0)
+	}
+
+	if s.Active == nil {
+		active := true
+		s.Active = &active
+	}
+
+	return nil
+}
+
+// Response never includes the secret, except right after a webhook is created.
+type Response struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty"`
+}
+
+func ParseFromEntity(data Entity) (res Response) {
+	res = Response{
+		ID:         data.ID,
+		URL:        *data.URL,
+		EventTypes: []string(data.EventTypes),
+		Active:     *data.Active,
+	}
+	if data.CreatedAt != nil {
+		res.CreatedAt = *data.CreatedAt
+	}
+	if res.EventTypes == nil {
+		res.EventTypes = make([]string, 0)
+	}
+	return
+}
+
+func ParseFromEntities(data []Entity) (res []Response) {
+	res = make([]Response, 0)
+	for _, object := range data {
+		res = append(res, ParseFromEntity(object))
+	}
+	return
+}
+
+type DeliveryResponse struct {
+	ID             int64           `json:"id"`
+	CreatedAt      time.Time       `json:"created_at"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+func ParseFromDelivery(data Delivery) (res DeliveryResponse) {
+	res = DeliveryResponse{
+		ID:          data.ID,
+		EventID:     *data.EventID,
+		EventType:   *data.EventType,
+		Status:      *data.Status,
+		Attempts:    *data.Attempts,
+		DeliveredAt: data.DeliveredAt,
+	}
+	if data.CreatedAt != nil {
+		res.CreatedAt = *data.CreatedAt
+	}
+	if res.Status == DeliveryPending {
+		res.NextAttemptAt = data.NextAttemptAt
+	}
+	if data.LastStatusCode != nil {
+		res.LastStatusCode = *data.LastStatusCode
+	}
+	if data.LastError != nil {
+		res.LastError = *data.LastError
+	}
+	if data.Payload != nil {
+		res.Payload = json.RawMessage(*data.Payload)
+	}
+	return
+}
+
+func ParseFromDeliveries(data []Delivery) (res []DeliveryResponse) {
+	res = make([]DeliveryResponse, 0)
+	for _, object := range data {
+		res = append(res, ParseFromDelivery(object))
+	}
+	return
+}
+
+const (
+	defaultDeliveryLimit = 100
+	maxDeliveryLimit     = 1000
+)
+
+// ParseDeliveryLimit reads how many deliveries to list from the limit query parameter.
+func ParseDeliveryLimit(r *http.Request) (limit int, err error) {
+	limit = defaultDeliveryLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 || limit > maxDeliveryLimit {
+			return limit, errors.New("limit: must be between 1 and " + strconv.Itoa(maxDeliveryLimit))
+		}
+	}
+
+	return
+}
+
+// ParseDeliveryID reads the id of a delivery from a path parameter.
+func ParseDeliveryID(value string) (id int64, err error) {
+	if id, err = strconv.ParseInt(value, 10, 64); err != nil || id <= 0 {
+		return 0, errors.New("delivery: must be a positive integer")
+	}
+
+	return
+}
@@ -0,0 +1,41 @@
+package webhook
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+type Entity struct {
+	ID         string         `db:"id"`
+	CreatedAt  *time.Time     `db:"created_at"`
+	URL        *string        `db:"url"`
+	EventTypes pq.StringArray `db:"event_types"`
+	Secret     *string        `db:"secret"`
+	Active     *bool          `db:"active"`
+}
+
+// Delivery is a single event sent to a single webhook. URL and Secret are
+// read from the webhook when a delivery is claimed.
+type Delivery struct {
+	ID             int64      `db:"id"`
+	CreatedAt      *time.Time `db:"created_at"`
+	WebhookID      *string    `db:"webhook_id"`
+	EventID        *int64     `db:"event_id"`
+	EventType      *string    `db:"event_type"`
+	Payload        *string    `db:"payload"`
+	Status         *string    `db:"status"`
+	Attempts       *int       `db:"attempts"`
+	NextAttemptAt  *time.Time `db:"next_attempt_at"`
+	LastStatusCode *int       `db:"last_status_code"`
+	LastError      *string    `db:"last_error"`
+	DeliveredAt    *time.Time `db:"delivered_at"`
+	URL            *string    `db:"url"`
+	Secret         *string    `db:"secret"`
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"strings"
+)
+
+// Matches reports whether a webhook subscribed to eventTypes wants an event.
+// A pattern is an event type, "<aggregate>.*" or "*"; no pattern means every event.
+func Matches(eventTypes []string, eventType string) bool {
+	if len(eventTypes) == 0 {
+		return true
+	}
+
+	for _, pattern := range eventTypes {
+		switch {
+		case pattern == "*" || pattern == eventType:
+			return true
+		case strings.HasSuffix(pattern, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(pattern, "*")):
+			return true
+		}
+	}
+
+	return false
+}
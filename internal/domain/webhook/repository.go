@@ -0,0 +1,26 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Select(ctx context.Context) (dest []Entity, err error)
+	Create(ctx context.Context, data Entity) (id string, err error)
+	Get(ctx context.Context, id string) (dest Entity, err error)
+	Update(ctx context.Context, id string, data Entity) (err error)
+	Delete(ctx context.Context, id string) (err error)
+
+	// AddDeliveries queues deliveries, skipping the events a webhook already has.
+	AddDeliveries(ctx context.Context, data []Delivery) (err error)
+	SelectDeliveries(ctx context.Context, webhookID string, limit int) (dest []Delivery, err error)
+	// ClaimDeliveries takes up to limit due deliveries and postpones them by
+	// lease, so they are retried if the claimer never reports back.
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) (dest []Delivery, err error)
+	// FinishDelivery records the outcome of an attempt. A pending delivery is
+	// attempted again after delay.
+	FinishDelivery(ctx context.Context, id int64, status string, statusCode int, lastError string, delay time.Duration) (err error)
+	// Redeliver queues a delivery of the webhook again right away, whatever its status.
+	Redeliver(ctx context.Context, webhookID string, id int64) (err error)
+}
@@ -3,17 +3,31 @@ package handler
 import (
 	"github.com/go-chi/chi/v5"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
+	stdhttp "net/http"
 	"net/url"
 	"product/docs"
+	"product/internal/auth"
 	"product/internal/config"
+	roles "product/internal/domain/auth"
 	"product/internal/handler/http"
+	"product/internal/health"
+	"product/internal/metrics"
 	"product/internal/service"
+	"product/internal/tracing"
+	"product/pkg/log"
 	"product/pkg/server/router"
 )
 
 type Dependencies struct {
 	Service *service.Service
 	Configs config.Config
+	// Verifier checks the bearer tokens of API requests. Authentication is
+	// disabled when it is nil.
+	Verifier *auth.Verifier
+	// Metrics records every request, when set.
+	Metrics *metrics.Metrics
+	// Health serves /healthz and /readyz, when set.
+	Health *health.Health
 }
 
 // Configuration is an alias for a function that will take in a pointer to a Handler and modify it
@@ -59,11 +73,25 @@ func New(d Dependencies, configs ...Configuration) (h *Handler, err error) {
 //	@host		localhost
 //	@BasePath	/api/v1
 
+//	@securityDefinitions.apikey	BearerAuth
+//	@in							header
+//	@name						Authorization
+
+//	@securityDefinitions.apikey	APIKeyAuth
+//	@in							header
+//	@name						X-API-Key
+
 // WithHTTPHandler applies a http handler to the Handler
 func WithHTTPHandler() Configuration {
 	return func(h *Handler) (err error) {
 		// Create the http handler, if we needed parameters, such as connection strings they could be inputted here
-		h.HTTP = router.New()
+		h.HTTP = router.New(h.dependencies.Configs.HTTP.CORSOrigins...)
+		h.HTTP.Use(tracing.Middleware)
+		h.HTTP.Use(log.Requests)
+		h.HTTP.Use(http.ReadYourWrites)
+		if h.dependencies.Metrics != nil {
+			h.HTTP.Use(h.dependencies.Metrics.Middleware)
+		}
 
 		docs.SwaggerInfo.BasePath = "/api/v1"
 		docs.SwaggerInfo.Host = h.dependencies.Configs.HTTP.Host
@@ -80,14 +108,69 @@ func WithHTTPHandler() Configuration {
 			httpSwagger.URL(swaggerURL.String()),
 		))
 
+		if h.dependencies.Health != nil {
+			h.HTTP.Get("/healthz", h.dependencies.Health.Live)
+			h.HTTP.Get("/readyz", h.dependencies.Health.Ready)
+		}
+
 		authorHandler := http.NewCategoryHandler(h.dependencies.Service)
 		bookHandler := http.NewProductHandler(h.dependencies.Service)
+		modifierHandler := http.NewModifierHandler(h.dependencies.Service)
+		brandHandler := http.NewBrandHandler(h.dependencies.Service)
+		supplierHandler := http.NewSupplierHandler(h.dependencies.Service)
+		countryHandler := http.NewCountryHandler(h.dependencies.Service)
+		importHandler := http.NewImportHandler(h.dependencies.Service)
+		exportHandler := http.NewExportHandler(h.dependencies.Service)
+		jobHandler := http.NewJobHandler(h.dependencies.Service)
+		auditHandler := http.NewAuditHandler(h.dependencies.Service)
+		webhookHandler := http.NewWebhookHandler(h.dependencies.Service)
+		changeHandler := http.NewChangeHandler(h.dependencies.Service)
+		snapshotHandler := http.NewSnapshotHandler(h.dependencies.Service)
+		apiKeyHandler := http.NewAPIKeyHandler(h.dependencies.Service)
+
+		var (
+			catalogReaders = []string{roles.RoleCatalogManager, roles.RoleKioskReadOnly}
+
+			catalog  = h.authorize(catalogReaders, []string{roles.RoleCatalogManager})
+			readers  = h.authorize(catalogReaders, catalogReaders)
+			managers = h.authorize([]string{roles.RoleCatalogManager}, []string{roles.RoleCatalogManager})
+			admins   = h.authorize(nil, nil)
+		)
 
 		h.HTTP.Route("/api/v1", func(r chi.Router) {
-			r.Mount("/categories", authorHandler.Routes())
-			r.Mount("/products", bookHandler.Routes())
+			if h.dependencies.Verifier != nil {
+				r.Use(http.Authenticate(h.dependencies.Verifier, h.dependencies.Service))
+			}
+			r.Use(http.AuditMetadata)
+
+			r.With(catalog).Mount("/categories", authorHandler.Routes())
+			r.With(catalog).Mount("/products", bookHandler.Routes())
+			// Checking a configuration changes nothing, so whoever reads the
+			// catalog may post one, kiosks included.
+			r.With(readers).Post("/products/{id}/configurations/validate", bookHandler.ValidateConfiguration)
+			r.With(catalog).Mount("/modifiers", modifierHandler.Routes())
+			r.With(catalog).Mount("/brands", brandHandler.Routes())
+			r.With(catalog).Mount("/suppliers", supplierHandler.Routes())
+			r.With(catalog).Mount("/countries", countryHandler.Routes())
+			r.With(catalog).Mount("/changes", changeHandler.Routes())
+			r.With(catalog).Mount("/snapshots", snapshotHandler.Routes())
+			r.With(managers).Mount("/imports", importHandler.Routes())
+			r.With(managers).Mount("/exports", exportHandler.Routes())
+			r.With(managers).Mount("/jobs", jobHandler.Routes())
+			r.With(admins).Mount("/audit", auditHandler.Routes())
+			r.With(admins).Mount("/webhooks", webhookHandler.Routes())
+			r.With(admins).Mount("/api-keys", apiKeyHandler.Routes())
 		})
 
 		return
 	}
 }
+
+// authorize restricts a group of routes to the given roles, see http.Authorize.
+// Every request passes when authentication is disabled.
+func (h *Handler) authorize(readers, writers []string) func(stdhttp.Handler) stdhttp.Handler {
+	if h.dependencies.Verifier == nil {
+		return func(next stdhttp.Handler) stdhttp.Handler { return next }
+	}
+	return http.Authorize(readers, writers)
+}
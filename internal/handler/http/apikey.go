@@ -0,0 +1,159 @@
+package http
+
+import (
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"io"
+	"net/http"
+	"product/internal/domain/apikey"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+)
+
+type APIKeyHandler struct {
+	Service *service.Service
+}
+
+func NewAPIKeyHandler(s *service.Service) *APIKeyHandler {
+	return &APIKeyHandler{Service: s}
+}
+
+func (h *APIKeyHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.issue)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Delete("/", h.revoke)
+		r.Post("/rotate", h.rotate)
+	})
+
+	return r
+}
+
+// List of device API keys from the database
+//
+//	@Summary	List of device API keys from the database
+//	@Tags		api-keys
+//	@Accept		json
+//	@Produce	json
+//	@Success	200				{array}		apikey.Response
+//	@Failure	500				{object}	status.Problem
+//	@Router		/api-keys 	[get]
+func (h *APIKeyHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Service.ListAPIKeys(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Issue a new device API key
+//
+//	@Summary		Issue a new device API key
+//	@Description	The key is only returned here and cannot be recovered later.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		apikey.Request	true	"body param"
+//	@Success		200		{object}	apikey.IssuedResponse
+//	@Failure		400		{object}	status.Problem
+//	@Failure		500		{object}	status.Problem
+//	@Router			/api-keys [post]
+func (h *APIKeyHandler) issue(w http.ResponseWriter, r *http.Request) {
+	req := apikey.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.IssueAPIKey(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Read the device API key from the database
+//
+//	@Summary	Read the device API key from the database
+//	@Tags		api-keys
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	apikey.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/api-keys/{id} [get]
+func (h *APIKeyHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.GetAPIKey(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Rotate the device API key
+//
+//	@Summary		Rotate the device API key
+//	@Description	Issues a replacement key with the same scopes. The old key keeps working for grace_period seconds, 0 by default.
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"path param"
+//	@Param			request	body		apikey.RotateRequest	false	"body param"
+//	@Success		200		{object}	apikey.IssuedResponse
+//	@Failure		400		{object}	status.Problem
+//	@Failure		404		{object}	status.Problem
+//	@Failure		500		{object}	status.Problem
+//	@Router			/api-keys/{id}/rotate [post]
+func (h *APIKeyHandler) rotate(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := apikey.RotateRequest{}
+	if err := render.Bind(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.RotateAPIKey(r.Context(), id, req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Revoke the device API key
+//
+//	@Summary	Revoke the device API key
+//	@Tags		api-keys
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path	string	true	"path param"
+//	@Success	200
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/api-keys/{id} [delete]
+func (h *APIKeyHandler) revoke(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.Service.RevokeAPIKey(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
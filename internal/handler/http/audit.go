@@ -0,0 +1,59 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/domain/audit"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+)
+
+type AuditHandler struct {
+	Service *service.Service
+}
+
+func NewAuditHandler(s *service.Service) *AuditHandler {
+	return &AuditHandler{Service: s}
+}
+
+func (h *AuditHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+
+	return r
+}
+
+// List of audit records, newest first
+//
+//	@Summary	List of audit records, newest first
+//	@Tags		audit
+//	@Accept		json
+//	@Produce	json
+//	@Param		actor		query		string	false	"who made the change"
+//	@Param		entity		query		string	false	"product, category, modifier_group, brand or supplier"
+//	@Param		entity_id	query		string	false	"entity id"
+//	@Param		from		query		string	false	"RFC 3339 timestamp, inclusive"
+//	@Param		to			query		string	false	"RFC 3339 timestamp, exclusive"
+//	@Param		limit		query		int		false	"maximal number of records, 100 by default"
+//	@Success	200			{array}		audit.Response
+//	@Failure	400			{object}	status.Problem
+//	@Failure	500			{object}	status.Problem
+//	@Router		/audit [get]
+func (h *AuditHandler) list(w http.ResponseWriter, r *http.Request) {
+	filters, err := audit.ParseFilters(r)
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.ListAudit(r.Context(), filters)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
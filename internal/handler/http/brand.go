@@ -0,0 +1,153 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/domain/brand"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+)
+
+type BrandHandler struct {
+	Service *service.Service
+}
+
+func NewBrandHandler(s *service.Service) *BrandHandler {
+	return &BrandHandler{Service: s}
+}
+
+func (h *BrandHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+	})
+
+	return r
+}
+
+// List of brands from the database
+//
+//	@Summary	List of brands from the database
+//	@Tags		brands
+//	@Accept		json
+//	@Produce	json
+//	@Success	200				{array}		brand.Response
+//	@Failure	500				{object}	status.Problem
+//	@Router		/brands 	[get]
+func (h *BrandHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Service.ListBrands(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Add a new brand to the database
+//
+//	@Summary	Add a new brand to the database
+//	@Tags		brands
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		brand.Request	true	"body param"
+//	@Success	200		{object}	brand.Response
+//	@Failure	400		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/brands [post]
+func (h *BrandHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := brand.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.AddBrand(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Read the brand from the database
+//
+//	@Summary	Read the brand from the database
+//	@Tags		brands
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	brand.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/brands/{id} [get]
+func (h *BrandHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.GetBrand(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Update the brand in the database
+//
+//	@Summary	Update the brand in the database
+//	@Tags		brands
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path	string					true	"path param"
+//	@Param		request	body	brand.Request	true	"body param"
+//	@Success	200
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/brands/{id} [put]
+func (h *BrandHandler) update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := brand.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	err := h.Service.UpdateBrand(r.Context(), id, req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
+
+// Delete the brand from the database
+//
+//	@Summary	Delete the brand from the database
+//	@Tags		brands
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path	string	true	"path param"
+//	@Success	200
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/brands/{id} [delete]
+func (h *BrandHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.Service.DeleteBrand(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
@@ -4,10 +4,12 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"net/http"
+	"product/internal/domain/audit"
 	"product/internal/domain/category"
+	"product/internal/domain/revision"
 	"product/internal/service"
+	"product/pkg/apperror"
 	"product/pkg/server/status"
-	"product/pkg/store"
 )
 
 type CategoryHandler struct {
@@ -23,11 +25,17 @@ func (h *CategoryHandler) Routes() chi.Router {
 
 	r.Get("/", h.list)
 	r.Post("/", h.add)
+	r.Post("/batch", h.batch)
 
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
 		r.Delete("/", h.delete)
+
+		r.Get("/revisions", h.listRevisions)
+		r.Get("/revisions/diff", h.diffRevisions)
+		r.Get("/revisions/{rev}", h.getRevision)
+		r.Post("/revisions/{rev}/restore", h.restoreRevision)
 	})
 
 	return r
@@ -40,12 +48,12 @@ func (h *CategoryHandler) Routes() chi.Router {
 //	@Accept		json
 //	@Produce	json
 //	@Success	200				{array}		category.Response
-//	@Failure	500				{object}	status.Response
+//	@Failure	500				{object}	status.Problem
 //	@Router		/categories 	[get]
 func (h *CategoryHandler) list(w http.ResponseWriter, r *http.Request) {
 	res, err := h.Service.ListCategories(r.Context())
 	if err != nil {
-		render.JSON(w, r, status.InternalServerError(err))
+		status.Error(w, r, err)
 		return
 	}
 
@@ -60,19 +68,19 @@ func (h *CategoryHandler) list(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Param		request	body		category.Request	true	"body param"
 //	@Success	200		{object}	category.Response
-//	@Failure	400		{object}	status.Response
-//	@Failure	500		{object}	status.Response
+//	@Failure	400		{object}	status.Problem{errors=[]validate.FieldError}
+//	@Failure	500		{object}	status.Problem
 //	@Router		/categories [post]
 func (h *CategoryHandler) add(w http.ResponseWriter, r *http.Request) {
 	req := category.Request{}
 	if err := render.Bind(r, &req); err != nil {
-		render.JSON(w, r, status.BadRequest(err, req))
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
 		return
 	}
 
 	res, err := h.Service.AddCategory(r.Context(), req)
 	if err != nil {
-		render.JSON(w, r, status.InternalServerError(err))
+		status.Error(w, r, err)
 		return
 	}
 
@@ -87,20 +95,15 @@ func (h *CategoryHandler) add(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Param		id	path		int	true	"path param"
 //	@Success	200	{object}	category.Response
-//	@Failure	404	{object}	status.Response
-//	@Failure	500	{object}	status.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
 //	@Router		/categories/{id} [get]
 func (h *CategoryHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	res, err := h.Service.GetCategory(r.Context(), id)
-	if err != nil && err != store.ErrorNotFound {
-		render.JSON(w, r, status.InternalServerError(err))
-		return
-	}
-
-	if err == store.ErrorNotFound {
-		render.JSON(w, r, status.NotFound(err))
+	if err != nil {
+		status.Error(w, r, err)
 		return
 	}
 
@@ -116,27 +119,22 @@ func (h *CategoryHandler) get(w http.ResponseWriter, r *http.Request) {
 //	@Param		id		path	int					true	"path param"
 //	@Param		request	body	category.Request	true	"body param"
 //	@Success	200
-//	@Failure	400	{object}	status.Response
-//	@Failure	404	{object}	status.Response
-//	@Failure	500	{object}	status.Response
+//	@Failure	400	{object}	status.Problem{errors=[]validate.FieldError}
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
 //	@Router		/categories/{id} [put]
 func (h *CategoryHandler) update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	req := category.Request{}
 	if err := render.Bind(r, &req); err != nil {
-		render.JSON(w, r, status.BadRequest(err, req))
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
 		return
 	}
 
 	err := h.Service.UpdateCategory(r.Context(), id, req)
-	if err != nil && err != store.ErrorNotFound {
-		render.JSON(w, r, status.InternalServerError(err))
-		return
-	}
-
-	if err == store.ErrorNotFound {
-		render.JSON(w, r, status.NotFound(err))
+	if err != nil {
+		status.Error(w, r, err)
 		return
 	}
 }
@@ -149,20 +147,157 @@ func (h *CategoryHandler) update(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Param		id	path	int	true	"path param"
 //	@Success	200
-//	@Failure	404	{object}	status.Response
-//	@Failure	500	{object}	status.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
 //	@Router		/categories/{id} [delete]
 func (h *CategoryHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	err := h.Service.DeleteCategory(r.Context(), id)
-	if err != nil && err != store.ErrorNotFound {
-		render.JSON(w, r, status.InternalServerError(err))
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
+
+// Apply a batch of category creates, updates and deletes
+//
+//	@Summary	Apply a batch of category creates, updates and deletes
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		category.BatchRequest	true	"body param"
+//	@Success	200		{object}	batch.Response
+//	@Failure	400		{object}	status.Problem{errors=[]validate.FieldError}
+//	@Router		/categories/batch [post]
+func (h *CategoryHandler) batch(w http.ResponseWriter, r *http.Request) {
+	req := category.BatchRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.BatchCategories(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
 		return
 	}
 
-	if err == store.ErrorNotFound {
-		render.JSON(w, r, status.NotFound(err))
+	render.JSON(w, r, status.OK(res))
+}
+
+// List of revisions of the category, newest first
+//
+//	@Summary	List of revisions of the category, newest first
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{array}		revision.Response
+//	@Failure	500	{object}	status.Problem
+//	@Router		/categories/{id}/revisions [get]
+func (h *CategoryHandler) listRevisions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.ListRevisions(r.Context(), audit.EntityCategory, id)
+	if err != nil {
+		status.Error(w, r, err)
 		return
 	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Read a revision of the category
+//
+//	@Summary	Read a revision of the category
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Param		rev	path		int		true	"revision number"
+//	@Success	200	{object}	revision.Response
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/categories/{id}/revisions/{rev} [get]
+func (h *CategoryHandler) getRevision(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	number, err := revision.ParseNumber("rev", chi.URLParam(r, "rev"))
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.GetRevision(r.Context(), audit.EntityCategory, id, number)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Compare two revisions of the category
+//
+//	@Summary	Compare two revisions of the category
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string	true	"path param"
+//	@Param		from	query		int		true	"revision number"
+//	@Param		to		query		int		true	"revision number"
+//	@Success	200		{object}	revision.DiffResponse
+//	@Failure	400		{object}	status.Problem
+//	@Failure	404		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/categories/{id}/revisions/diff [get]
+func (h *CategoryHandler) diffRevisions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	from, to, err := revision.ParseDiffRequest(r)
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.DiffRevisions(r.Context(), audit.EntityCategory, id, from, to)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Bring back the field values of a revision as a new revision
+//
+//	@Summary	Bring back the field values of a revision as a new revision
+//	@Tags		categories
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Param		rev	path		int		true	"revision number"
+//	@Success	200	{object}	category.Response
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/categories/{id}/revisions/{rev}/restore [post]
+func (h *CategoryHandler) restoreRevision(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	number, err := revision.ParseNumber("rev", chi.URLParam(r, "rev"))
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.RestoreCategory(r.Context(), id, number)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
 }
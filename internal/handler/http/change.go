@@ -0,0 +1,57 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/domain/change"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+)
+
+type ChangeHandler struct {
+	Service *service.Service
+}
+
+func NewChangeHandler(s *service.Service) *ChangeHandler {
+	return &ChangeHandler{Service: s}
+}
+
+func (h *ChangeHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+
+	return r
+}
+
+// Products and categories changed since a sync token
+//
+//	@Summary		Products and categories changed since a sync token
+//	@Description	Start without a token to receive the whole catalog, then pass the next token of every page back as since.
+//	@Description	Deleted entities come as tombstones with the delete operation and no data.
+//	@Tags			changes
+//	@Accept			json
+//	@Produce		json
+//	@Param			since	query		string	false	"sync token of the last page"
+//	@Param			limit	query		int		false	"maximal number of events per page, 500 by default"
+//	@Success		200		{object}	change.Response
+//	@Failure		400		{object}	status.Problem
+//	@Failure		500		{object}	status.Problem
+//	@Router			/changes [get]
+func (h *ChangeHandler) list(w http.ResponseWriter, r *http.Request) {
+	req, err := change.ParseRequest(r)
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.ListChanges(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
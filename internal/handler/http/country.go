@@ -0,0 +1,37 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/service"
+	"product/pkg/server/status"
+)
+
+type CountryHandler struct {
+	Service *service.Service
+}
+
+func NewCountryHandler(s *service.Service) *CountryHandler {
+	return &CountryHandler{Service: s}
+}
+
+func (h *CountryHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+
+	return r
+}
+
+// List of ISO 3166-1 countries accepted by the service
+//
+//	@Summary	List of ISO 3166-1 countries accepted by the service
+//	@Tags		countries
+//	@Accept		json
+//	@Produce	json
+//	@Success	200			{array}		country.Country
+//	@Router		/countries 	[get]
+func (h *CountryHandler) list(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, status.OK(h.Service.ListCountries()))
+}
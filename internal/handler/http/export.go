@@ -0,0 +1,106 @@
+package http
+
+import (
+	"compress/gzip"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"product/internal/domain/product"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/log"
+	"product/pkg/server/status"
+)
+
+type ExportHandler struct {
+	Service *service.Service
+}
+
+func NewExportHandler(s *service.Service) *ExportHandler {
+	return &ExportHandler{Service: s}
+}
+
+func (h *ExportHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/products", h.products)
+	r.Post("/products", h.queueProducts)
+
+	return r
+}
+
+// Export the product catalog
+//
+//	@Summary	Export the product catalog
+//	@Tags		exports
+//	@Produce	text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/gzip
+//	@Param		format				query	string	false	"csv, jsonl or xlsx"
+//	@Param		gzip				query	bool	false	"compress the file with gzip"
+//	@Param		cost_gte			query	int		false	"minimal cost"
+//	@Param		cost_lte			query	int		false	"maximal cost"
+//	@Param		search				query	string	false	"name search"
+//	@Param		brand_id			query	string	false	"brand id"
+//	@Param		supplier_id			query	string	false	"supplier id"
+//	@Param		producer_country	query	string	false	"ISO 3166-1 alpha-2 code"
+//	@Success	200
+//	@Failure	400	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/exports/products [get]
+func (h *ExportHandler) products(w http.ResponseWriter, r *http.Request) {
+	req, err := product.ParseExportRequest(r)
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	w.Header().Set("Content-Type", req.ContentType())
+	w.Header().Set("Content-Disposition", `attachment; filename="`+req.Filename()+`"`)
+
+	var out io.Writer = w
+	if req.Gzip {
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+		out = gz
+	}
+
+	// The status line is already sent once rows are streamed,
+	// so a failure half way can only be logged and the response cut short.
+	if err = h.Service.ExportProducts(r.Context(), req, out); err != nil {
+		zap.L().Error("ERR_EXPORT_PRODUCTS", append(log.TraceFields(r.Context()), zap.Error(err))...)
+	}
+}
+
+// Export the product catalog in the background
+//
+//	@Summary	Export the product catalog in the background
+//	@Tags		exports
+//	@Produce	json
+//	@Param		format				query		string	false	"csv, jsonl or xlsx"
+//	@Param		gzip				query		bool	false	"compress the file with gzip"
+//	@Param		cost_gte			query		int		false	"minimal cost"
+//	@Param		cost_lte			query		int		false	"maximal cost"
+//	@Param		search				query		string	false	"name search"
+//	@Param		brand_id			query		string	false	"brand id"
+//	@Param		supplier_id			query		string	false	"supplier id"
+//	@Param		producer_country	query		string	false	"ISO 3166-1 alpha-2 code"
+//	@Success	200					{object}	job.Response
+//	@Failure	400					{object}	status.Problem
+//	@Failure	500					{object}	status.Problem
+//	@Router		/exports/products [post]
+func (h *ExportHandler) queueProducts(w http.ResponseWriter, r *http.Request) {
+	req, err := product.ParseExportRequest(r)
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.QueueExport(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
@@ -0,0 +1,110 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/domain/imports"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+)
+
+type ImportHandler struct {
+	Service *service.Service
+}
+
+func NewImportHandler(s *service.Service) *ImportHandler {
+	return &ImportHandler{Service: s}
+}
+
+func (h *ImportHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Post("/resume", h.resume)
+	})
+
+	return r
+}
+
+// Import products from a CSV or XLSX file in the background
+//
+//	@Summary	Import products from a CSV or XLSX file in the background
+//	@Tags		imports
+//	@Accept		mpfd
+//	@Produce	json
+//	@Param		file		formData	file	true	"csv or xlsx file"
+//	@Param		format		formData	string	false	"csv or xlsx, guessed from the file name by default"
+//	@Param		mapping		formData	string	false	"JSON object of product field to column header"
+//	@Param		dry_run		formData	bool	false	"validate without writing"
+//	@Param		batch_size	formData	int		false	"rows per transaction"
+//	@Success	200			{object}	imports.Response
+//	@Failure	400			{object}	status.Problem
+//	@Failure	500			{object}	status.Problem
+//	@Router		/imports [post]
+func (h *ImportHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := imports.Request{}
+	if err := req.Bind(r); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.AddImport(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Read the import report
+//
+//	@Summary	Read the import report
+//	@Tags		imports
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	imports.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/imports/{id} [get]
+func (h *ImportHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.GetImport(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Resume an interrupted import
+//
+//	@Summary	Resume an interrupted import
+//	@Tags		imports
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	imports.Response
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/imports/{id}/resume [post]
+func (h *ImportHandler) resume(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.ResumeImport(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
@@ -0,0 +1,100 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/service"
+	"product/pkg/server/status"
+)
+
+type JobHandler struct {
+	Service *service.Service
+}
+
+func NewJobHandler(s *service.Service) *JobHandler {
+	return &JobHandler{Service: s}
+}
+
+func (h *JobHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Post("/cancel", h.cancel)
+		r.Get("/result", h.result)
+	})
+
+	return r
+}
+
+// Read the status and progress of a background job
+//
+//	@Summary	Read the status and progress of a background job
+//	@Tags		jobs
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	job.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/jobs/{id} [get]
+func (h *JobHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.GetJob(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Cancel a background job
+//
+//	@Summary	Cancel a background job
+//	@Tags		jobs
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	job.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/jobs/{id}/cancel [post]
+func (h *JobHandler) cancel(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.CancelJob(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Download the file written by a finished export job
+//
+//	@Summary	Download the file written by a finished export job
+//	@Tags		jobs
+//	@Produce	text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/gzip
+//	@Param		id	path	string	true	"path param"
+//	@Success	200
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/jobs/{id}/result [get]
+func (h *JobHandler) result(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	content, res, err := h.Service.ExportFile(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", res.ContentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+res.Filename+`"`)
+	w.Write(content)
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5/middleware"
+	"net/http"
+	authn "product/internal/auth"
+	"product/internal/domain/audit"
+	"product/internal/domain/auth"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+	"product/pkg/store"
+	"strings"
+)
+
+// ActorHeader names the caller of a request in the audit log when
+// authentication is disabled. Authenticated requests are attributed to the
+// subject of their token instead.
+const ActorHeader = "X-Actor"
+
+// APIKeyHeader carries the API key of a kiosk device.
+const APIKeyHeader = "X-API-Key"
+
+var (
+	ErrUnauthorized = apperror.New(apperror.Unauthorized, "auth: a valid bearer token or API key is required")
+	ErrForbidden    = apperror.New(apperror.Forbidden, "auth: the caller lacks the role this request needs")
+)
+
+// ReadYourWrites starts a store session for every request, so once the
+// request writes, its reads go to the primary rather than to a replica that
+// may not have the write yet.
+func ReadYourWrites(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(store.WithSession(r.Context())))
+	})
+}
+
+// AuditMetadata attaches the caller and the request ID to the request context,
+// so the changes the request makes are attributed to them.
+func AuditMetadata(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actor := r.Header.Get(ActorHeader)
+		if identity, ok := auth.FromContext(r.Context()); ok {
+			actor = identity.Subject
+		}
+
+		ctx := audit.WithMetadata(r.Context(), audit.Metadata{
+			Actor:     actor,
+			RequestID: middleware.GetReqID(r.Context()),
+		})
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// KeyAuthenticator resolves a device API key to the identity of the device.
+type KeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (auth.Identity, error)
+}
+
+// Authenticate rejects requests without a valid API key or bearer token and
+// attaches the identity of the caller to the request context otherwise. A
+// request carrying an API key is judged by the key alone.
+func Authenticate(verifier *authn.Verifier, keys KeyAuthenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get(APIKeyHeader); key != "" {
+				identity, err := keys.AuthenticateAPIKey(r.Context(), key)
+				if err != nil {
+					unauthorized(w, r, err)
+					return
+				}
+
+				next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+				return
+			}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" {
+				unauthorized(w, r, ErrUnauthorized)
+				return
+			}
+
+			identity, err := verifier.Verify(strings.TrimSpace(token))
+			if err != nil {
+				unauthorized(w, r, apperror.Wrap(apperror.Unauthorized, err))
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+		})
+	}
+}
+
+// Authorize lets safe requests through for callers holding one of readers and
+// the other ones for callers holding one of writers. Admins pass either way.
+func Authorize(readers, writers []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			roles := writers
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				roles = readers
+			}
+
+			identity, ok := auth.FromContext(r.Context())
+			if !ok {
+				unauthorized(w, r, ErrUnauthorized)
+				return
+			}
+
+			if !identity.HasRole(roles...) {
+				status.Error(w, r, ErrForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// unauthorized writes err, asking for credentials when it is about them.
+func unauthorized(w http.ResponseWriter, r *http.Request, err error) {
+	if apperror.Is(err, apperror.Unauthorized) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	}
+	status.Error(w, r, err)
+}
@@ -0,0 +1,63 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"product/internal/domain/auth"
+	"product/pkg/apperror"
+)
+
+type keyAuthenticator map[string]auth.Identity
+
+func (k keyAuthenticator) AuthenticateAPIKey(ctx context.Context, key string) (auth.Identity, error) {
+	identity, ok := k[key]
+	if !ok {
+		return identity, apperror.New(apperror.Unauthorized, "apikey: invalid or expired key")
+	}
+	return identity, nil
+}
+
+func TestAPIKeyAuthorization(t *testing.T) {
+	keys := keyAuthenticator{
+		"kiosk":   {Subject: "apikey:1", Roles: []string{auth.RoleKioskReadOnly}},
+		"manager": {Subject: "apikey:2", Roles: []string{auth.RoleCatalogManager}},
+	}
+	readers, writers := []string{auth.RoleKioskReadOnly, auth.RoleCatalogManager}, []string{auth.RoleCatalogManager}
+	handler := Authenticate(nil, keys)(Authorize(readers, writers)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		want   int
+	}{
+		{"read with a read key", http.MethodGet, "kiosk", http.StatusNoContent},
+		{"write with a read key", http.MethodPost, "kiosk", http.StatusForbidden},
+		{"write with a write key", http.MethodPost, "manager", http.StatusNoContent},
+		{"unknown key", http.MethodGet, "stolen", http.StatusUnauthorized},
+		{"no credentials", http.MethodGet, "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/api/v1/products", nil)
+			if tt.key != "" {
+				r.Header.Set(APIKeyHeader, tt.key)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("got status %d, want %d", w.Code, tt.want)
+			}
+			if challenge := w.Header().Get("WWW-Authenticate"); (tt.want == http.StatusUnauthorized) != (challenge != "") {
+				t.Errorf("got WWW-Authenticate %q for status %d", challenge, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/domain/modifier"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+)
+
+type ModifierHandler struct {
+	Service *service.Service
+}
+
+func NewModifierHandler(s *service.Service) *ModifierHandler {
+	return &ModifierHandler{Service: s}
+}
+
+func (h *ModifierHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+	})
+
+	return r
+}
+
+// List of modifier groups from the database
+//
+//	@Summary	List of modifier groups from the database
+//	@Tags		modifiers
+//	@Accept		json
+//	@Produce	json
+//	@Success	200			{array}		modifier.Response
+//	@Failure	500			{object}	status.Problem
+//	@Router		/modifiers 	[get]
+func (h *ModifierHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Service.ListModifierGroups(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Add a new modifier group to the database
+//
+//	@Summary	Add a new modifier group to the database
+//	@Tags		modifiers
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		modifier.Request	true	"body param"
+//	@Success	200		{object}	modifier.Response
+//	@Failure	400		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/modifiers [post]
+func (h *ModifierHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := modifier.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.AddModifierGroup(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Read the modifier group from the database
+//
+//	@Summary	Read the modifier group from the database
+//	@Tags		modifiers
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	modifier.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/modifiers/{id} [get]
+func (h *ModifierHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.GetModifierGroup(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Update the modifier group in the database
+//
+//	@Summary	Update the modifier group in the database
+//	@Tags		modifiers
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string				true	"path param"
+//	@Param		request	body		modifier.Request	true	"body param"
+//	@Success	200		{object}	modifier.Response
+//	@Failure	400		{object}	status.Problem
+//	@Failure	404		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/modifiers/{id} [put]
+func (h *ModifierHandler) update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := modifier.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.UpdateModifierGroup(r.Context(), id, req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Delete the modifier group from the database
+//
+//	@Summary	Delete the modifier group from the database
+//	@Tags		modifiers
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path	string	true	"path param"
+//	@Success	200
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/modifiers/{id} [delete]
+func (h *ModifierHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.Service.DeleteModifierGroup(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
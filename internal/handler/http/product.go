@@ -4,10 +4,13 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"net/http"
+	"product/internal/domain/audit"
+	"product/internal/domain/modifier"
 	"product/internal/domain/product"
+	"product/internal/domain/revision"
 	"product/internal/service"
+	"product/pkg/apperror"
 	"product/pkg/server/status"
-	"product/pkg/store"
 )
 
 type ProductHandler struct {
@@ -23,11 +26,23 @@ func (h *ProductHandler) Routes() chi.Router {
 
 	r.Get("/", h.list)
 	r.Post("/", h.add)
+	r.Post("/batch", h.batch)
+	r.Post("/prices", h.updatePrices)
+	r.Get("/unmatched-references", h.listUnmatched)
 
 	r.Route("/{id}", func(r chi.Router) {
 		r.Get("/", h.get)
 		r.Put("/", h.update)
 		r.Delete("/", h.delete)
+		r.Get("/history", h.history)
+
+		r.Get("/revisions", h.listRevisions)
+		r.Get("/revisions/diff", h.diffRevisions)
+		r.Get("/revisions/{rev}", h.getRevision)
+		r.Post("/revisions/{rev}/restore", h.restoreRevision)
+
+		r.Get("/modifiers", h.listModifiers)
+		r.Put("/modifiers", h.attachModifiers)
 	})
 
 	return r
@@ -39,13 +54,38 @@ func (h *ProductHandler) Routes() chi.Router {
 //	@Tags		products
 //	@Accept		json
 //	@Produce	json
+//	@Param		cost_gte			query		int		false	"minimal cost"
+//	@Param		cost_lte			query		int		false	"maximal cost"
+//	@Param		search				query		string	false	"name search"
+//	@Param		brand_id			query		string	false	"brand id"
+//	@Param		supplier_id			query		string	false	"supplier id"
+//	@Param		producer_country	query		string	false	"ISO 3166-1 alpha-2 code"
 //	@Success	200			{array}		product.Response
-//	@Failure	500			{object}	status.Response
+//	@Failure	500			{object}	status.Problem
 //	@Router		/products 	[get]
 func (h *ProductHandler) list(w http.ResponseWriter, r *http.Request) {
-	res, err := h.Service.ListProduct(r.Context(), r)
+	res, err := h.Service.ListProduct(r.Context(), product.ParseFilters(r))
 	if err != nil {
-		render.JSON(w, r, status.InternalServerError(err))
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// List of legacy brand and country values that could not be mapped
+//
+//	@Summary	List of legacy brand and country values that could not be mapped
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{array}		product.UnmatchedReferenceResponse
+//	@Failure	500	{object}	status.Problem
+//	@Router		/products/unmatched-references [get]
+func (h *ProductHandler) listUnmatched(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Service.ListUnmatchedReferences(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
 		return
 	}
 
@@ -60,19 +100,19 @@ func (h *ProductHandler) list(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Param		request	body		product.Request	true	"body param"
 //	@Success	200		{object}	product.Response
-//	@Failure	400		{object}	status.Response
-//	@Failure	500		{object}	status.Response
+//	@Failure	400		{object}	status.Problem{errors=[]validate.FieldError}
+//	@Failure	500		{object}	status.Problem
 //	@Router		/products [post]
 func (h *ProductHandler) add(w http.ResponseWriter, r *http.Request) {
 	req := product.Request{}
 	if err := render.Bind(r, &req); err != nil {
-		render.JSON(w, r, status.BadRequest(err, req))
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
 		return
 	}
 
 	res, err := h.Service.AddProduct(r.Context(), req)
 	if err != nil {
-		render.JSON(w, r, status.InternalServerError(err))
+		status.Error(w, r, err)
 		return
 	}
 
@@ -87,20 +127,15 @@ func (h *ProductHandler) add(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Param		id	path		int	true	"path param"
 //	@Success	200	{object}	product.Response
-//	@Failure	404	{object}	status.Response
-//	@Failure	500	{object}	status.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
 //	@Router		/products/{id} [get]
 func (h *ProductHandler) get(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	res, err := h.Service.GetProduct(r.Context(), id)
-	if err != nil && err != store.ErrorNotFound {
-		render.JSON(w, r, status.InternalServerError(err))
-		return
-	}
-
-	if err == store.ErrorNotFound {
-		render.JSON(w, r, status.NotFound(err))
+	if err != nil {
+		status.Error(w, r, err)
 		return
 	}
 
@@ -116,27 +151,22 @@ func (h *ProductHandler) get(w http.ResponseWriter, r *http.Request) {
 //	@Param		id		path	int				true	"path param"
 //	@Param		request	body	product.Request	true	"body param"
 //	@Success	200
-//	@Failure	400	{object}	status.Response
-//	@Failure	404	{object}	status.Response
-//	@Failure	500	{object}	status.Response
+//	@Failure	400	{object}	status.Problem{errors=[]validate.FieldError}
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
 //	@Router		/products/{id} [put]
 func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	req := product.Request{}
 	if err := render.Bind(r, &req); err != nil {
-		render.JSON(w, r, status.BadRequest(err, req))
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
 		return
 	}
 
 	res, err := h.Service.UpdateProduct(r.Context(), id, req)
-	if err != nil && err != store.ErrorNotFound {
-		render.JSON(w, r, status.InternalServerError(err))
-		return
-	}
-
-	if err == store.ErrorNotFound {
-		render.JSON(w, r, status.NotFound(err))
+	if err != nil {
+		status.Error(w, r, err)
 		return
 	}
 
@@ -151,20 +181,302 @@ func (h *ProductHandler) update(w http.ResponseWriter, r *http.Request) {
 //	@Produce	json
 //	@Param		id	path	int	true	"path param"
 //	@Success	200
-//	@Failure	404	{object}	status.Response
-//	@Failure	500	{object}	status.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
 //	@Router		/products/{id} [delete]
 func (h *ProductHandler) delete(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
 	err := h.Service.DeleteProduct(r.Context(), id)
-	if err != nil && err != store.ErrorNotFound {
-		render.JSON(w, r, status.InternalServerError(err))
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
+
+// List of modifier groups attached to the product
+//
+//	@Summary	List of modifier groups attached to the product
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{array}		modifier.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/products/{id}/modifiers [get]
+func (h *ProductHandler) listModifiers(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.ListProductModifiers(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Replace the modifier groups attached to the product
+//
+//	@Summary	Replace the modifier groups attached to the product
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string					true	"path param"
+//	@Param		request	body		modifier.AttachRequest	true	"body param"
+//	@Success	200		{array}		modifier.Response
+//	@Failure	400		{object}	status.Problem
+//	@Failure	404		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/products/{id}/modifiers [put]
+func (h *ProductHandler) attachModifiers(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := modifier.AttachRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.AttachProductModifiers(r.Context(), id, req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Validate a modifier configuration and price it
+//
+//	@Summary	Validate a modifier configuration and price it
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string							true	"path param"
+//	@Param		request	body		modifier.ConfigurationRequest	true	"body param"
+//	@Success	200		{object}	modifier.ConfigurationResponse
+//	@Failure	400		{object}	status.Problem{errors=[]validate.FieldError}
+//	@Failure	404		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/products/{id}/configurations/validate [post]
+func (h *ProductHandler) ValidateConfiguration(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := modifier.ConfigurationRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.ValidateConfiguration(r.Context(), id, req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Apply a batch of product creates, updates and deletes
+//
+//	@Summary	Apply a batch of product creates, updates and deletes
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		product.BatchRequest	true	"body param"
+//	@Success	200		{object}	batch.Response
+//	@Failure	400		{object}	status.Problem{errors=[]validate.FieldError}
+//	@Router		/products/batch [post]
+func (h *ProductHandler) batch(w http.ResponseWriter, r *http.Request) {
+	req := product.BatchRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.BatchProducts(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Change the cost of every product matching the filters in the background
+//
+//	@Summary	Change the cost of every product matching the filters in the background
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		product.PriceUpdateRequest	true	"body param"
+//	@Success	200		{object}	job.Response
+//	@Failure	400		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/products/prices [post]
+func (h *ProductHandler) updatePrices(w http.ResponseWriter, r *http.Request) {
+	req := product.PriceUpdateRequest{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
 		return
 	}
 
-	if err == store.ErrorNotFound {
-		render.JSON(w, r, status.NotFound(err))
+	res, err := h.Service.UpdatePrices(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
 		return
 	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// List of changes made to the product, newest first
+//
+//	@Summary	List of changes made to the product, newest first
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string	true	"path param"
+//	@Param		actor	query		string	false	"who made the change"
+//	@Param		from	query		string	false	"RFC 3339 timestamp, inclusive"
+//	@Param		to		query		string	false	"RFC 3339 timestamp, exclusive"
+//	@Param		limit	query		int		false	"maximal number of records, 100 by default"
+//	@Success	200		{array}		audit.Response
+//	@Failure	400		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/products/{id}/history [get]
+func (h *ProductHandler) history(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	filters, err := audit.ParseFilters(r)
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.ListProductHistory(r.Context(), id, filters)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// List of revisions of the product, newest first
+//
+//	@Summary	List of revisions of the product, newest first
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{array}		revision.Response
+//	@Failure	500	{object}	status.Problem
+//	@Router		/products/{id}/revisions [get]
+func (h *ProductHandler) listRevisions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.ListRevisions(r.Context(), audit.EntityProduct, id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Read a revision of the product
+//
+//	@Summary	Read a revision of the product
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Param		rev	path		int		true	"revision number"
+//	@Success	200	{object}	revision.Response
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/products/{id}/revisions/{rev} [get]
+func (h *ProductHandler) getRevision(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	number, err := revision.ParseNumber("rev", chi.URLParam(r, "rev"))
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.GetRevision(r.Context(), audit.EntityProduct, id, number)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Compare two revisions of the product
+//
+//	@Summary	Compare two revisions of the product
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string	true	"path param"
+//	@Param		from	query		int		true	"revision number"
+//	@Param		to		query		int		true	"revision number"
+//	@Success	200		{object}	revision.DiffResponse
+//	@Failure	400		{object}	status.Problem
+//	@Failure	404		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/products/{id}/revisions/diff [get]
+func (h *ProductHandler) diffRevisions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	from, to, err := revision.ParseDiffRequest(r)
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.DiffRevisions(r.Context(), audit.EntityProduct, id, from, to)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Bring back the field values of a revision as a new revision
+//
+//	@Summary	Bring back the field values of a revision as a new revision
+//	@Tags		products
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Param		rev	path		int		true	"revision number"
+//	@Success	200	{object}	product.Response
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/products/{id}/revisions/{rev}/restore [post]
+func (h *ProductHandler) restoreRevision(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	number, err := revision.ParseNumber("rev", chi.URLParam(r, "rev"))
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.RestoreProduct(r.Context(), id, number)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
 }
@@ -0,0 +1,143 @@
+package http
+
+import (
+	"bytes"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/domain/snapshot"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+	"strconv"
+	"time"
+)
+
+const (
+	SnapshotVersionHeader     = "X-Snapshot-Version"
+	SnapshotBaseVersionHeader = "X-Snapshot-Base-Version"
+	SnapshotHashHeader        = "X-Snapshot-Hash"
+	SyncTokenHeader           = "X-Sync-Token"
+)
+
+type SnapshotHandler struct {
+	Service *service.Service
+}
+
+func NewSnapshotHandler(s *service.Service) *SnapshotHandler {
+	return &SnapshotHandler{Service: s}
+}
+
+func (h *SnapshotHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.generate)
+	r.Get("/latest", h.latest)
+	r.Get("/latest/diff", h.diff)
+
+	return r
+}
+
+// List of catalog snapshots, newest first
+//
+//	@Summary	List of catalog snapshots, newest first
+//	@Tags		snapshots
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{array}		snapshot.Response
+//	@Failure	500	{object}	status.Problem
+//	@Router		/snapshots [get]
+func (h *SnapshotHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Service.ListSnapshots(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Generate a catalog snapshot in the background
+//
+//	@Summary	Generate a catalog snapshot in the background
+//	@Tags		snapshots
+//	@Accept		json
+//	@Produce	json
+//	@Success	200	{object}	job.Response
+//	@Failure	500	{object}	status.Problem
+//	@Router		/snapshots [post]
+func (h *SnapshotHandler) generate(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Service.QueueSnapshot(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Download the latest catalog snapshot
+//
+//	@Summary		Download the latest catalog snapshot
+//	@Description	The bundle is gzipped JSON. X-Snapshot-Hash is the SHA-256 of the uncompressed bundle and also its ETag,
+//	@Description	X-Sync-Token the change feed position to continue from.
+//	@Tags			snapshots
+//	@Produce		application/gzip
+//	@Success		200
+//	@Success		304
+//	@Failure		400	{object}	status.Problem
+//	@Failure		500	{object}	status.Problem
+//	@Router			/snapshots/latest [get]
+func (h *SnapshotHandler) latest(w http.ResponseWriter, r *http.Request) {
+	bundle, res, err := h.Service.LatestSnapshot(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	setSnapshotHeaders(w, res)
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="catalog-`+strconv.FormatInt(res.Version, 10)+`.json.gz"`)
+	http.ServeContent(w, r, "", res.CreatedAt, bytes.NewReader(bundle))
+}
+
+// Download a binary delta from a snapshot to the latest one
+//
+//	@Summary		Download a binary delta from a snapshot to the latest one
+//	@Description	Applied to the uncompressed bundle of version from, the delta yields the uncompressed latest bundle.
+//	@Description	A from version that is no longer kept answers 404; download the latest snapshot in full then.
+//	@Tags			snapshots
+//	@Produce		application/octet-stream
+//	@Param			from	query	int	true	"snapshot version the kiosk has"
+//	@Success		200
+//	@Failure		400	{object}	status.Problem
+//	@Failure		404	{object}	status.Problem
+//	@Failure		500	{object}	status.Problem
+//	@Router			/snapshots/latest/diff [get]
+func (h *SnapshotHandler) diff(w http.ResponseWriter, r *http.Request) {
+	from, err := snapshot.ParseVersion("from", r.URL.Query().Get("from"))
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	patch, res, err := h.Service.DiffSnapshot(r.Context(), from)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	setSnapshotHeaders(w, res)
+	w.Header().Set(SnapshotBaseVersionHeader, strconv.FormatInt(from, 10))
+	w.Header().Set("ETag", `"`+strconv.FormatInt(from, 10)+"-"+res.Hash+`"`)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(patch))
+}
+
+func setSnapshotHeaders(w http.ResponseWriter, res snapshot.Response) {
+	w.Header().Set(SnapshotVersionHeader, strconv.FormatInt(res.Version, 10))
+	w.Header().Set(SnapshotHashHeader, "sha256="+res.Hash)
+	w.Header().Set(SyncTokenHeader, res.SyncToken)
+	w.Header().Set("ETag", `"`+res.Hash+`"`)
+}
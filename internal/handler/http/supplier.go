@@ -0,0 +1,153 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/domain/supplier"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+)
+
+type SupplierHandler struct {
+	Service *service.Service
+}
+
+func NewSupplierHandler(s *service.Service) *SupplierHandler {
+	return &SupplierHandler{Service: s}
+}
+
+func (h *SupplierHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+	})
+
+	return r
+}
+
+// List of suppliers from the database
+//
+//	@Summary	List of suppliers from the database
+//	@Tags		suppliers
+//	@Accept		json
+//	@Produce	json
+//	@Success	200				{array}		supplier.Response
+//	@Failure	500				{object}	status.Problem
+//	@Router		/suppliers 	[get]
+func (h *SupplierHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Service.ListSuppliers(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Add a new supplier to the database
+//
+//	@Summary	Add a new supplier to the database
+//	@Tags		suppliers
+//	@Accept		json
+//	@Produce	json
+//	@Param		request	body		supplier.Request	true	"body param"
+//	@Success	200		{object}	supplier.Response
+//	@Failure	400		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/suppliers [post]
+func (h *SupplierHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := supplier.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.AddSupplier(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Read the supplier from the database
+//
+//	@Summary	Read the supplier from the database
+//	@Tags		suppliers
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	supplier.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/suppliers/{id} [get]
+func (h *SupplierHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.GetSupplier(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Update the supplier in the database
+//
+//	@Summary	Update the supplier in the database
+//	@Tags		suppliers
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path	string					true	"path param"
+//	@Param		request	body	supplier.Request	true	"body param"
+//	@Success	200
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/suppliers/{id} [put]
+func (h *SupplierHandler) update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := supplier.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	err := h.Service.UpdateSupplier(r.Context(), id, req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
+
+// Delete the supplier from the database
+//
+//	@Summary	Delete the supplier from the database
+//	@Tags		suppliers
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path	string	true	"path param"
+//	@Success	200
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/suppliers/{id} [delete]
+func (h *SupplierHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.Service.DeleteSupplier(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
@@ -0,0 +1,216 @@
+package http
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"net/http"
+	"product/internal/domain/webhook"
+	"product/internal/service"
+	"product/pkg/apperror"
+	"product/pkg/server/status"
+)
+
+type WebhookHandler struct {
+	Service *service.Service
+}
+
+func NewWebhookHandler(s *service.Service) *WebhookHandler {
+	return &WebhookHandler{Service: s}
+}
+
+func (h *WebhookHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", h.list)
+	r.Post("/", h.add)
+
+	r.Route("/{id}", func(r chi.Router) {
+		r.Get("/", h.get)
+		r.Put("/", h.update)
+		r.Delete("/", h.delete)
+		r.Get("/deliveries", h.deliveries)
+		r.Post("/deliveries/{deliveryID}/redeliver", h.redeliver)
+	})
+
+	return r
+}
+
+// List of webhooks from the database
+//
+//	@Summary	List of webhooks from the database
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Success	200				{array}		webhook.Response
+//	@Failure	500				{object}	status.Problem
+//	@Router		/webhooks 	[get]
+func (h *WebhookHandler) list(w http.ResponseWriter, r *http.Request) {
+	res, err := h.Service.ListWebhooks(r.Context())
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Register a new webhook
+//
+//	@Summary		Register a new webhook
+//	@Description	The secret deliveries are signed with is only returned here.
+//	@Tags			webhooks
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		webhook.Request	true	"body param"
+//	@Success		200		{object}	webhook.Response
+//	@Failure		400		{object}	status.Problem
+//	@Failure		500		{object}	status.Problem
+//	@Router			/webhooks [post]
+func (h *WebhookHandler) add(w http.ResponseWriter, r *http.Request) {
+	req := webhook.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.AddWebhook(r.Context(), req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Read the webhook from the database
+//
+//	@Summary	Read the webhook from the database
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path		string	true	"path param"
+//	@Success	200	{object}	webhook.Response
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/webhooks/{id} [get]
+func (h *WebhookHandler) get(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	res, err := h.Service.GetWebhook(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Update the webhook in the database
+//
+//	@Summary	Update the webhook in the database
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path	string			true	"path param"
+//	@Param		request	body	webhook.Request	true	"body param"
+//	@Success	200
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/webhooks/{id} [put]
+func (h *WebhookHandler) update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	req := webhook.Request{}
+	if err := render.Bind(r, &req); err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	err := h.Service.UpdateWebhook(r.Context(), id, req)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
+
+// Delete the webhook from the database
+//
+//	@Summary	Delete the webhook from the database
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id	path	string	true	"path param"
+//	@Success	200
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/webhooks/{id} [delete]
+func (h *WebhookHandler) delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.Service.DeleteWebhook(r.Context(), id)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
+
+// List the latest deliveries of the webhook
+//
+//	@Summary	List the latest deliveries of the webhook
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id		path		string	true	"path param"
+//	@Param		limit	query		int		false	"at most 1000, 100 by default"
+//	@Success	200		{array}		webhook.DeliveryResponse
+//	@Failure	400		{object}	status.Problem
+//	@Failure	404		{object}	status.Problem
+//	@Failure	500		{object}	status.Problem
+//	@Router		/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) deliveries(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	limit, err := webhook.ParseDeliveryLimit(r)
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	res, err := h.Service.ListWebhookDeliveries(r.Context(), id, limit)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, status.OK(res))
+}
+
+// Send a delivery of the webhook again
+//
+//	@Summary	Send a delivery of the webhook again
+//	@Tags		webhooks
+//	@Accept		json
+//	@Produce	json
+//	@Param		id			path	string	true	"path param"
+//	@Param		deliveryID	path	int		true	"path param"
+//	@Success	200
+//	@Failure	400	{object}	status.Problem
+//	@Failure	404	{object}	status.Problem
+//	@Failure	500	{object}	status.Problem
+//	@Router		/webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) redeliver(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	deliveryID, err := webhook.ParseDeliveryID(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		status.Error(w, r, apperror.Wrap(apperror.Validation, err))
+		return
+	}
+
+	err = h.Service.RedeliverWebhook(r.Context(), id, deliveryID)
+	if err != nil {
+		status.Error(w, r, err)
+		return
+	}
+}
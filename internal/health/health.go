@@ -0,0 +1,155 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+const (
+	defaultTimeout = 2 * time.Second
+
+	StatusPass = "pass"
+	StatusFail = "fail"
+)
+
+// ErrDraining fails readiness once the service is shutting down.
+var ErrDraining = errors.New("health: shutting down")
+
+// Check reports whether a dependency is usable. It must return once ctx is done.
+type Check func(ctx context.Context) error
+
+type check struct {
+	name string
+	fn   Check
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Health and modify it
+type Configuration func(h *Health) error
+
+// Health serves the liveness and readiness probes of the service.
+type Health struct {
+	checks   []check
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// New takes a variable amount of Configuration functions and returns a new Health
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (h *Health, err error) {
+	h = &Health{
+		timeout: defaultTimeout,
+	}
+
+	for _, cfg := range configs {
+		if err = cfg(h); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WithCheck makes readiness depend on fn.
+func WithCheck(name string, fn Check) Configuration {
+	return func(h *Health) error {
+		h.checks = append(h.checks, check{name: name, fn: fn})
+		return nil
+	}
+}
+
+// WithTimeout sets how long every check may take before it is failed.
+func WithTimeout(timeout time.Duration) Configuration {
+	return func(h *Health) error {
+		if timeout > 0 {
+			h.timeout = timeout
+		}
+		return nil
+	}
+}
+
+// Drain makes readiness fail from now on, so load balancers stop sending
+// requests while the ones in flight finish.
+func (h *Health) Drain() {
+	h.draining.Store(true)
+}
+
+// Response is the body of both probes.
+type Response struct {
+	Status string                   `json:"status"`
+	Checks map[string]CheckResponse `json:"checks,omitempty"`
+}
+
+// CheckResponse is the outcome of a single check.
+type CheckResponse struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Live answers as long as the process serves requests. It checks no
+// dependency, a database outage must not get the instance restarted.
+func (h *Health) Live(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, Response{Status: StatusPass})
+}
+
+// Ready runs every check concurrently and fails when any of them does.
+func (h *Health) Ready(w http.ResponseWriter, r *http.Request) {
+	res := h.Check(r.Context())
+
+	if res.Status != StatusPass {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+	render.JSON(w, r, res)
+}
+
+// Check runs every check and reports their outcome.
+func (h *Health) Check(ctx context.Context) (res Response) {
+	res = Response{Status: StatusPass, Checks: make(map[string]CheckResponse, len(h.checks)+1)}
+
+	if h.draining.Load() {
+		res.Status = StatusFail
+		res.Checks["shutdown"] = CheckResponse{Status: StatusFail, Error: ErrDraining.Error()}
+		return
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := h.run(ctx, c)
+
+			mu.Lock()
+			defer mu.Unlock()
+			res.Checks[c.name] = result
+			if result.Status != StatusPass {
+				res.Status = StatusFail
+			}
+		}()
+	}
+	wg.Wait()
+
+	return
+}
+
+func (h *Health) run(ctx context.Context, c check) (res CheckResponse) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.fn(ctx)
+	res = CheckResponse{Status: StatusPass, LatencyMS: float64(time.Since(start).Microseconds()) / 1000}
+
+	if err != nil {
+		res.Status, res.Error = StatusFail, err.Error()
+	}
+
+	return
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// probe calls handler and returns the status code and body of the response.
+func probe(t *testing.T, handler http.HandlerFunc) (int, Response) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var res Response
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	return w.Code, res
+}
+
+func pass(ctx context.Context) error {
+	return nil
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  Check
+		code   int
+		status string
+		err    string
+	}{
+		{"every check passes", pass, http.StatusOK, StatusPass, ""},
+		{"a check fails", func(ctx context.Context) error { return errors.New("connection refused") },
+			http.StatusServiceUnavailable, StatusFail, "connection refused"},
+		{"a check hangs", func(ctx context.Context) error { <-ctx.Done(); return ctx.Err() },
+			http.StatusServiceUnavailable, StatusFail, context.DeadlineExceeded.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := New(WithCheck("postgres", pass), WithCheck("cache", tt.check), WithTimeout(10*time.Millisecond))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			code, res := probe(t, h.Ready)
+			if code != tt.code || res.Status != tt.status {
+				t.Errorf("got %d %s, want %d %s", code, res.Status, tt.code, tt.status)
+			}
+			if res.Checks["postgres"].Status != StatusPass {
+				t.Errorf("the passing check is reported as %s", res.Checks["postgres"].Status)
+			}
+			if got := res.Checks["cache"].Error; got != tt.err {
+				t.Errorf("got error %q, want %q", got, tt.err)
+			}
+		})
+	}
+}
+
+func TestDrain(t *testing.T) {
+	h, err := New(WithCheck("postgres", pass))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.Drain()
+
+	if code, res := probe(t, h.Ready); code != http.StatusServiceUnavailable || res.Checks["shutdown"].Error != ErrDraining.Error() {
+		t.Errorf("got %d with %v while draining, want %d", code, res.Checks, http.StatusServiceUnavailable)
+	}
+	if code, res := probe(t, h.Live); code != http.StatusOK || res.Status != StatusPass {
+		t.Errorf("got liveness %d %s while draining, want it to pass", code, res.Status)
+	}
+}
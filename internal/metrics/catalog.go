@@ -0,0 +1,48 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// catalogTimeout bounds how long a scrape waits for the catalog to be counted.
+const catalogTimeout = 5 * time.Second
+
+// CatalogCounter counts the products and categories in the catalog.
+type CatalogCounter func(ctx context.Context) (products, categories int, err error)
+
+type catalogCollector struct {
+	counter    CatalogCounter
+	products   *prometheus.Desc
+	categories *prometheus.Desc
+}
+
+func newCatalogCollector(counter CatalogCounter) *catalogCollector {
+	return &catalogCollector{
+		counter:    counter,
+		products:   prometheus.NewDesc(namespace+"_products", "Products in the catalog.", nil, nil),
+		categories: prometheus.NewDesc(namespace+"_categories", "Categories in the catalog.", nil, nil),
+	}
+}
+
+func (c *catalogCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.products
+	ch <- c.categories
+}
+
+func (c *catalogCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogTimeout)
+	defer cancel()
+
+	products, categories, err := c.counter(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.products, err)
+		ch <- prometheus.NewInvalidMetric(c.categories, err)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(c.products, prometheus.GaugeValue, float64(products))
+	ch <- prometheus.MustNewConstMetric(c.categories, prometheus.GaugeValue, float64(categories))
+}
@@ -0,0 +1,124 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"product/pkg/server/router"
+)
+
+const namespace = "catalog"
+
+// Configuration is an alias for a function that will take in a pointer to a Metrics and modify it
+type Configuration func(m *Metrics) error
+
+// Metrics holds the collectors of the service and the registry serving them.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+	queries  *prometheus.HistogramVec
+	failures *prometheus.CounterVec
+}
+
+// New takes a variable amount of Configuration functions and returns a new Metrics
+// Each Configuration will be called in the order they are passed in
+func New(configs ...Configuration) (m *Metrics, err error) {
+	m = &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route and status code.",
+		}, []string{"method", "route", "status"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Time spent serving HTTP requests by route.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Time spent on database queries by the repository method running them.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"query"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "db_query_errors_total",
+			Help:      "Database queries that failed, by the repository method running them.",
+		}, []string{"query"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.latency, m.queries, m.failures)
+
+	for _, cfg := range configs {
+		if err = cfg(m); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WithDatabase exposes the connection pool statistics of db.
+func WithDatabase(db *sql.DB, name string) Configuration {
+	return func(m *Metrics) error {
+		if db == nil {
+			return nil
+		}
+		return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+	}
+}
+
+// WithCatalog exposes the size of the catalog, counted on every scrape.
+func WithCatalog(counter CatalogCounter) Configuration {
+	return func(m *Metrics) error {
+		return m.registry.Register(newCatalogCollector(counter))
+	}
+}
+
+// Handler serves the metrics in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Middleware records the latency and status code of every request under the
+// chi pattern of its route, so path parameters do not split the series.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := router.Pattern(r)
+		code := ww.Status()
+		if code == 0 {
+			code = http.StatusOK
+		}
+
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(code)).Inc()
+		m.latency.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
+
+// ObserveQuery records a database query, see store.ObserveQueries.
+func (m *Metrics) ObserveQuery(name string, duration time.Duration, err error) {
+	m.queries.WithLabelValues(name).Observe(duration.Seconds())
+	if err != nil && err != sql.ErrNoRows && err != context.Canceled {
+		m.failures.WithLabelValues(name).Inc()
+	}
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// scrape returns the metrics m serves.
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
+
+// expect fails t for every line missing from the scraped metrics.
+func expect(t *testing.T, metrics string, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if !strings.Contains(metrics, line+"\n") {
+			t.Errorf("missing %s", line)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	m, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := chi.NewRouter()
+	r.Use(m.Middleware)
+	r.Get("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if chi.URLParam(r, "id") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	for _, path := range []string{"/products/1", "/products/2", "/products/missing", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expect(t, scrape(t, m),
+		`catalog_http_requests_total{method="GET",route="/products/{id}",status="200"} 2`,
+		`catalog_http_requests_total{method="GET",route="/products/{id}",status="404"} 1`,
+		`catalog_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`catalog_http_request_duration_seconds_count{method="GET",route="/products/{id}"} 3`)
+}
+
+func TestObserveQuery(t *testing.T) {
+	m, err := New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.ObserveQuery("ProductRepository.Get", time.Millisecond, nil)
+	m.ObserveQuery("ProductRepository.Get", time.Millisecond, sql.ErrNoRows)
+	m.ObserveQuery("ProductRepository.Get", time.Millisecond, context.Canceled)
+	m.ObserveQuery("ProductRepository.Get", time.Millisecond, errors.New("connection refused"))
+
+	expect(t, scrape(t, m),
+		`catalog_db_query_duration_seconds_count{query="ProductRepository.Get"} 4`,
+		`catalog_db_query_errors_total{query="ProductRepository.Get"} 1`)
+}
+
+func TestWithCatalog(t *testing.T) {
+	m, err := New(WithCatalog(func(ctx context.Context) (int, int, error) {
+		return 120, 7, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect(t, scrape(t, m), "catalog_products 120", "catalog_categories 7")
+}
@@ -0,0 +1,67 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"product/internal/domain/event"
+)
+
+const natsFlushTimeout = 5 * time.Second
+
+// NATSPublisher publishes every event to the subject "<prefix>.<event type>",
+// e.g. catalog.product.price_changed. The event ID is sent as Nats-Msg-Id, so
+// a JetStream stream on those subjects drops the duplicates of a redelivery.
+type NATSPublisher struct {
+	conn   *nats.Conn
+	prefix string
+}
+
+func NewNATSPublisher(url, prefix string) (p *NATSPublisher, err error) {
+	conn, err := nats.Connect(url, nats.Name("product-service"))
+	if err != nil {
+		return
+	}
+	p = &NATSPublisher{conn: conn, prefix: prefix}
+
+	return
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, message event.Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(p.prefix + "." + message.Type)
+	msg.Header.Set(nats.MsgIdHdr, strconv.FormatInt(message.ID, 10))
+	msg.Data = data
+
+	if err = p.conn.PublishMsg(msg); err != nil {
+		return err
+	}
+
+	// A plain publish only reaches the client buffer; flushing waits for the
+	// server to acknowledge it.
+	ctx, cancel := context.WithTimeout(ctx, natsFlushTimeout)
+	defer cancel()
+
+	return p.conn.FlushWithContext(ctx)
+}
+
+// Ping checks that the connection to NATS is up and the server answers.
+func (p *NATSPublisher) Ping(ctx context.Context) error {
+	if !p.conn.IsConnected() {
+		return fmt.Errorf("nats: connection is %s", p.conn.Status())
+	}
+	return p.conn.FlushWithContext(ctx)
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
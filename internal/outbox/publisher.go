@@ -0,0 +1,115 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"product/internal/domain/event"
+)
+
+// Publisher delivers events to their subscribers. Publish returns only once
+// the event is handed over for good; an error makes the relay try again later.
+type Publisher interface {
+	Publish(ctx context.Context, message event.Message) error
+	Close() error
+}
+
+// Pinger is implemented by publishers that depend on a remote service, so
+// readiness can check it.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// LogPublisher writes every event to the log. It is meant for local development.
+type LogPublisher struct {
+	logger *zap.Logger
+}
+
+func NewLogPublisher(logger *zap.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, message event.Message) error {
+	p.logger.Info("event published",
+		zap.Int64("id", message.ID),
+		zap.String("type", message.Type),
+		zap.String("aggregate_id", message.AggregateID))
+	return nil
+}
+
+func (p *LogPublisher) Close() error {
+	return nil
+}
+
+// FilePublisher appends every event to a file as a line of JSON.
+type FilePublisher struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func NewFilePublisher(path string) (p *FilePublisher, err error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return
+	}
+	p = &FilePublisher{file: file}
+
+	return
+}
+
+func (p *FilePublisher) Publish(ctx context.Context, message event.Message) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err = p.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return p.file.Sync()
+}
+
+func (p *FilePublisher) Close() error {
+	return p.file.Close()
+}
+
+// MultiPublisher hands every event to several publishers in turn. A failing
+// publisher makes the event go out again to all of them.
+type MultiPublisher []Publisher
+
+func (p MultiPublisher) Publish(ctx context.Context, message event.Message) error {
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p MultiPublisher) Close() error {
+	errs := make([]error, 0, len(p))
+	for _, publisher := range p {
+		errs = append(errs, publisher.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Ping checks every publisher that implements Pinger.
+func (p MultiPublisher) Ping(ctx context.Context) error {
+	for _, publisher := range p {
+		if pinger, ok := publisher.(Pinger); ok {
+			if err := pinger.Ping(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package outbox
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"product/internal/domain/event"
+	"product/pkg/store"
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultPollInterval = time.Second
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Relay and modify it
+type Configuration func(r *Relay) error
+
+// Relay moves events from the outbox to a Publisher. An event is marked as
+// published only after the publisher accepted it, so a crash in between
+// delivers it again: delivery is at least once. Events are published in the
+// order they were written and a failure holds back the ones after it. When
+// several instances run a relay, one at a time publishes, see
+// event.Repository.SelectPending.
+type Relay struct {
+	repository   event.Repository
+	transactor   store.Transactor
+	publisher    Publisher
+	batchSize    int
+	pollInterval time.Duration
+	logger       *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New takes a variable amount of Configuration functions and returns a new Relay
+// Each Configuration will be called in the order they are passed in
+func New(repository event.Repository, transactor store.Transactor, publisher Publisher, configs ...Configuration) (r *Relay, err error) {
+	r = &Relay{
+		repository:   repository,
+		transactor:   transactor,
+		publisher:    publisher,
+		batchSize:    defaultBatchSize,
+		pollInterval: defaultPollInterval,
+		logger:       zap.NewNop(),
+	}
+
+	for _, cfg := range configs {
+		if err = cfg(r); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WithBatchSize sets how many events are published per transaction.
+func WithBatchSize(size int) Configuration {
+	return func(r *Relay) error {
+		if size > 0 {
+			r.batchSize = size
+		}
+		return nil
+	}
+}
+
+// WithPollInterval sets how long the relay waits when the outbox is empty.
+func WithPollInterval(interval time.Duration) Configuration {
+	return func(r *Relay) error {
+		if interval > 0 {
+			r.pollInterval = interval
+		}
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the relay.
+func WithLogger(logger *zap.Logger) Configuration {
+	return func(r *Relay) error {
+		r.logger = logger
+		return nil
+	}
+}
+
+// Start launches the relay in the background.
+func (r *Relay) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.loop(ctx)
+	}()
+}
+
+// Stop waits for the batch in flight and closes the publisher.
+func (r *Relay) Stop(ctx context.Context) error {
+	if r.cancel == nil {
+		return nil
+	}
+	r.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return r.publisher.Close()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Relay) loop(ctx context.Context) {
+	for {
+		published, err := r.Flush(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error("ERR_PUBLISH_EVENTS", zap.Error(err))
+		}
+
+		// A full batch means more events are probably waiting.
+		if err == nil && published == r.batchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.pollInterval):
+		}
+	}
+}
+
+// Flush publishes one batch of pending events and returns how many went out.
+func (r *Relay) Flush(ctx context.Context) (published int, err error) {
+	err = r.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		events, err := r.repository.SelectPending(ctx, r.batchSize)
+		if err != nil {
+			return
+		}
+
+		ids := make([]int64, 0, len(events))
+		defer func() {
+			if markErr := r.repository.MarkPublished(ctx, ids); err == nil {
+				err = markErr
+			}
+			published = len(ids)
+		}()
+
+		for _, data := range events {
+			message, err := event.ParseToMessage(data)
+			if err != nil {
+				// A malformed event is recorded like a failed publish, so the
+				// events published before it are committed rather than sent again.
+				r.logger.Error("ERR_PARSE_EVENT", zap.Int64("id", data.ID), zap.Error(err))
+				return r.repository.MarkFailed(ctx, data.ID, err.Error())
+			}
+
+			if err = r.publisher.Publish(ctx, message); err != nil {
+				r.logger.Warn("event not published", zap.Int64("id", data.ID), zap.Error(err))
+				// The failure is recorded and committed with the events published
+				// before it; the rest of the batch waits for the next round.
+				return r.repository.MarkFailed(ctx, data.ID, err.Error())
+			}
+			ids = append(ids, data.ID)
+		}
+
+		return
+	})
+
+	return
+}
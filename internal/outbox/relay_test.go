@@ -0,0 +1,195 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"product/internal/domain/event"
+	"product/internal/repository/memory"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// fakePublisher records the events handed to it and fails the ones fail
+// says so. Before acking, it checks that the event is still pending, that
+// is, not marked as published ahead of the ack.
+type fakePublisher struct {
+	t         *testing.T
+	events    event.Repository
+	fail      func(id int64) error
+	attempted []int64
+	acked     []int64
+}
+
+func (p *fakePublisher) Publish(ctx context.Context, message event.Message) error {
+	p.attempted = append(p.attempted, message.ID)
+
+	pending, err := p.events.SelectPending(ctx, 100)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(pending, func(data event.Entity) bool { return data.ID == message.ID }) {
+		p.t.Errorf("event %d was marked as published before it was acked", message.ID)
+	}
+
+	if p.fail != nil {
+		if err = p.fail(message.ID); err != nil {
+			return err
+		}
+	}
+	p.acked = append(p.acked, message.ID)
+	return nil
+}
+
+func (p *fakePublisher) Close() error {
+	return nil
+}
+
+// newRelay returns a relay over an outbox holding count events.
+func newRelay(t *testing.T, count int, fail func(id int64) error) (*Relay, *fakePublisher, event.Repository) {
+	t.Helper()
+
+	db := memory.NewStore()
+	events := memory.NewEventRepository(db)
+	for range count {
+		_, err := events.Create(context.Background(), event.Entity{
+			Type:          ptr("product.updated"),
+			AggregateType: ptr("product"),
+			AggregateID:   ptr("p1"),
+			Payload:       ptr(`{"actor":"test","data":{}}`),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	publisher := &fakePublisher{t: t, events: events, fail: fail}
+	relay, err := New(events, db, publisher, WithBatchSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return relay, publisher, events
+}
+
+func pendingIDs(t *testing.T, events event.Repository) (ids []int64) {
+	t.Helper()
+	pending, err := events.SelectPending(context.Background(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, data := range pending {
+		ids = append(ids, data.ID)
+	}
+	return
+}
+
+func flush(t *testing.T, relay *Relay, want int) {
+	t.Helper()
+	published, err := relay.Flush(context.Background())
+	if err != nil || published != want {
+		t.Fatalf("Flush = %d, %v, want %d published", published, err, want)
+	}
+}
+
+func TestFlushMarksPublishedAfterAck(t *testing.T) {
+	relay, publisher, events := newRelay(t, 3, nil)
+
+	flush(t, relay, 3)
+
+	if !slices.Equal(publisher.acked, []int64{1, 2, 3}) {
+		t.Fatalf("acked %v, want the events in order", publisher.acked)
+	}
+	if ids := pendingIDs(t, events); len(ids) != 0 {
+		t.Fatalf("events %v are still pending", ids)
+	}
+
+	flush(t, relay, 0)
+	if len(publisher.attempted) != 3 {
+		t.Fatalf("published %v, want every event once", publisher.attempted)
+	}
+}
+
+func TestFlushRetriesFailedEventInOrder(t *testing.T) {
+	failures := 1
+	relay, publisher, events := newRelay(t, 3, func(id int64) error {
+		if id == 2 && failures > 0 {
+			failures--
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+
+	// The failure holds back the events after it.
+	flush(t, relay, 1)
+	if ids := pendingIDs(t, events); !slices.Equal(ids, []int64{2, 3}) {
+		t.Fatalf("pending %v after a failure, want [2 3]", ids)
+	}
+
+	pending, _ := events.SelectPending(context.Background(), 1)
+	if data := pending[0]; *data.Attempts != 1 || data.LastError == nil || *data.LastError != "broker unavailable" {
+		t.Fatalf("the failed event has %d attempts and the error %v", *data.Attempts, data.LastError)
+	}
+
+	flush(t, relay, 2)
+	if !slices.Equal(publisher.attempted, []int64{1, 2, 2, 3}) {
+		t.Fatalf("attempted %v, want the failed event retried before the next one", publisher.attempted)
+	}
+	if !slices.Equal(publisher.acked, []int64{1, 2, 3}) {
+		t.Fatalf("acked %v, want the events in order", publisher.acked)
+	}
+	if ids := pendingIDs(t, events); len(ids) != 0 {
+		t.Fatalf("events %v are still pending", ids)
+	}
+}
+
+func TestFlushKeepsEventsPendingWhilePublishFails(t *testing.T) {
+	relay, publisher, events := newRelay(t, 2, func(id int64) error {
+		return errors.New("broker unavailable")
+	})
+
+	for range 3 {
+		flush(t, relay, 0)
+	}
+
+	if !slices.Equal(publisher.attempted, []int64{1, 1, 1}) {
+		t.Fatalf("attempted %v, want only the first event", publisher.attempted)
+	}
+	if ids := pendingIDs(t, events); !slices.Equal(ids, []int64{1, 2}) {
+		t.Fatalf("pending %v, want [1 2]", ids)
+	}
+}
+
+func TestFlushCommitsEventsBeforeMalformedOne(t *testing.T) {
+	relay, publisher, events := newRelay(t, 2, nil)
+	for _, payload := range []string{`not json`, `{"actor":"test","data":{}}`} {
+		_, err := events.Create(context.Background(), event.Entity{
+			Type:          ptr("product.updated"),
+			AggregateType: ptr("product"),
+			AggregateID:   ptr("p1"),
+			Payload:       ptr(payload),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The events before the malformed one are marked, so they never go out twice.
+	flush(t, relay, 2)
+	flush(t, relay, 0)
+	if !slices.Equal(publisher.acked, []int64{1, 2}) {
+		t.Fatalf("acked %v, want [1 2] once", publisher.acked)
+	}
+
+	if ids := pendingIDs(t, events); !slices.Equal(ids, []int64{3, 4}) {
+		t.Fatalf("pending %v, want [3 4]", ids)
+	}
+	pending, _ := events.SelectPending(context.Background(), 1)
+	if data := pending[0]; *data.Attempts != 2 || data.LastError == nil {
+		t.Fatalf("the malformed event has %d attempts and the error %v", *data.Attempts, data.LastError)
+	}
+}
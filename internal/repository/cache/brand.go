@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+
+	"product/internal/domain/brand"
+	"product/internal/domain/product"
+)
+
+// BrandRepository drops the cached products of a brand when it is renamed,
+// since they carry its name. Brands themselves are not cached.
+type BrandRepository struct {
+	brand.Repository
+	products product.Repository
+	cache    *Cache
+}
+
+// NewBrandRepository decorates repository. products is the repository the
+// products of a brand are looked up in, not cached itself.
+func NewBrandRepository(repository brand.Repository, products product.Repository, cache *Cache) *BrandRepository {
+	return &BrandRepository{
+		Repository: repository,
+		products:   products,
+		cache:      cache,
+	}
+}
+
+func (s *BrandRepository) Update(ctx context.Context, id string, data brand.Entity) (err error) {
+	if err = s.Repository.Update(ctx, id, data); err != nil || data.Name == nil {
+		return
+	}
+
+	products, err := s.products.Select(ctx, product.Filters{BrandID: id})
+	if err != nil {
+		return
+	}
+
+	keys := make([]string, 0, len(products))
+	for _, row := range products {
+		keys = append(keys, productKey(row.ID))
+	}
+	s.cache.invalidate(ctx, keys...)
+
+	return
+}
@@ -0,0 +1,99 @@
+// Package cache decorates repositories with a read-through cache. Writes made
+// through a decorator drop the entries they affect once their transaction
+// commits; reads inside a transaction always go to the database.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"product/pkg/store"
+)
+
+// invalidationDelay is when keys are dropped a second time after a write. A
+// read that started before the commit may otherwise store the old value back
+// right after the first drop.
+const invalidationDelay = time.Second
+
+// Cache is shared by the repository decorators.
+type Cache struct {
+	backend Backend
+	ttl     time.Duration
+	group   singleflight.Group
+}
+
+func New(backend Backend, ttl time.Duration) *Cache {
+	return &Cache{backend: backend, ttl: ttl}
+}
+
+func (c *Cache) Close() error {
+	return c.backend.Close()
+}
+
+// pinger is implemented by backends living outside the process.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks that the backend answers. Backends living in the process
+// always do.
+func (c *Cache) Ping(ctx context.Context) error {
+	if backend, ok := c.backend.(pinger); ok {
+		return backend.Ping(ctx)
+	}
+	return nil
+}
+
+// fetch returns the cached value of key, or calls load on a miss and caches
+// its result. Concurrent misses of the same key share a single load.
+func fetch[T any](ctx context.Context, c *Cache, key string, load func(ctx context.Context) (T, error)) (dest T, err error) {
+	if store.InTransaction(ctx) {
+		return load(ctx)
+	}
+
+	if value, ok, _ := c.backend.Get(ctx, key); ok {
+		if err = json.Unmarshal(value, &dest); err == nil {
+			return
+		}
+	}
+
+	shared, err, _ := c.group.Do(key, func() (any, error) {
+		// The load is shared, so it must not fail because the first caller gave up.
+		ctx := context.WithoutCancel(ctx)
+
+		dest, err := load(ctx)
+		if err != nil {
+			return dest, err
+		}
+
+		if value, err := json.Marshal(dest); err == nil {
+			c.backend.Set(ctx, key, value, c.ttl)
+		}
+		return dest, nil
+	})
+	if err != nil {
+		return
+	}
+
+	return shared.(T), nil
+}
+
+// invalidate drops keys once the transaction carried by ctx commits.
+func (c *Cache) invalidate(ctx context.Context, keys ...string) {
+	store.AfterCommit(ctx, func() {
+		c.drop(keys...)
+		time.AfterFunc(invalidationDelay, func() {
+			c.drop(keys...)
+		})
+	})
+}
+
+func (c *Cache) drop(keys ...string) {
+	for _, key := range keys {
+		c.group.Forget(key)
+	}
+	c.backend.Delete(context.Background(), keys...)
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"product/internal/domain/brand"
+	"product/internal/domain/category"
+	"product/internal/domain/product"
+	"product/internal/repository/memory"
+	"product/pkg/apperror"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// fixture is a memory store holding a category, a brand and a product of it,
+// with the repositories of the store both bare and behind a cache.
+type fixture struct {
+	categories *memory.CategoryRepository
+	products   *memory.ProductRepository
+	brands     *memory.BrandRepository
+	cache      *Cache
+}
+
+func newFixture(t *testing.T) *fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	db := memory.NewStore()
+	f := &fixture{
+		categories: memory.NewCategoryRepository(db),
+		products:   memory.NewProductRepository(db),
+		brands:     memory.NewBrandRepository(db),
+		cache:      New(NewLRU(100), time.Minute),
+	}
+
+	if _, err := f.categories.Create(ctx, category.Entity{ID: "c1", Name: ptr("Drinks"), ParentId: ptr("")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.brands.Create(ctx, brand.Entity{ID: "b1", Name: ptr("Acme")}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := f.products.Create(ctx, product.Entity{
+		ID:          "p1",
+		CategoryID:  ptr("c1"),
+		Barcode:     ptr("4600000000001"),
+		Name:        ptr("Cola"),
+		BrandID:     ptr("b1"),
+		Description: ptr(""),
+		Image:       ptr(""),
+		IsWeighted:  ptr(false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return f
+}
+
+func expectName(t *testing.T, products product.Repository, id, want string) {
+	t.Helper()
+	data, err := products.Get(context.Background(), id)
+	if err != nil || *data.Name != want {
+		t.Fatalf("Get(%q) = %v, %v, want the name %q", id, data.Name, err, want)
+	}
+}
+
+func TestProductInvalidatedOnWrite(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	products := NewProductRepository(f.products, f.cache)
+
+	expectName(t, products, "p1", "Cola")
+
+	// Writes that bypass the cache are not seen until the entry goes.
+	if err := f.products.Update(ctx, "p1", product.Entity{Name: ptr("Cola Zero")}); err != nil {
+		t.Fatal(err)
+	}
+	expectName(t, products, "p1", "Cola")
+
+	if err := products.Update(ctx, "p1", product.Entity{Name: ptr("Cola Light")}); err != nil {
+		t.Fatal(err)
+	}
+	expectName(t, products, "p1", "Cola Light")
+
+	if _, err := products.GetByBarcode(ctx, "4600000000001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := products.Delete(ctx, "p1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := products.Get(ctx, "p1"); !apperror.Is(err, apperror.NotFound) {
+		t.Fatalf("Get after Delete = %v, want not found", err)
+	}
+	if _, err := products.GetByBarcode(ctx, "4600000000001"); !apperror.Is(err, apperror.NotFound) {
+		t.Fatalf("GetByBarcode after Delete = %v, want not found", err)
+	}
+}
+
+func TestCategoriesInvalidatedOnWrite(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	categories := NewCategoryRepository(f.categories, f.cache)
+
+	if _, err := categories.Select(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := categories.Create(ctx, category.Entity{ID: "c2", Name: ptr("Food"), ParentId: ptr("")}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := categories.Get(ctx, "c2")
+	if err != nil || *data.Name != "Food" {
+		t.Fatalf("Get after Create = %v, %v", data, err)
+	}
+}
+
+func TestBrandRenameInvalidatesProducts(t *testing.T) {
+	ctx := context.Background()
+	f := newFixture(t)
+	products := NewProductRepository(f.products, f.cache)
+	brands := NewBrandRepository(f.brands, f.products, f.cache)
+
+	if data, err := products.Get(ctx, "p1"); err != nil || *data.BrandName != "Acme" {
+		t.Fatalf("Get = %v, %v, want the brand Acme", data.BrandName, err)
+	}
+
+	if err := brands.Update(ctx, "b1", brand.Entity{Name: ptr("Acme Foods")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := products.Get(ctx, "p1"); err != nil || *data.BrandName != "Acme Foods" {
+		t.Fatalf("Get after the rename = %v, %v, want the brand Acme Foods", data.BrandName, err)
+	}
+}
+
+// slowProducts counts the loads of products and holds them until released.
+type slowProducts struct {
+	product.Repository
+	loads   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *slowProducts) Get(ctx context.Context, id string) (product.Entity, error) {
+	if s.loads.Add(1) == 1 {
+		close(s.started)
+	}
+	<-s.release
+	return s.Repository.Get(ctx, id)
+}
+
+func TestConcurrentMissesShareOneLoad(t *testing.T) {
+	f := newFixture(t)
+	slow := &slowProducts{Repository: f.products, started: make(chan struct{}), release: make(chan struct{})}
+	products := NewProductRepository(slow, f.cache)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			expectName(t, products, "p1", "Cola")
+		})
+	}
+
+	// Give the other readers time to miss and join the first load.
+	<-slow.started
+	time.Sleep(50 * time.Millisecond)
+	close(slow.release)
+	wg.Wait()
+
+	if loads := slow.loads.Load(); loads != 1 {
+		t.Fatalf("%d loads, want 1", loads)
+	}
+
+	expectName(t, products, "p1", "Cola")
+	if loads := slow.loads.Load(); loads != 1 {
+		t.Fatalf("%d loads after a hit, want 1", loads)
+	}
+}
@@ -0,0 +1,82 @@
+package cache
+
+import (
+	"context"
+
+	"product/internal/domain/category"
+	"product/pkg/store"
+)
+
+const categoriesKey = "categories"
+
+// CategoryRepository caches the whole category list, which is small, and
+// answers single categories from it. Any write drops the list.
+type CategoryRepository struct {
+	category.Repository
+	cache *Cache
+}
+
+func NewCategoryRepository(repository category.Repository, cache *Cache) *CategoryRepository {
+	return &CategoryRepository{
+		Repository: repository,
+		cache:      cache,
+	}
+}
+
+func (s *CategoryRepository) Select(ctx context.Context) (dest []category.Entity, err error) {
+	return fetch(ctx, s.cache, categoriesKey, s.Repository.Select)
+}
+
+func (s *CategoryRepository) Get(ctx context.Context, id string) (dest category.Entity, err error) {
+	if store.InTransaction(ctx) {
+		return s.Repository.Get(ctx, id)
+	}
+
+	categories, err := s.Select(ctx)
+	if err != nil {
+		return
+	}
+
+	err = store.ErrorNotFound
+	for _, object := range categories {
+		switch {
+		case object.ID == id:
+			dest.ID, dest.Name, dest.ParentId = object.ID, object.Name, object.ParentId
+			err = nil
+		case object.ParentId != nil && *object.ParentId == id:
+			dest.Child = append(dest.Child, object)
+		}
+	}
+	if err != nil {
+		dest.Child = nil
+	}
+
+	return
+}
+
+func (s *CategoryRepository) Create(ctx context.Context, data category.Entity) (id string, err error) {
+	if id, err = s.Repository.Create(ctx, data); err != nil {
+		return
+	}
+	s.cache.invalidate(ctx, categoriesKey)
+
+	return
+}
+
+func (s *CategoryRepository) Update(ctx context.Context, id string, data category.Entity) (err error) {
+	if err = s.Repository.Update(ctx, id, data); err != nil {
+		return
+	}
+	s.cache.invalidate(ctx, categoriesKey)
+
+	return
+}
+
+func (s *CategoryRepository) Delete(ctx context.Context, id string) (err error) {
+	if err = s.Repository.Delete(ctx, id); err != nil {
+		return
+	}
+	s.cache.invalidate(ctx, categoriesKey)
+
+	return
+}
@@ -0,0 +1,134 @@
+package cache
+
+import (
+	"context"
+
+	"product/internal/domain/product"
+	"product/pkg/apperror"
+	"product/pkg/store"
+)
+
+func productKey(id string) string {
+	return "product:" + id
+}
+
+// barcodeKey maps a barcode to the id of its product, whose data is cached
+// under productKey. Only product ids need dropping on writes that way.
+func barcodeKey(barcode string) string {
+	return "product:barcode:" + barcode
+}
+
+// ProductRepository caches single products, looked up by id or barcode.
+// Lists, streams and counts go straight to the wrapped repository.
+type ProductRepository struct {
+	product.Repository
+	cache *Cache
+}
+
+func NewProductRepository(repository product.Repository, cache *Cache) *ProductRepository {
+	return &ProductRepository{
+		Repository: repository,
+		cache:      cache,
+	}
+}
+
+func (s *ProductRepository) Get(ctx context.Context, id string) (dest product.Entity, err error) {
+	return fetch(ctx, s.cache, productKey(id), func(ctx context.Context) (product.Entity, error) {
+		return s.Repository.Get(ctx, id)
+	})
+}
+
+func (s *ProductRepository) GetByBarcode(ctx context.Context, barcode string) (dest product.Entity, err error) {
+	if store.InTransaction(ctx) {
+		return s.Repository.GetByBarcode(ctx, barcode)
+	}
+
+	id, err := fetch(ctx, s.cache, barcodeKey(barcode), func(ctx context.Context) (string, error) {
+		data, err := s.Repository.GetByBarcode(ctx, barcode)
+		return data.ID, err
+	})
+	if err != nil {
+		return
+	}
+
+	// The barcode may have moved to another product since it was cached.
+	dest, err = s.Get(ctx, id)
+	if err == nil && dest.Barcode != nil && *dest.Barcode == barcode {
+		return
+	}
+	if err != nil && !apperror.Is(err, apperror.NotFound) {
+		return
+	}
+	s.cache.drop(barcodeKey(barcode))
+
+	return s.Repository.GetByBarcode(ctx, barcode)
+}
+
+func (s *ProductRepository) Create(ctx context.Context, data product.Entity) (id string, err error) {
+	if id, err = s.Repository.Create(ctx, data); err != nil {
+		return
+	}
+	s.cache.invalidate(ctx, productKey(id))
+
+	return
+}
+
+func (s *ProductRepository) Update(ctx context.Context, id string, data product.Entity) (err error) {
+	if err = s.Repository.Update(ctx, id, data); err != nil {
+		return
+	}
+	s.cache.invalidate(ctx, productKey(id))
+
+	return
+}
+
+func (s *ProductRepository) Delete(ctx context.Context, id string) (err error) {
+	if err = s.Repository.Delete(ctx, id); err != nil {
+		return
+	}
+	s.cache.invalidate(ctx, productKey(id))
+
+	return
+}
+
+// Upsert matches products by barcode, so the ids of the products it updates
+// are looked up first.
+func (s *ProductRepository) Upsert(ctx context.Context, data []product.Entity) (err error) {
+	barcodes := make([]string, 0, len(data))
+	keys := make([]string, 0, 2*len(data))
+	for _, row := range data {
+		if row.Barcode != nil {
+			barcodes = append(barcodes, *row.Barcode)
+		}
+		keys = append(keys, productKey(row.ID))
+	}
+
+	existing, err := s.Repository.SelectByBarcodes(ctx, barcodes)
+	if err != nil {
+		return
+	}
+	for _, row := range existing {
+		keys = append(keys, productKey(row.ID))
+	}
+
+	if err = s.Repository.Upsert(ctx, data); err != nil {
+		return
+	}
+	s.cache.invalidate(ctx, keys...)
+
+	return
+}
+
+func (s *ProductRepository) AdjustCosts(ctx context.Context, filters product.Filters, percent float64, delta int, afterID string, limit int) (dest []product.CostChange, err error) {
+	if dest, err = s.Repository.AdjustCosts(ctx, filters, percent, delta, afterID, limit); err != nil {
+		return
+	}
+
+	keys := make([]string, 0, len(dest))
+	for _, change := range dest {
+		keys = append(keys, productKey(change.ID))
+	}
+	s.cache.invalidate(ctx, keys...)
+
+	return
+}
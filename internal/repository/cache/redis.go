@@ -0,0 +1,143 @@
+package cache
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationChannel is where a Redis backend announces deleted keys, so
+// that instances keeping a local copy drop it as well.
+const invalidationChannel = "invalidate"
+
+// Redis is a Backend shared by every instance. It takes any go-redis client,
+// so tests can point it at a local stand-in such as miniredis.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.prefix + key
+	}
+	if err := c.client.Del(ctx, prefixed...).Err(); err != nil {
+		return err
+	}
+
+	return c.client.Publish(ctx, c.prefix+invalidationChannel, strings.Join(keys, "\n")).Err()
+}
+
+// Subscribe calls fn with the keys deleted by any instance until ctx is done.
+func (c *Redis) Subscribe(ctx context.Context, fn func(keys []string)) {
+	subscription := c.client.Subscribe(ctx, c.prefix+invalidationChannel)
+	defer subscription.Close()
+
+	messages := subscription.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			fn(strings.Split(message.Payload, "\n"))
+		}
+	}
+}
+
+// Ping checks that Redis answers.
+func (c *Redis) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
+
+// Tiered keeps a short-lived local copy of the values read from Redis. Keys
+// deleted by any instance are dropped from every local copy as well.
+type Tiered struct {
+	local    *LRU
+	remote   *Redis
+	localTTL time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewTiered(local *LRU, remote *Redis, localTTL time.Duration) *Tiered {
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Tiered{local: local, remote: remote, localTTL: localTTL, cancel: cancel}
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		remote.Subscribe(ctx, func(keys []string) {
+			local.Delete(ctx, keys...)
+		})
+	}()
+
+	return c
+}
+
+func (c *Tiered) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	if value, ok, _ := c.local.Get(ctx, key); ok {
+		return value, true, nil
+	}
+
+	value, ok, err := c.remote.Get(ctx, key)
+	if ok {
+		c.local.Set(ctx, key, value, c.localTTL)
+	}
+	return value, ok, err
+}
+
+func (c *Tiered) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.local.Set(ctx, key, value, min(ttl, c.localTTL))
+	return c.remote.Set(ctx, key, value, ttl)
+}
+
+func (c *Tiered) Delete(ctx context.Context, keys ...string) error {
+	c.local.Delete(ctx, keys...)
+	return c.remote.Delete(ctx, keys...)
+}
+
+// Ping checks the shared tier, the local one cannot fail.
+func (c *Tiered) Ping(ctx context.Context) error {
+	return c.remote.Ping(ctx)
+}
+
+func (c *Tiered) Close() error {
+	c.cancel()
+	c.wg.Wait()
+	return c.remote.Close()
+}
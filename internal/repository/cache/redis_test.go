@@ -0,0 +1,108 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+const testPrefix = "test:"
+
+func newRedis(t *testing.T, server *miniredis.Miniredis) *Redis {
+	t.Helper()
+	return NewRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}), testPrefix)
+}
+
+// eventually fails the test unless cond holds within a second.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func expectHit(t *testing.T, backend Backend, key, want string) {
+	t.Helper()
+	value, ok, err := backend.Get(context.Background(), key)
+	if err != nil || !ok || string(value) != want {
+		t.Fatalf("Get(%q) = %q, %v, %v, want %q", key, value, ok, err, want)
+	}
+}
+
+func expectMiss(t *testing.T, backend Backend, key string) {
+	t.Helper()
+	value, ok, err := backend.Get(context.Background(), key)
+	if err != nil || ok {
+		t.Fatalf("Get(%q) = %q, %v, %v, want a miss", key, value, ok, err)
+	}
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	backend := newRedis(t, server)
+	defer backend.Close()
+
+	expectMiss(t, backend, "product:1")
+
+	if err := backend.Set(ctx, "product:1", []byte("cola"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	expectHit(t, backend, "product:1", "cola")
+	if ttl := server.TTL(testPrefix + "product:1"); ttl != time.Minute {
+		t.Fatalf("stored with a TTL of %s, want 1m", ttl)
+	}
+
+	server.FastForward(time.Minute)
+	expectMiss(t, backend, "product:1")
+
+	backend.Set(ctx, "product:1", []byte("cola"), time.Minute)
+	backend.Set(ctx, "product:2", []byte("tea"), time.Minute)
+	if err := backend.Delete(ctx, "product:1", "product:2"); err != nil {
+		t.Fatal(err)
+	}
+	expectMiss(t, backend, "product:1")
+	expectMiss(t, backend, "product:2")
+}
+
+func TestTiered(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+
+	// Two instances sharing Redis, each with a local copy.
+	first := NewTiered(NewLRU(10), newRedis(t, server), time.Minute)
+	defer first.Close()
+	second := NewTiered(NewLRU(10), newRedis(t, server), time.Minute)
+	defer second.Close()
+
+	channel := testPrefix + invalidationChannel
+	eventually(t, "both instances to subscribe", func() bool {
+		return server.PubSubNumSub(channel)[channel] == 2
+	})
+
+	expectMiss(t, second, "product:1")
+
+	if err := first.Set(ctx, "product:1", []byte("cola"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	expectHit(t, second, "product:1", "cola")
+
+	// The local copy answers without Redis.
+	server.Del(testPrefix + "product:1")
+	expectHit(t, second, "product:1", "cola")
+
+	// A delete on one instance drops the local copies of the others.
+	if err := first.Delete(ctx, "product:1"); err != nil {
+		t.Fatal(err)
+	}
+	expectMiss(t, first, "product:1")
+	eventually(t, "the local copy to be dropped", func() bool {
+		_, ok, _ := second.Get(ctx, "product:1")
+		return !ok
+	})
+}
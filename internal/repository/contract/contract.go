@@ -0,0 +1,378 @@
+// Package contract checks that implementations of category.Repository and
+// product.Repository behave alike, so the memory store can stand in for
+// Postgres. The checks write their own rows, named after a random token, and
+// delete them when they are done; run them against a scratch database.
+package contract
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/category"
+	"product/internal/domain/product"
+	"product/pkg/apperror"
+)
+
+// Run checks categories and products against the contract, a subtest of t
+// per behaviour.
+func Run(t *testing.T, categories category.Repository, products product.Repository) {
+	c := &checker{
+		token:      "contract-" + uuid.NewString()[:8],
+		categories: categories,
+		products:   products,
+	}
+	t.Cleanup(c.cleanup)
+
+	t.Run("categories", c.category)
+	t.Run("products", c.product)
+}
+
+type checker struct {
+	token      string
+	categories category.Repository
+	products   product.Repository
+
+	createdCategories []string
+	createdProducts   []string
+}
+
+// expect fails t unless err is of the given kind.
+func expect(t *testing.T, err error, kind apperror.Kind) {
+	t.Helper()
+	if !apperror.Is(err, kind) {
+		t.Errorf("got error %v, want %s", err, kind)
+	}
+}
+
+// must stops t when err is not nil.
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (c *checker) newCategory(t *testing.T, name, parentID string) string {
+	t.Helper()
+
+	id, err := c.categories.Create(context.Background(), category.Entity{ID: uuid.NewString(), Name: &name, ParentId: &parentID})
+	must(t, err)
+	c.createdCategories = append(c.createdCategories, id)
+	return id
+}
+
+func (c *checker) newProduct(name, categoryID string, cost int) product.Entity {
+	barcode, description, image, weighted := uuid.NewString(), "", "", false
+	name = c.token + " " + name
+	return product.Entity{
+		ID:          uuid.NewString(),
+		CategoryID:  &categoryID,
+		Barcode:     &barcode,
+		Name:        &name,
+		Cost:        &cost,
+		Description: &description,
+		Image:       &image,
+		IsWeighted:  &weighted,
+	}
+}
+
+func (c *checker) createProduct(t *testing.T, data product.Entity) {
+	t.Helper()
+
+	id, err := c.products.Create(context.Background(), data)
+	must(t, err)
+	c.createdProducts = append(c.createdProducts, id)
+	if id != data.ID {
+		t.Errorf("create product: got id %q, want %q", id, data.ID)
+	}
+}
+
+// cleanup deletes the rows the checks created, products first since they
+// reference categories.
+func (c *checker) cleanup() {
+	ctx := context.Background()
+	for _, id := range c.createdProducts {
+		c.products.Delete(ctx, id)
+	}
+	for _, id := range slices.Backward(c.createdCategories) {
+		c.categories.Delete(ctx, id)
+	}
+}
+
+func (c *checker) category(t *testing.T) {
+	ctx := context.Background()
+	parentID := c.newCategory(t, c.token+" parent", "")
+	childID := c.newCategory(t, c.token+" child", parentID)
+
+	t.Run("create with a taken id", func(t *testing.T) {
+		_, err := c.categories.Create(ctx, category.Entity{ID: parentID, Name: new(string), ParentId: new(string)})
+		expect(t, err, apperror.Conflict)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		parent, err := c.categories.Get(ctx, parentID)
+		must(t, err)
+		if parent.Name == nil || *parent.Name != c.token+" parent" {
+			t.Errorf("got name %v, want %q", parent.Name, c.token+" parent")
+		}
+		if len(parent.Child) != 1 || parent.Child[0].ID != childID {
+			t.Errorf("got children %v, want [%s]", parent.Child, childID)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		_, err := c.categories.Get(ctx, uuid.NewString())
+		expect(t, err, apperror.NotFound)
+	})
+
+	t.Run("select", func(t *testing.T) {
+		all, err := c.categories.Select(ctx)
+		must(t, err)
+		ids := make([]string, 0, len(all))
+		for _, data := range all {
+			ids = append(ids, data.ID)
+		}
+		if !slices.Contains(ids, parentID) || !slices.Contains(ids, childID) {
+			t.Errorf("created categories are missing")
+		}
+		if !slices.IsSorted(ids) {
+			t.Errorf("not ordered by id")
+		}
+	})
+
+	name := c.token + " renamed"
+	t.Run("update", func(t *testing.T) {
+		must(t, c.categories.Update(ctx, childID, category.Entity{Name: &name}))
+		child, err := c.categories.Get(ctx, childID)
+		must(t, err)
+		if child.Name == nil || *child.Name != name {
+			t.Errorf("got name %v, want %q", child.Name, name)
+		}
+		if child.ParentId == nil || *child.ParentId != parentID {
+			t.Errorf("parent changed to %v", child.ParentId)
+		}
+	})
+
+	t.Run("update missing", func(t *testing.T) {
+		expect(t, c.categories.Update(ctx, uuid.NewString(), category.Entity{Name: &name}), apperror.NotFound)
+	})
+
+	t.Run("update with no field", func(t *testing.T) {
+		must(t, c.categories.Update(ctx, uuid.NewString(), category.Entity{}))
+	})
+
+	t.Run("delete missing", func(t *testing.T) {
+		expect(t, c.categories.Delete(ctx, uuid.NewString()), apperror.NotFound)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		must(t, c.categories.Delete(ctx, childID))
+		_, err := c.categories.Get(ctx, childID)
+		expect(t, err, apperror.NotFound)
+	})
+}
+
+func (c *checker) product(t *testing.T) {
+	ctx := context.Background()
+	categoryID := c.newCategory(t, c.token+" products", "")
+
+	cheap, dear := c.newProduct("cheap", categoryID, 100), c.newProduct("dear", categoryID, 1000)
+	country := ""
+	cheap.ProducerCountry = &country
+	c.createProduct(t, cheap)
+	c.createProduct(t, dear)
+
+	t.Run("get", func(t *testing.T) {
+		got, err := c.products.Get(ctx, cheap.ID)
+		must(t, err)
+		if got.Barcode == nil || *got.Barcode != *cheap.Barcode || got.Cost == nil || *got.Cost != 100 {
+			t.Errorf("got barcode %v and cost %v, want %s and 100", got.Barcode, got.Cost, *cheap.Barcode)
+		}
+		if got.ProducerCountry != nil {
+			t.Errorf("blank producer country stored as %q, want nil", *got.ProducerCountry)
+		}
+	})
+
+	t.Run("get by barcode", func(t *testing.T) {
+		got, err := c.products.GetByBarcode(ctx, *dear.Barcode)
+		must(t, err)
+		if got.ID != dear.ID {
+			t.Errorf("got %q, want %q", got.ID, dear.ID)
+		}
+	})
+
+	t.Run("get missing", func(t *testing.T) {
+		_, err := c.products.Get(ctx, uuid.NewString())
+		expect(t, err, apperror.NotFound)
+		_, err = c.products.GetByBarcode(ctx, uuid.NewString())
+		expect(t, err, apperror.NotFound)
+	})
+
+	t.Run("create with a taken barcode", func(t *testing.T) {
+		taken := c.newProduct("taken barcode", categoryID, 1)
+		taken.Barcode = cheap.Barcode
+		_, err := c.products.Create(ctx, taken)
+		expect(t, err, apperror.Conflict)
+	})
+
+	t.Run("create in a missing category", func(t *testing.T) {
+		_, err := c.products.Create(ctx, c.newProduct("orphan", uuid.NewString(), 1))
+		expect(t, err, apperror.Validation)
+	})
+
+	t.Run("delete category with products", func(t *testing.T) {
+		expect(t, c.categories.Delete(ctx, categoryID), apperror.Conflict)
+	})
+
+	t.Run("filters", func(t *testing.T) { c.filters(t, cheap, dear) })
+	t.Run("writes", func(t *testing.T) { c.writes(t, categoryID, cheap, dear) })
+}
+
+func (c *checker) filters(t *testing.T, cheap, dear product.Entity) {
+	ctx := context.Background()
+	atLeast, atMost := 500, 500
+	cases := []struct {
+		name    string
+		filters product.Filters
+		want    []string
+	}{
+		{"search", product.Filters{Search: c.token}, []string{cheap.ID, dear.ID}},
+		{"search is case-sensitive", product.Filters{Search: strings.ToUpper(c.token)}, nil},
+		{"cost_gte", product.Filters{Search: c.token, CostGTE: &atLeast}, []string{dear.ID}},
+		{"cost_lte", product.Filters{Search: c.token, CostLTE: &atMost}, []string{cheap.ID}},
+		{"missing producer country", product.Filters{Search: c.token, ProducerCountry: "ZZ"}, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := c.products.Select(ctx, tc.filters)
+			must(t, err)
+			slices.Sort(tc.want)
+			if got := ids(data, true); !slices.Equal(got, tc.want) {
+				t.Errorf("select: got %v, want %v", got, tc.want)
+			}
+
+			count, err := c.products.Count(ctx, tc.filters)
+			must(t, err)
+			if count != len(tc.want) {
+				t.Errorf("count: got %d, want %d", count, len(tc.want))
+			}
+		})
+	}
+
+	t.Run("stream", func(t *testing.T) {
+		var streamed []product.Entity
+		err := c.products.Stream(ctx, product.Filters{Search: c.token}, func(data product.Entity) error {
+			streamed = append(streamed, data)
+			return nil
+		})
+		must(t, err)
+		if got := ids(streamed, false); !slices.Equal(got, ids(streamed, true)) || len(got) != 2 {
+			t.Errorf("got %v, want both products ordered by id", got)
+		}
+	})
+
+	t.Run("select by barcodes", func(t *testing.T) {
+		data, err := c.products.SelectByBarcodes(ctx, []string{*dear.Barcode, uuid.NewString()})
+		must(t, err)
+		if got := ids(data, true); !slices.Equal(got, []string{dear.ID}) {
+			t.Errorf("got %v, want [%s]", got, dear.ID)
+		}
+	})
+}
+
+func (c *checker) writes(t *testing.T, categoryID string, cheap, dear product.Entity) {
+	ctx := context.Background()
+	name := c.token + " renamed"
+
+	t.Run("update", func(t *testing.T) {
+		must(t, c.products.Update(ctx, cheap.ID, product.Entity{Name: &name}))
+		got, err := c.products.Get(ctx, cheap.ID)
+		must(t, err)
+		if got.Name == nil || *got.Name != name || got.Cost == nil || *got.Cost != 100 {
+			t.Errorf("got name %v and cost %v, want %q and 100", got.Name, got.Cost, name)
+		}
+	})
+
+	t.Run("update to a taken barcode", func(t *testing.T) {
+		expect(t, c.products.Update(ctx, cheap.ID, product.Entity{Barcode: dear.Barcode}), apperror.Conflict)
+	})
+
+	t.Run("update missing", func(t *testing.T) {
+		expect(t, c.products.Update(ctx, uuid.NewString(), product.Entity{Name: &name}), apperror.NotFound)
+	})
+
+	t.Run("update with no field", func(t *testing.T) {
+		must(t, c.products.Update(ctx, uuid.NewString(), product.Entity{}))
+	})
+
+	// Upserting by barcode keeps the id of the existing product.
+	added := c.newProduct("added", categoryID, 3000)
+	t.Run("upsert", func(t *testing.T) {
+		existing := c.newProduct("upserted", categoryID, 2000)
+		existing.Barcode = dear.Barcode
+		c.createdProducts = append(c.createdProducts, added.ID)
+		must(t, c.products.Upsert(ctx, []product.Entity{existing, added}))
+
+		got, err := c.products.GetByBarcode(ctx, *dear.Barcode)
+		must(t, err)
+		if got.ID != dear.ID || got.Cost == nil || *got.Cost != 2000 {
+			t.Errorf("got id %q and cost %v, want %q and 2000", got.ID, got.Cost, dear.ID)
+		}
+		_, err = c.products.Get(ctx, added.ID)
+		must(t, err)
+	})
+
+	// Products are adjusted in id order, a batch at a time.
+	t.Run("adjust costs", func(t *testing.T) {
+		filters := product.Filters{Search: c.token}
+		before, err := c.products.Select(ctx, filters)
+		must(t, err)
+		slices.SortFunc(before, func(a, b product.Entity) int { return strings.Compare(a.ID, b.ID) })
+
+		var changes []product.CostChange
+		for afterID := ""; ; {
+			batch, err := c.products.AdjustCosts(ctx, filters, 10, -5, afterID, 2)
+			must(t, err)
+			if len(batch) == 0 {
+				break
+			}
+			changes = append(changes, batch...)
+			afterID = batch[len(batch)-1].ID
+		}
+		if len(changes) != len(before) {
+			t.Fatalf("got %d changes, want %d", len(changes), len(before))
+		}
+		for i, change := range changes {
+			want := product.CostChange{ID: before[i].ID, Before: *before[i].Cost, After: *before[i].Cost*11/10 - 5}
+			if change != want {
+				t.Errorf("got %+v, want %+v", change, want)
+			}
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		must(t, c.products.Delete(ctx, added.ID))
+		_, err := c.products.Get(ctx, added.ID)
+		expect(t, err, apperror.NotFound)
+	})
+
+	t.Run("delete missing", func(t *testing.T) {
+		expect(t, c.products.Delete(ctx, uuid.NewString()), apperror.NotFound)
+	})
+}
+
+// ids returns the ids of data, sorted when asked to.
+func ids(data []product.Entity, sorted bool) (dest []string) {
+	for _, object := range data {
+		dest = append(dest, object.ID)
+	}
+	if sorted {
+		slices.Sort(dest)
+	}
+	return
+}
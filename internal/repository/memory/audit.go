@@ -0,0 +1,50 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"product/internal/domain/audit"
+)
+
+type AuditRepository struct {
+	db *Store
+}
+
+func NewAuditRepository(db *Store) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (s *AuditRepository) Create(ctx context.Context, data audit.Entity) (id int64, err error) {
+	defer s.db.lock(ctx)()
+
+	now := time.Now()
+	data.ID, data.CreatedAt = int64(len(s.db.audit))+1, &now
+	data.RequestID = nullIf(data.RequestID)
+	s.db.audit = append(s.db.audit, data)
+
+	return data.ID, nil
+}
+
+// Select returns the records matching the filters, newest first.
+func (s *AuditRepository) Select(ctx context.Context, filters audit.Filters) (dest []audit.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]audit.Entity, 0)
+	for i := len(s.db.audit) - 1; i >= 0 && len(dest) < filters.Limit; i-- {
+		data := s.db.audit[i]
+		switch {
+		case filters.Actor != "" && *data.Actor != filters.Actor:
+		case filters.EntityType != "" && *data.EntityType != filters.EntityType:
+		case filters.EntityID != "" && *data.EntityID != filters.EntityID:
+		case filters.From != nil && data.CreatedAt.Before(*filters.From):
+		case filters.To != nil && !data.CreatedAt.Before(*filters.To):
+		default:
+			dest = append(dest, data)
+		}
+	}
+
+	return
+}
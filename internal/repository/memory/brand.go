@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"product/internal/domain/brand"
+	"product/pkg/store"
+)
+
+type BrandRepository struct {
+	db *Store
+}
+
+func NewBrandRepository(db *Store) *BrandRepository {
+	return &BrandRepository{
+		db: db,
+	}
+}
+
+func (s *BrandRepository) Select(ctx context.Context) (dest []brand.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]brand.Entity, 0, len(s.db.brands))
+	for _, data := range s.db.brands {
+		dest = append(dest, cloneBrand(data))
+	}
+	slices.SortFunc(dest, func(a, b brand.Entity) int {
+		return strings.Compare(*a.Name, *b.Name)
+	})
+
+	return
+}
+
+func (s *BrandRepository) Create(ctx context.Context, data brand.Entity) (id string, err error) {
+	defer s.db.lock(ctx)()
+
+	if data.Name == nil {
+		return "", blank("name")
+	}
+	if _, ok := s.db.brands[data.ID]; ok {
+		return "", duplicate("brands", "id")
+	}
+	if s.nameTakenLocked(data.ID, *data.Name) {
+		return "", duplicate("brands", "name")
+	}
+
+	s.db.brands[data.ID] = cloneBrand(data)
+
+	return data.ID, nil
+}
+
+func (s *BrandRepository) Get(ctx context.Context, id string) (dest brand.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	data, ok := s.db.brands[id]
+	if !ok {
+		return dest, store.ErrorNotFound
+	}
+
+	return cloneBrand(data), nil
+}
+
+func (s *BrandRepository) Update(ctx context.Context, id string, data brand.Entity) (err error) {
+	if data.Name == nil && data.Country == nil {
+		return
+	}
+
+	defer s.db.lock(ctx)()
+
+	current, ok := s.db.brands[id]
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	if data.Name != nil {
+		if s.nameTakenLocked(id, *data.Name) {
+			return duplicate("brands", "name")
+		}
+		current.Name = clone(data.Name)
+	}
+	if data.Country != nil {
+		current.Country = nullIf(data.Country)
+	}
+	s.db.brands[id] = current
+
+	return
+}
+
+func (s *BrandRepository) Delete(ctx context.Context, id string) (err error) {
+	defer s.db.lock(ctx)()
+
+	if _, ok := s.db.brands[id]; !ok {
+		return store.ErrorNotFound
+	}
+	for _, data := range s.db.products {
+		if data.BrandID != nil && *data.BrandID == id {
+			return referenced("products")
+		}
+	}
+
+	delete(s.db.brands, id)
+
+	return
+}
+
+// nameTakenLocked reports whether another brand has the name, ignoring case
+// as the unique index on LOWER(name) does. The caller holds the store lock.
+func (s *BrandRepository) nameTakenLocked(id, name string) bool {
+	for _, data := range s.db.brands {
+		if data.ID != id && strings.EqualFold(*data.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneBrand(data brand.Entity) brand.Entity {
+	return brand.Entity{
+		ID:      data.ID,
+		Name:    clone(data.Name),
+		Country: nullIf(data.Country),
+	}
+}
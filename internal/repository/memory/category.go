@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"product/internal/domain/category"
+	"product/pkg/store"
+)
+
+type CategoryRepository struct {
+	db *Store
+}
+
+func NewCategoryRepository(db *Store) *CategoryRepository {
+	return &CategoryRepository{
+		db: db,
+	}
+}
+
+func (s *CategoryRepository) Select(ctx context.Context) (dest []category.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]category.Entity, 0, len(s.db.categories))
+	for _, data := range s.db.categories {
+		dest = append(dest, cloneCategory(data))
+	}
+	sortCategories(dest)
+
+	return
+}
+
+func (s *CategoryRepository) Create(ctx context.Context, data category.Entity) (id string, err error) {
+	defer s.db.lock(ctx)()
+
+	switch {
+	case data.Name == nil:
+		return "", blank("name")
+	case data.ParentId == nil:
+		return "", blank("parent_id")
+	}
+	if _, ok := s.db.categories[data.ID]; ok {
+		return "", duplicate("categories", "id")
+	}
+
+	s.db.categories[data.ID] = cloneCategory(data)
+
+	return data.ID, nil
+}
+
+func (s *CategoryRepository) Get(ctx context.Context, id string) (dest category.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	data, ok := s.db.categories[id]
+	if !ok {
+		return dest, store.ErrorNotFound
+	}
+
+	dest = cloneCategory(data)
+	for _, child := range s.db.categories {
+		if child.ParentId != nil && *child.ParentId == id {
+			dest.Child = append(dest.Child, cloneCategory(child))
+		}
+	}
+	sortCategories(dest.Child)
+
+	return
+}
+
+func (s *CategoryRepository) Update(ctx context.Context, id string, data category.Entity) (err error) {
+	if data.Name == nil && data.ParentId == nil {
+		return
+	}
+
+	defer s.db.lock(ctx)()
+
+	current, ok := s.db.categories[id]
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	if data.Name != nil {
+		current.Name = clone(data.Name)
+	}
+	if data.ParentId != nil {
+		current.ParentId = clone(data.ParentId)
+	}
+	s.db.categories[id] = current
+
+	return
+}
+
+func (s *CategoryRepository) Delete(ctx context.Context, id string) (err error) {
+	defer s.db.lock(ctx)()
+
+	if _, ok := s.db.categories[id]; !ok {
+		return store.ErrorNotFound
+	}
+	for _, data := range s.db.products {
+		if data.CategoryID != nil && *data.CategoryID == id {
+			return referenced("products")
+		}
+	}
+
+	delete(s.db.categories, id)
+
+	return
+}
+
+// cloneCategory copies a category without its children, which are never stored.
+func cloneCategory(data category.Entity) category.Entity {
+	return category.Entity{
+		ID:       data.ID,
+		ParentId: clone(data.ParentId),
+		Name:     clone(data.Name),
+	}
+}
+
+func sortCategories(data []category.Entity) {
+	slices.SortFunc(data, func(a, b category.Entity) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+
+	"product/internal/repository/contract"
+	"product/internal/repository/memory"
+)
+
+func TestContract(t *testing.T) {
+	db := memory.NewStore()
+
+	contract.Run(t, memory.NewCategoryRepository(db), memory.NewProductRepository(db))
+}
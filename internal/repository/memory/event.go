@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"product/internal/domain/event"
+)
+
+// EventRepository keeps the outbox in a Store. Writes are serialized by the
+// store lock, so every event is given the transaction ID of its own ID and
+// the change feed reads them in the order they were written.
+type EventRepository struct {
+	db *Store
+}
+
+func NewEventRepository(db *Store) *EventRepository {
+	return &EventRepository{
+		db: db,
+	}
+}
+
+func (s *EventRepository) Create(ctx context.Context, data event.Entity) (id int64, err error) {
+	defer s.db.lock(ctx)()
+
+	now, attempts := time.Now(), 0
+	id = int64(len(s.db.events)) + 1
+	s.db.events = append(s.db.events, event.Entity{
+		ID:            id,
+		CreatedAt:     &now,
+		Type:          clone(data.Type),
+		AggregateType: clone(data.AggregateType),
+		AggregateID:   clone(data.AggregateID),
+		Payload:       clone(data.Payload),
+		Attempts:      &attempts,
+		TxID:          &id,
+	})
+
+	return
+}
+
+func (s *EventRepository) SelectPending(ctx context.Context, limit int) (dest []event.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]event.Entity, 0)
+	for _, data := range s.db.events {
+		if len(dest) == limit {
+			break
+		}
+		if data.PublishedAt == nil {
+			dest = append(dest, data)
+		}
+	}
+
+	return
+}
+
+func (s *EventRepository) MarkPublished(ctx context.Context, ids []int64) (err error) {
+	defer s.db.lock(ctx)()
+
+	now := time.Now()
+	for i, data := range s.db.events {
+		if slices.Contains(ids, data.ID) {
+			attempts := *data.Attempts + 1
+			data.PublishedAt, data.Attempts, data.LastError = &now, &attempts, nil
+			s.db.events[i] = data
+		}
+	}
+
+	return
+}
+
+func (s *EventRepository) MarkFailed(ctx context.Context, id int64, lastError string) (err error) {
+	defer s.db.lock(ctx)()
+
+	for i, data := range s.db.events {
+		if data.ID == id {
+			attempts := *data.Attempts + 1
+			data.Attempts, data.LastError = &attempts, &lastError
+			s.db.events[i] = data
+		}
+	}
+
+	return
+}
+
+func (s *EventRepository) SelectSince(ctx context.Context, txid, id int64, types []string, limit int) (dest []event.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]event.Entity, 0)
+	for _, data := range s.db.events {
+		if len(dest) == limit {
+			break
+		}
+		after := *data.TxID > txid || *data.TxID == txid && data.ID > id
+		if after && slices.Contains(types, *data.Type) {
+			dest = append(dest, data)
+		}
+	}
+
+	return
+}
+
+func (s *EventRepository) Position(ctx context.Context) (txid, id int64, err error) {
+	defer s.db.lock(ctx)()
+
+	if n := len(s.db.events); n > 0 {
+		last := s.db.events[n-1]
+		return *last.TxID, last.ID, nil
+	}
+
+	return
+}
@@ -0,0 +1,370 @@
+package memory
+
+import (
+	"context"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"product/internal/domain/product"
+	"product/pkg/store"
+)
+
+// ProductRepository keeps products in a Store. Like the postgres store, it
+// joins the brand name to every product read and the supplier name only to
+// the streamed ones.
+type ProductRepository struct {
+	db *Store
+}
+
+func NewProductRepository(db *Store) *ProductRepository {
+	return &ProductRepository{
+		db: db,
+	}
+}
+
+func (s *ProductRepository) Select(ctx context.Context, filters product.Filters) (dest []product.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	return s.selectLocked(filters), nil
+}
+
+// Stream passes every product matching the filters to fn, ordered by id. The
+// products are copied first, so fn may call the repository.
+func (s *ProductRepository) Stream(ctx context.Context, filters product.Filters, fn func(product.Entity) error) (err error) {
+	unlock := s.db.lock(ctx)
+	data := s.selectLocked(filters)
+	for i := range data {
+		if data[i].SupplierID != nil {
+			data[i].SupplierName = clone(s.db.suppliers[*data[i].SupplierID].Name)
+		}
+	}
+	unlock()
+
+	for _, row := range data {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		if err = fn(row); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *ProductRepository) Count(ctx context.Context, filters product.Filters) (count int, err error) {
+	defer s.db.lock(ctx)()
+
+	for _, data := range s.db.products {
+		if matches(data, filters) {
+			count++
+		}
+	}
+
+	return
+}
+
+// AdjustCosts rescales the cost of at most limit products matching the filters
+// whose id sorts after afterID, and returns the changes ordered by id.
+func (s *ProductRepository) AdjustCosts(ctx context.Context, filters product.Filters, percent float64, delta int, afterID string, limit int) (dest []product.CostChange, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]product.CostChange, 0)
+	for _, data := range s.selectLocked(filters) {
+		if len(dest) == limit {
+			break
+		}
+		if data.ID <= afterID {
+			continue
+		}
+
+		before := 0
+		if data.Cost != nil {
+			before = *data.Cost
+		}
+		after := max(int(math.Round(float64(before)*(1+percent/100)))+delta, 0)
+
+		current := s.db.products[data.ID]
+		current.Cost = &after
+		s.db.products[data.ID] = current
+
+		dest = append(dest, product.CostChange{ID: data.ID, Before: before, After: after})
+	}
+
+	return
+}
+
+func (s *ProductRepository) Create(ctx context.Context, data product.Entity) (id string, err error) {
+	defer s.db.lock(ctx)()
+
+	if err = s.checkLocked(data); err != nil {
+		return
+	}
+	if _, ok := s.db.products[data.ID]; ok {
+		return "", duplicate("products", "id")
+	}
+	if _, ok := s.findByBarcode(*data.Barcode); ok {
+		return "", duplicate("products", "barcode")
+	}
+
+	s.db.products[data.ID] = cloneProduct(data)
+
+	return data.ID, nil
+}
+
+func (s *ProductRepository) Get(ctx context.Context, id string) (dest product.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	data, ok := s.db.products[id]
+	if !ok {
+		return dest, store.ErrorNotFound
+	}
+
+	return s.joinLocked(data), nil
+}
+
+func (s *ProductRepository) GetByBarcode(ctx context.Context, barcode string) (dest product.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	data, ok := s.findByBarcode(barcode)
+	if !ok {
+		return dest, store.ErrorNotFound
+	}
+
+	return s.joinLocked(data), nil
+}
+
+func (s *ProductRepository) SelectByBarcodes(ctx context.Context, barcodes []string) (dest []product.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]product.Entity, 0)
+	for _, data := range s.db.products {
+		if slices.Contains(barcodes, *data.Barcode) {
+			dest = append(dest, s.joinLocked(data))
+		}
+	}
+	sortProducts(dest)
+
+	return
+}
+
+// Upsert inserts the products or updates the ones whose barcode already exists.
+// Either every row is written or none is.
+func (s *ProductRepository) Upsert(ctx context.Context, data []product.Entity) (err error) {
+	defer s.db.lock(ctx)()
+
+	products := maps.Clone(s.db.products)
+	defer func() {
+		if err != nil {
+			s.db.products = products
+		}
+	}()
+
+	for _, row := range data {
+		if err = s.checkLocked(row); err != nil {
+			return
+		}
+
+		row = cloneProduct(row)
+		if current, ok := s.findByBarcode(*row.Barcode); ok {
+			row.ID = current.ID
+		} else if _, ok = s.db.products[row.ID]; ok {
+			return duplicate("products", "id")
+		}
+		s.db.products[row.ID] = row
+	}
+
+	return
+}
+
+func (s *ProductRepository) Update(ctx context.Context, id string, data product.Entity) (err error) {
+	defer s.db.lock(ctx)()
+
+	current, ok := s.db.products[id]
+	if !apply(&current, data) {
+		return
+	}
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	if data.Barcode != nil {
+		if other, ok := s.findByBarcode(*data.Barcode); ok && other.ID != id {
+			return duplicate("products", "barcode")
+		}
+	}
+	if err = s.checkLocked(current); err != nil {
+		return
+	}
+	s.db.products[id] = current
+
+	return
+}
+
+// apply sets the fields of data that are not nil on current and reports
+// whether there was any.
+func apply(current *product.Entity, data product.Entity) (changed bool) {
+	set := func(dest **string, value *string, blankAsNil bool) {
+		if value == nil {
+			return
+		}
+		if blankAsNil {
+			*dest = nullIf(value)
+		} else {
+			*dest = clone(value)
+		}
+		changed = true
+	}
+
+	set(&current.CategoryID, data.CategoryID, false)
+	set(&current.Barcode, data.Barcode, false)
+	set(&current.Name, data.Name, false)
+	set(&current.Measure, data.Measure, false)
+	set(&current.ProducerCountry, data.ProducerCountry, true)
+	set(&current.BrandID, data.BrandID, true)
+	set(&current.SupplierID, data.SupplierID, true)
+	set(&current.Description, data.Description, false)
+	set(&current.Image, data.Image, false)
+
+	if data.Cost != nil {
+		current.Cost, changed = clone(data.Cost), true
+	}
+	if data.IsWeighted != nil {
+		current.IsWeighted, changed = clone(data.IsWeighted), true
+	}
+
+	return
+}
+
+func (s *ProductRepository) Delete(ctx context.Context, id string) (err error) {
+	defer s.db.lock(ctx)()
+
+	if _, ok := s.db.products[id]; !ok {
+		return store.ErrorNotFound
+	}
+
+	delete(s.db.products, id)
+
+	return
+}
+
+// SelectUnmatched returns no reference: products in memory never went through
+// the migration that leaves them behind.
+func (s *ProductRepository) SelectUnmatched(ctx context.Context) (dest []product.UnmatchedReference, err error) {
+	return make([]product.UnmatchedReference, 0), nil
+}
+
+// selectLocked returns copies of the products matching the filters ordered by
+// id. The caller holds the store lock.
+func (s *ProductRepository) selectLocked(filters product.Filters) (dest []product.Entity) {
+	dest = make([]product.Entity, 0)
+	for _, data := range s.db.products {
+		if matches(data, filters) {
+			dest = append(dest, s.joinLocked(data))
+		}
+	}
+	sortProducts(dest)
+
+	return
+}
+
+// joinLocked copies a product along with the name of its brand. The caller
+// holds the store lock.
+func (s *ProductRepository) joinLocked(data product.Entity) product.Entity {
+	data = cloneProduct(data)
+	if data.BrandID != nil {
+		data.BrandName = clone(s.db.brands[*data.BrandID].Name)
+	}
+	return data
+}
+
+// checkLocked reports the columns that are NOT NULL or reference another
+// table in Postgres. The caller holds the store lock.
+func (s *ProductRepository) checkLocked(data product.Entity) error {
+	switch {
+	case data.CategoryID == nil:
+		return blank("category_id")
+	case data.Barcode == nil:
+		return blank("barcode")
+	case data.Name == nil:
+		return blank("name")
+	case data.Description == nil:
+		return blank("description")
+	case data.Image == nil:
+		return blank("image")
+	case data.IsWeighted == nil:
+		return blank("is_weighted")
+	}
+
+	if _, ok := s.db.categories[*data.CategoryID]; !ok {
+		return missingReference("category_id")
+	}
+	if id := data.BrandID; id != nil && *id != "" {
+		if _, ok := s.db.brands[*id]; !ok {
+			return missingReference("brand_id")
+		}
+	}
+	if id := data.SupplierID; id != nil && *id != "" {
+		if _, ok := s.db.suppliers[*id]; !ok {
+			return missingReference("supplier_id")
+		}
+	}
+
+	return nil
+}
+
+func (s *ProductRepository) findByBarcode(barcode string) (product.Entity, bool) {
+	for _, data := range s.db.products {
+		if *data.Barcode == barcode {
+			return data, true
+		}
+	}
+	return product.Entity{}, false
+}
+
+// matches applies the filters the way the Postgres repository does: a product
+// without a value never matches a filter on it, and Search is case-sensitive.
+func matches(data product.Entity, filters product.Filters) bool {
+	switch {
+	case filters.CostGTE != nil && (data.Cost == nil || *data.Cost < *filters.CostGTE):
+		return false
+	case filters.CostLTE != nil && (data.Cost == nil || *data.Cost > *filters.CostLTE):
+		return false
+	case filters.Search != "" && !strings.Contains(*data.Name, filters.Search):
+		return false
+	case filters.BrandID != "" && (data.BrandID == nil || *data.BrandID != filters.BrandID):
+		return false
+	case filters.SupplierID != "" && (data.SupplierID == nil || *data.SupplierID != filters.SupplierID):
+		return false
+	case filters.ProducerCountry != "" && (data.ProducerCountry == nil || *data.ProducerCountry != filters.ProducerCountry):
+		return false
+	}
+	return true
+}
+
+// cloneProduct copies a product, leaving out the names joined from brands and
+// suppliers and storing blank references as nil.
+func cloneProduct(data product.Entity) product.Entity {
+	return product.Entity{
+		ID:              data.ID,
+		CategoryID:      clone(data.CategoryID),
+		Barcode:         clone(data.Barcode),
+		Name:            clone(data.Name),
+		Measure:         clone(data.Measure),
+		Cost:            clone(data.Cost),
+		ProducerCountry: nullIf(data.ProducerCountry),
+		BrandID:         nullIf(data.BrandID),
+		SupplierID:      nullIf(data.SupplierID),
+		Description:     clone(data.Description),
+		Image:           clone(data.Image),
+		IsWeighted:      clone(data.IsWeighted),
+	}
+}
+
+func sortProducts(data []product.Entity) {
+	slices.SortFunc(data, func(a, b product.Entity) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"product/internal/domain/revision"
+	"product/pkg/store"
+)
+
+type RevisionRepository struct {
+	db *Store
+}
+
+func NewRevisionRepository(db *Store) *RevisionRepository {
+	return &RevisionRepository{
+		db: db,
+	}
+}
+
+// Create numbers the revision after the last one of the entity.
+func (s *RevisionRepository) Create(ctx context.Context, data revision.Entity) (number int, err error) {
+	defer s.db.lock(ctx)()
+
+	for _, current := range s.db.revisions {
+		if *current.EntityType == *data.EntityType && *current.EntityID == *data.EntityID {
+			number = max(number, current.Revision)
+		}
+	}
+
+	now := time.Now()
+	data.Revision, data.CreatedAt = number+1, &now
+	data.RequestID = nullIf(data.RequestID)
+	s.db.revisions = append(s.db.revisions, data)
+
+	return data.Revision, nil
+}
+
+// Select returns the revisions of the entity, latest first.
+func (s *RevisionRepository) Select(ctx context.Context, entityType, entityID string) (dest []revision.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]revision.Entity, 0)
+	for i := len(s.db.revisions) - 1; i >= 0; i-- {
+		if data := s.db.revisions[i]; *data.EntityType == entityType && *data.EntityID == entityID {
+			dest = append(dest, data)
+		}
+	}
+
+	return
+}
+
+func (s *RevisionRepository) Get(ctx context.Context, entityType, entityID string, number int) (dest revision.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	for _, data := range s.db.revisions {
+		if *data.EntityType == entityType && *data.EntityID == entityID && data.Revision == number {
+			return data, nil
+		}
+	}
+
+	return dest, store.ErrorNotFound
+}
@@ -0,0 +1,143 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"product/internal/domain/snapshot"
+	"product/pkg/store"
+)
+
+type SnapshotRepository struct {
+	db *Store
+}
+
+func NewSnapshotRepository(db *Store) *SnapshotRepository {
+	return &SnapshotRepository{
+		db: db,
+	}
+}
+
+// Select returns the snapshots without their bundles, latest first.
+func (s *SnapshotRepository) Select(ctx context.Context, limit int) (dest []snapshot.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]snapshot.Entity, 0)
+	for i := len(s.db.snapshots) - 1; i >= 0 && len(dest) < limit; i-- {
+		dest = append(dest, withoutBundle(s.db.snapshots[i]))
+	}
+
+	return
+}
+
+// Create numbers the snapshot after the last one ever created.
+func (s *SnapshotRepository) Create(ctx context.Context, data snapshot.Entity) (version int64, err error) {
+	defer s.db.lock(ctx)()
+
+	now := time.Now()
+	s.db.lastSnapshot++
+	data.Version, data.CreatedAt = s.db.lastSnapshot, &now
+	data.Bundle = slices.Clone(data.Bundle)
+	s.db.snapshots = append(s.db.snapshots, data)
+
+	return data.Version, nil
+}
+
+func (s *SnapshotRepository) Get(ctx context.Context, version int64) (dest snapshot.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	data, ok := s.findLocked(version)
+	if !ok {
+		return dest, store.ErrorNotFound
+	}
+
+	return withoutBundle(data), nil
+}
+
+func (s *SnapshotRepository) Latest(ctx context.Context) (dest snapshot.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	if len(s.db.snapshots) == 0 {
+		return dest, store.ErrorNotFound
+	}
+
+	return withoutBundle(s.db.snapshots[len(s.db.snapshots)-1]), nil
+}
+
+func (s *SnapshotRepository) Bundle(ctx context.Context, version int64) (bundle []byte, err error) {
+	defer s.db.lock(ctx)()
+
+	data, ok := s.findLocked(version)
+	if !ok {
+		return nil, store.ErrorNotFound
+	}
+
+	return slices.Clone(data.Bundle), nil
+}
+
+func (s *SnapshotRepository) Delta(ctx context.Context, from, to int64) (patch []byte, err error) {
+	defer s.db.lock(ctx)()
+
+	patch, ok := s.db.deltas[[2]int64{from, to}]
+	if !ok {
+		return nil, store.ErrorNotFound
+	}
+
+	return slices.Clone(patch), nil
+}
+
+func (s *SnapshotRepository) CreateDelta(ctx context.Context, from, to int64, patch []byte) (err error) {
+	defer s.db.lock(ctx)()
+
+	_, fromOK := s.findLocked(from)
+	_, toOK := s.findLocked(to)
+	if !fromOK || !toOK {
+		return missingReference("from_version")
+	}
+
+	key := [2]int64{from, to}
+	if _, ok := s.db.deltas[key]; !ok {
+		s.db.deltas[key] = slices.Clone(patch)
+	}
+
+	return
+}
+
+func (s *SnapshotRepository) Prune(ctx context.Context, keep int) (versions []int64, err error) {
+	defer s.db.lock(ctx)()
+
+	versions = make([]int64, 0)
+	if len(s.db.snapshots) <= keep {
+		return
+	}
+
+	cut := len(s.db.snapshots) - keep
+	for _, data := range s.db.snapshots[:cut] {
+		versions = append(versions, data.Version)
+	}
+	s.db.snapshots = slices.Clone(s.db.snapshots[cut:])
+
+	for key := range s.db.deltas {
+		if slices.Contains(versions, key[0]) || slices.Contains(versions, key[1]) {
+			delete(s.db.deltas, key)
+		}
+	}
+
+	return
+}
+
+// findLocked returns the snapshot of version. The caller holds the store lock.
+func (s *SnapshotRepository) findLocked(version int64) (snapshot.Entity, bool) {
+	for _, data := range s.db.snapshots {
+		if data.Version == version {
+			return data, true
+		}
+	}
+	return snapshot.Entity{}, false
+}
+
+func withoutBundle(data snapshot.Entity) snapshot.Entity {
+	data.Bundle = nil
+	return data
+}
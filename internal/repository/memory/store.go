@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/brand"
+	"product/internal/domain/category"
+	"product/internal/domain/event"
+	"product/internal/domain/product"
+	"product/internal/domain/revision"
+	"product/internal/domain/snapshot"
+	"product/internal/domain/supplier"
+	"product/pkg/apperror"
+)
+
+// Store keeps the catalog, its audit log, revisions, outbox and snapshots in
+// memory, for tests and for running the service without a database. Every call
+// holds a single lock, so reads never see the writes of a transaction that has
+// not finished.
+type Store struct {
+	mu sync.Mutex
+	tables
+}
+
+// tables holds the rows of a Store. Rows are only ever replaced, never
+// changed in place, so a shallow copy is enough to roll a transaction back.
+type tables struct {
+	categories map[string]category.Entity
+	products   map[string]product.Entity
+	brands     map[string]brand.Entity
+	suppliers  map[string]supplier.Entity
+	audit      []audit.Entity
+	revisions  []revision.Entity
+	events     []event.Entity
+
+	snapshots    []snapshot.Entity
+	deltas       map[[2]int64][]byte
+	lastSnapshot int64
+}
+
+func NewStore() *Store {
+	return &Store{
+		tables: tables{
+			categories: make(map[string]category.Entity),
+			products:   make(map[string]product.Entity),
+			brands:     make(map[string]brand.Entity),
+			suppliers:  make(map[string]supplier.Entity),
+			deltas:     make(map[[2]int64][]byte),
+		},
+	}
+}
+
+func (t tables) clone() tables {
+	return tables{
+		categories: maps.Clone(t.categories),
+		products:   maps.Clone(t.products),
+		brands:     maps.Clone(t.brands),
+		suppliers:  maps.Clone(t.suppliers),
+		audit:      slices.Clone(t.audit),
+		revisions:  slices.Clone(t.revisions),
+		events:     slices.Clone(t.events),
+
+		snapshots:    slices.Clone(t.snapshots),
+		deltas:       maps.Clone(t.deltas),
+		lastSnapshot: t.lastSnapshot,
+	}
+}
+
+type txKey struct{}
+
+// lock takes the store lock unless ctx carries a transaction of s, which
+// already holds it.
+func (s *Store) lock(ctx context.Context) (unlock func()) {
+	if tx, ok := ctx.Value(txKey{}).(*Store); ok && tx == s {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// Transaction implements store.Transactor. fn runs holding the store lock and
+// its writes are undone when it returns an error. When ctx already carries a
+// transaction fn runs in it and only its own writes are undone, like in a
+// savepoint. Hooks registered with store.AfterCommit run right away, since
+// there is no database transaction to wait for.
+func (s *Store) Transaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if tx, ok := ctx.Value(txKey{}).(*Store); !ok || tx != s {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		ctx = context.WithValue(ctx, txKey{}, s)
+	}
+
+	saved := s.tables.clone()
+	if err = fn(ctx); err != nil {
+		s.tables = saved
+	}
+
+	return
+}
+
+// clone copies the value behind p, so callers never share memory with the store.
+func clone[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// nullIf stores blank strings as nil, as NULLIF does in the postgres store.
+func nullIf(p *string) *string {
+	if p == nil || *p == "" {
+		return nil
+	}
+	return clone(p)
+}
+
+// The errors below read the same as the ones store.Translate makes of the
+// matching Postgres constraint violations.
+
+func duplicate(table, column string) error {
+	return apperror.New(apperror.Conflict, fmt.Sprintf("%s: a record with the same %s already exists", table, column))
+}
+
+func referenced(table string) error {
+	return apperror.New(apperror.Conflict, fmt.Sprintf("the record is still referenced by %s", table))
+}
+
+func missingReference(column string) error {
+	return apperror.New(apperror.Validation, fmt.Sprintf("%s: the referenced record does not exist", column))
+}
+
+func blank(column string) error {
+	return apperror.New(apperror.Validation, fmt.Sprintf("%s: cannot be blank", column))
+}
@@ -0,0 +1,133 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"product/internal/domain/supplier"
+	"product/pkg/store"
+)
+
+type SupplierRepository struct {
+	db *Store
+}
+
+func NewSupplierRepository(db *Store) *SupplierRepository {
+	return &SupplierRepository{
+		db: db,
+	}
+}
+
+func (s *SupplierRepository) Select(ctx context.Context) (dest []supplier.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	dest = make([]supplier.Entity, 0, len(s.db.suppliers))
+	for _, data := range s.db.suppliers {
+		dest = append(dest, cloneSupplier(data))
+	}
+	slices.SortFunc(dest, func(a, b supplier.Entity) int {
+		return strings.Compare(*a.Name, *b.Name)
+	})
+
+	return
+}
+
+func (s *SupplierRepository) Create(ctx context.Context, data supplier.Entity) (id string, err error) {
+	defer s.db.lock(ctx)()
+
+	if data.Name == nil {
+		return "", blank("name")
+	}
+	if _, ok := s.db.suppliers[data.ID]; ok {
+		return "", duplicate("suppliers", "id")
+	}
+	if s.nameTakenLocked(data.ID, *data.Name) {
+		return "", duplicate("suppliers", "name")
+	}
+
+	s.db.suppliers[data.ID] = cloneSupplier(data)
+
+	return data.ID, nil
+}
+
+func (s *SupplierRepository) Get(ctx context.Context, id string) (dest supplier.Entity, err error) {
+	defer s.db.lock(ctx)()
+
+	data, ok := s.db.suppliers[id]
+	if !ok {
+		return dest, store.ErrorNotFound
+	}
+
+	return cloneSupplier(data), nil
+}
+
+func (s *SupplierRepository) Update(ctx context.Context, id string, data supplier.Entity) (err error) {
+	if data.Name == nil && data.Country == nil && data.Email == nil && data.Phone == nil {
+		return
+	}
+
+	defer s.db.lock(ctx)()
+
+	current, ok := s.db.suppliers[id]
+	if !ok {
+		return store.ErrorNotFound
+	}
+
+	if data.Name != nil {
+		if s.nameTakenLocked(id, *data.Name) {
+			return duplicate("suppliers", "name")
+		}
+		current.Name = clone(data.Name)
+	}
+	if data.Country != nil {
+		current.Country = nullIf(data.Country)
+	}
+	if data.Email != nil {
+		current.Email = nullIf(data.Email)
+	}
+	if data.Phone != nil {
+		current.Phone = nullIf(data.Phone)
+	}
+	s.db.suppliers[id] = current
+
+	return
+}
+
+func (s *SupplierRepository) Delete(ctx context.Context, id string) (err error) {
+	defer s.db.lock(ctx)()
+
+	if _, ok := s.db.suppliers[id]; !ok {
+		return store.ErrorNotFound
+	}
+	for _, data := range s.db.products {
+		if data.SupplierID != nil && *data.SupplierID == id {
+			return referenced("products")
+		}
+	}
+
+	delete(s.db.suppliers, id)
+
+	return
+}
+
+// nameTakenLocked reports whether another supplier has the name, ignoring
+// case as the unique index on LOWER(name) does. The caller holds the store lock.
+func (s *SupplierRepository) nameTakenLocked(id, name string) bool {
+	for _, data := range s.db.suppliers {
+		if data.ID != id && strings.EqualFold(*data.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func cloneSupplier(data supplier.Entity) supplier.Entity {
+	return supplier.Entity{
+		ID:      data.ID,
+		Name:    clone(data.Name),
+		Country: nullIf(data.Country),
+		Email:   nullIf(data.Email),
+		Phone:   nullIf(data.Phone),
+	}
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"product/internal/domain/apikey"
+	"product/internal/domain/imports"
+	"product/internal/domain/job"
+	"product/internal/domain/modifier"
+	"product/internal/domain/webhook"
+	"product/pkg/apperror"
+	"product/pkg/store"
+)
+
+// The repositories below stand in for the tables the memory store does not
+// keep, so the service runs without a database: they find nothing and refuse
+// to store anything with errUnsupported. Queues are always empty, so workers
+// polling them stay idle.
+
+var errUnsupported = apperror.New(apperror.PreconditionFailed, "not available with the memory store")
+
+type ModifierRepository struct{}
+
+func (ModifierRepository) Select(ctx context.Context) ([]modifier.Entity, error) {
+	return make([]modifier.Entity, 0), nil
+}
+func (ModifierRepository) Create(ctx context.Context, data modifier.Entity) (string, error) {
+	return "", errUnsupported
+}
+func (ModifierRepository) Get(ctx context.Context, id string) (modifier.Entity, error) {
+	return modifier.Entity{}, store.ErrorNotFound
+}
+func (ModifierRepository) Update(ctx context.Context, id string, data modifier.Entity) error {
+	return store.ErrorNotFound
+}
+func (ModifierRepository) Delete(ctx context.Context, id string) error {
+	return store.ErrorNotFound
+}
+func (ModifierRepository) SelectByProduct(ctx context.Context, productID string) ([]modifier.Entity, error) {
+	return make([]modifier.Entity, 0), nil
+}
+func (ModifierRepository) Attach(ctx context.Context, productID string, groupIDs []string) error {
+	if len(groupIDs) > 0 {
+		return errUnsupported
+	}
+	return nil
+}
+
+type ImportRepository struct{}
+
+func (ImportRepository) Create(ctx context.Context, data imports.Entity) (string, error) {
+	return "", errUnsupported
+}
+func (ImportRepository) Get(ctx context.Context, id string) (imports.Entity, error) {
+	return imports.Entity{}, store.ErrorNotFound
+}
+func (ImportRepository) Update(ctx context.Context, id string, data imports.Entity) error {
+	return store.ErrorNotFound
+}
+func (ImportRepository) AddErrors(ctx context.Context, id string, errs []imports.RowError) error {
+	return store.ErrorNotFound
+}
+func (ImportRepository) SelectErrors(ctx context.Context, id string) ([]imports.RowError, error) {
+	return nil, store.ErrorNotFound
+}
+
+type JobRepository struct{}
+
+func (JobRepository) Create(ctx context.Context, data job.Entity) (string, error) {
+	return "", errUnsupported
+}
+func (JobRepository) Get(ctx context.Context, id string) (job.Entity, error) {
+	return job.Entity{}, store.ErrorNotFound
+}
+func (JobRepository) Claim(ctx context.Context, worker string, types []string) (job.Entity, error) {
+	return job.Entity{}, store.ErrorNotFound
+}
+func (JobRepository) Heartbeat(ctx context.Context, id, worker string) (bool, error) {
+	return false, job.ErrLockLost
+}
+func (JobRepository) Progress(ctx context.Context, id, worker string, progress, total int, checkpoint *string) error {
+	return job.ErrLockLost
+}
+func (JobRepository) Finish(ctx context.Context, id, worker, status string, result, lastError *string) error {
+	return job.ErrLockLost
+}
+func (JobRepository) Retry(ctx context.Context, id, worker string, delay time.Duration, lastError string) error {
+	return job.ErrLockLost
+}
+func (JobRepository) Release(ctx context.Context, id, worker string) error {
+	return job.ErrLockLost
+}
+func (JobRepository) SaveFile(ctx context.Context, id, worker string, content []byte) error {
+	return job.ErrLockLost
+}
+func (JobRepository) File(ctx context.Context, id string) ([]byte, error) {
+	return nil, store.ErrorNotFound
+}
+func (JobRepository) Cancel(ctx context.Context, id string) error {
+	return store.ErrorNotFound
+}
+func (JobRepository) RequeueStale(ctx context.Context, timeout time.Duration) (int64, error) {
+	return 0, nil
+}
+
+type WebhookRepository struct{}
+
+func (WebhookRepository) Select(ctx context.Context) ([]webhook.Entity, error) {
+	return make([]webhook.Entity, 0), nil
+}
+func (WebhookRepository) Create(ctx context.Context, data webhook.Entity) (string, error) {
+	return "", errUnsupported
+}
+func (WebhookRepository) Get(ctx context.Context, id string) (webhook.Entity, error) {
+	return webhook.Entity{}, store.ErrorNotFound
+}
+func (WebhookRepository) Update(ctx context.Context, id string, data webhook.Entity) error {
+	return store.ErrorNotFound
+}
+func (WebhookRepository) Delete(ctx context.Context, id string) error {
+	return store.ErrorNotFound
+}
+func (WebhookRepository) AddDeliveries(ctx context.Context, data []webhook.Delivery) error {
+	if len(data) > 0 {
+		return errUnsupported
+	}
+	return nil
+}
+func (WebhookRepository) SelectDeliveries(ctx context.Context, webhookID string, limit int) ([]webhook.Delivery, error) {
+	return nil, store.ErrorNotFound
+}
+func (WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]webhook.Delivery, error) {
+	return make([]webhook.Delivery, 0), nil
+}
+func (WebhookRepository) FinishDelivery(ctx context.Context, id int64, status string, statusCode int, lastError string, delay time.Duration) error {
+	return store.ErrorNotFound
+}
+func (WebhookRepository) Redeliver(ctx context.Context, webhookID string, id int64) error {
+	return store.ErrorNotFound
+}
+
+type APIKeyRepository struct{}
+
+func (APIKeyRepository) Select(ctx context.Context) ([]apikey.Entity, error) {
+	return make([]apikey.Entity, 0), nil
+}
+func (APIKeyRepository) Create(ctx context.Context, data apikey.Entity) (string, error) {
+	return "", errUnsupported
+}
+func (APIKeyRepository) Get(ctx context.Context, id string) (apikey.Entity, error) {
+	return apikey.Entity{}, store.ErrorNotFound
+}
+func (APIKeyRepository) GetActive(ctx context.Context, prefix string) (apikey.Entity, error) {
+	return apikey.Entity{}, store.ErrorNotFound
+}
+func (APIKeyRepository) Touch(ctx context.Context, id string, interval time.Duration) error {
+	return nil
+}
+func (APIKeyRepository) Expire(ctx context.Context, id string, after time.Duration) error {
+	return store.ErrorNotFound
+}
+func (APIKeyRepository) Revoke(ctx context.Context, id string) error {
+	return store.ErrorNotFound
+}
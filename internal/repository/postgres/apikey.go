@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"product/internal/domain/apikey"
+	"product/pkg/store"
+)
+
+const apiKeyColumns = `id, created_at, name, prefix, hash, scopes, store_id, expires_at, last_used_at, revoked_at, rotated_from`
+
+type APIKeyRepository struct {
+	db *store.Database
+}
+
+func NewAPIKeyRepository(db *store.Database) *APIKeyRepository {
+	return &APIKeyRepository{
+		db: db,
+	}
+}
+
+func (s *APIKeyRepository) Select(ctx context.Context) (dest []apikey.Entity, err error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		ORDER BY created_at`
+
+	dest = make([]apikey.Entity, 0)
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &dest, query)
+
+	return
+}
+
+func (s *APIKeyRepository) Create(ctx context.Context, data apikey.Entity) (id string, err error) {
+	query := `
+		INSERT INTO api_keys (id, name, prefix, hash, scopes, store_id, expires_at, rotated_from)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)
+		RETURNING id`
+
+	args := []any{data.ID, data.Name, data.Prefix, data.Hash, data.Scopes, data.StoreID, data.ExpiresAt, data.RotatedFrom}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *APIKeyRepository) Get(ctx context.Context, id string) (dest apikey.Entity, err error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE id=$1`
+
+	args := []any{id}
+
+	if err = store.Conn(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *APIKeyRepository) GetActive(ctx context.Context, prefix string) (dest apikey.Entity, err error) {
+	query := `
+		SELECT ` + apiKeyColumns + `
+		FROM api_keys
+		WHERE prefix=$1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)`
+
+	args := []any{prefix}
+
+	err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...)
+	if err == sql.ErrNoRows {
+		// A key just created may not have reached the replicas yet. The
+		// lookup alone does not make the session read from the primary.
+		err = store.Conn(store.WithoutSession(ctx), s.db).GetContext(ctx, &dest, query, args...)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *APIKeyRepository) Touch(ctx context.Context, id string, interval time.Duration) (err error) {
+	query := `
+		UPDATE api_keys
+		SET last_used_at=CURRENT_TIMESTAMP
+		WHERE id=$1 AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - make_interval(secs => $2))`
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, query, id, interval.Seconds())
+
+	return
+}
+
+func (s *APIKeyRepository) Expire(ctx context.Context, id string, after time.Duration) (err error) {
+	query := `
+		UPDATE api_keys
+		SET expires_at=LEAST(COALESCE(expires_at, 'infinity'), CURRENT_TIMESTAMP + make_interval(secs => $2))
+		WHERE id=$1`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, id, after.Seconds())
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *APIKeyRepository) Revoke(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE api_keys
+		SET revoked_at=COALESCE(revoked_at, CURRENT_TIMESTAMP)
+		WHERE id=$1`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"product/internal/domain/audit"
+	"product/pkg/store"
+)
+
+type AuditRepository struct {
+	db *store.Database
+}
+
+func NewAuditRepository(db *store.Database) *AuditRepository {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (s *AuditRepository) Create(ctx context.Context, data audit.Entity) (id int64, err error) {
+	query := `
+		INSERT INTO audit_log (actor, request_id, entity_type, entity_id, operation, changes)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6)
+		RETURNING id`
+
+	args := []any{data.Actor, data.RequestID, data.EntityType, data.EntityID, data.Operation, data.Changes}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *AuditRepository) Select(ctx context.Context, filters audit.Filters) (dest []audit.Entity, err error) {
+	conditions, args := s.prepareFilters(filters)
+	args = append(args, filters.Limit)
+	query := fmt.Sprintf(`
+		SELECT id, created_at, actor, request_id, entity_type, entity_id, operation, changes
+		FROM audit_log
+		WHERE %s 1=1
+		ORDER BY id DESC
+		LIMIT $%d`, strings.Join(conditions, " "), len(args))
+
+	dest = make([]audit.Entity, 0)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query, args...)
+
+	return
+}
+
+func (s *AuditRepository) prepareFilters(filters audit.Filters) (conditions []string, args []any) {
+	if filters.Actor != "" {
+		args = append(args, filters.Actor)
+		conditions = append(conditions, fmt.Sprintf("actor = $%d AND", len(args)))
+	}
+
+	if filters.EntityType != "" {
+		args = append(args, filters.EntityType)
+		conditions = append(conditions, fmt.Sprintf("entity_type = $%d AND", len(args)))
+	}
+
+	if filters.EntityID != "" {
+		args = append(args, filters.EntityID)
+		conditions = append(conditions, fmt.Sprintf("entity_id = $%d AND", len(args)))
+	}
+
+	// created_at holds the wall clock of the session time zone,
+	// so the bounds are converted to it before comparing.
+	if filters.From != nil {
+		args = append(args, filters.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d::TIMESTAMPTZ::TIMESTAMP AND", len(args)))
+	}
+
+	if filters.To != nil {
+		args = append(args, filters.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d::TIMESTAMPTZ::TIMESTAMP AND", len(args)))
+	}
+
+	return
+}
@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"product/internal/domain/brand"
+	"product/pkg/store"
+)
+
+type BrandRepository struct {
+	db *store.Database
+}
+
+func NewBrandRepository(db *store.Database) *BrandRepository {
+	return &BrandRepository{
+		db: db,
+	}
+}
+
+func (s *BrandRepository) Select(ctx context.Context) (dest []brand.Entity, err error) {
+	query := `
+		SELECT id, name, country
+		FROM brands
+		ORDER BY name`
+
+	dest = make([]brand.Entity, 0)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query)
+
+	return
+}
+
+func (s *BrandRepository) Create(ctx context.Context, data brand.Entity) (id string, err error) {
+	query := `
+		INSERT INTO brands (id, name, country)
+		VALUES ($1, $2, NULLIF($3, ''))
+		RETURNING id`
+
+	args := []any{data.ID, data.Name, data.Country}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *BrandRepository) Get(ctx context.Context, id string) (dest brand.Entity, err error) {
+	query := `
+		SELECT id, name, country
+		FROM brands
+		WHERE id=$1`
+
+	args := []any{id}
+
+	if err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *BrandRepository) Update(ctx context.Context, id string, data brand.Entity) (err error) {
+	sets, args := s.prepareArgs(data)
+	if len(args) > 0 {
+		args = append(args, id)
+		sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+
+		query := fmt.Sprintf("UPDATE brands SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
+		result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return store.ErrorNotFound
+		}
+	}
+
+	return
+}
+
+func (s *BrandRepository) prepareArgs(data brand.Entity) (sets []string, args []any) {
+	if data.Name != nil {
+		args = append(args, data.Name)
+		sets = append(sets, fmt.Sprintf("name=$%d", len(args)))
+	}
+
+	if data.Country != nil {
+		args = append(args, data.Country)
+		sets = append(sets, fmt.Sprintf("country=NULLIF($%d, '')", len(args)))
+	}
+
+	return
+}
+
+func (s *BrandRepository) Delete(ctx context.Context, id string) (err error) {
+	query := `
+		DELETE
+		FROM brands
+		WHERE id=$1`
+
+	args := []any{id}
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
@@ -4,17 +4,16 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"github.com/jmoiron/sqlx"
 	category "product/internal/domain/category"
 	"product/pkg/store"
 	"strings"
 )
 
 type CategoryRepository struct {
-	db *sqlx.DB
+	db *store.Database
 }
 
-func NewCategoryRepository(db *sqlx.DB) *CategoryRepository {
+func NewCategoryRepository(db *store.Database) *CategoryRepository {
 	return &CategoryRepository{
 		db: db,
 	}
@@ -26,7 +25,7 @@ func (s *CategoryRepository) Select(ctx context.Context) (dest []category.Entity
 		FROM categories
 		ORDER BY id`
 
-	err = s.db.SelectContext(ctx, &dest, query)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query)
 
 	return
 }
@@ -39,7 +38,7 @@ func (s *CategoryRepository) Create(ctx context.Context, data category.Entity) (
 
 	args := []any{data.ID, data.Name, data.ParentId}
 
-	err = s.db.QueryRowContext(ctx, query, args...).Scan(&id)
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
 
 	return
 }
@@ -51,7 +50,7 @@ func (s *CategoryRepository) GetChilds(ctx context.Context, id string) (dest []c
 		WHERE parent_id=$1
 	`
 
-	err = s.db.SelectContext(ctx, &dest, query, id)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query, id)
 
 	fmt.Println(err)
 
@@ -60,13 +59,13 @@ func (s *CategoryRepository) GetChilds(ctx context.Context, id string) (dest []c
 
 func (s *CategoryRepository) Get(ctx context.Context, id string) (dest category.Entity, err error) {
 	query := `
-		SELECT id, name
+		SELECT id, name, parent_id
 		FROM categories
 		WHERE id=$1`
 
 	args := []any{id}
 
-	if err = s.db.GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+	if err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
 		return
 	}
 
@@ -87,13 +86,13 @@ func (s *CategoryRepository) Update(ctx context.Context, id string, data categor
 		sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
 
 		query := fmt.Sprintf("UPDATE categories SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
-		_, err = s.db.ExecContext(ctx, query, args...)
-		if err != nil && err != sql.ErrNoRows {
-			return
+		result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
 		}
 
-		if err == sql.ErrNoRows {
-			err = store.ErrorNotFound
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return store.ErrorNotFound
 		}
 	}
 
@@ -121,12 +120,12 @@ func (s *CategoryRepository) Delete(ctx context.Context, id string) (err error)
 
 	args := []any{id}
 
-	_, err = s.db.ExecContext(ctx, query, args...)
-	if err != nil && err != sql.ErrNoRows {
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
 		return
 	}
 
-	if err == sql.ErrNoRows {
+	if rows, _ := result.RowsAffected(); rows == 0 {
 		err = store.ErrorNotFound
 	}
 
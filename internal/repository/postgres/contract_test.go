@@ -0,0 +1,41 @@
+package postgres_test
+
+import (
+	"os"
+	"testing"
+
+	"product/internal/repository/contract"
+	"product/internal/repository/postgres"
+	"product/migrations"
+	"product/pkg/store"
+)
+
+// openDatabase migrates the database at TEST_POSTGRES_DSN, which must be a
+// scratch database, and skips the test when the variable is not set.
+func openDatabase(t *testing.T) *store.Database {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := store.NewDatabase("public", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if err = db.Migrate(migrations.FS); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// TestContract runs the repository contract against Postgres.
+func TestContract(t *testing.T) {
+	db := openDatabase(t)
+
+	contract.Run(t, postgres.NewCategoryRepository(db), postgres.NewProductRepository(db))
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"product/internal/domain/event"
+	"product/pkg/store"
+)
+
+type EventRepository struct {
+	db *store.Database
+}
+
+func NewEventRepository(db *store.Database) *EventRepository {
+	return &EventRepository{
+		db: db,
+	}
+}
+
+func (s *EventRepository) Create(ctx context.Context, data event.Entity) (id int64, err error) {
+	query := `
+		INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	args := []any{data.Type, data.AggregateType, data.AggregateID, data.Payload}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+// SelectPending returns nothing while the transaction of another relay holds
+// the outbox: a second relay skipping the locked head of the outbox would
+// publish the events after it first.
+func (s *EventRepository) SelectPending(ctx context.Context, limit int) (dest []event.Entity, err error) {
+	dest = make([]event.Entity, 0)
+
+	var locked bool
+	query := `SELECT pg_try_advisory_xact_lock(hashtext('outbox:' || current_schema()))`
+	if err = store.Conn(ctx, s.db).QueryRowContext(ctx, query).Scan(&locked); err != nil || !locked {
+		return
+	}
+
+	query = `
+		SELECT id, created_at, event_type, aggregate_type, aggregate_id, payload, published_at, attempts, last_error
+		FROM outbox
+		WHERE published_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE`
+
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &dest, query, limit)
+
+	return
+}
+
+func (s *EventRepository) MarkPublished(ctx context.Context, ids []int64) (err error) {
+	if len(ids) == 0 {
+		return
+	}
+
+	query := `
+		UPDATE outbox
+		SET published_at=CURRENT_TIMESTAMP, attempts=attempts+1, last_error=NULL
+		WHERE id = ANY($1)`
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, query, pq.Array(ids))
+
+	return
+}
+
+func (s *EventRepository) MarkFailed(ctx context.Context, id int64, lastError string) (err error) {
+	query := `
+		UPDATE outbox
+		SET attempts=attempts+1, last_error=$1
+		WHERE id=$2`
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, query, lastError, id)
+
+	return
+}
+
+func (s *EventRepository) SelectSince(ctx context.Context, txid, id int64, types []string, limit int) (dest []event.Entity, err error) {
+	query := `
+		SELECT id, created_at, event_type, aggregate_type, aggregate_id, payload, published_at, attempts, last_error, txid
+		FROM outbox
+		WHERE (txid, id) > ($1, $2) AND event_type = ANY($3)
+		  AND txid < txid_snapshot_xmin(txid_current_snapshot())
+		ORDER BY txid, id
+		LIMIT $4`
+
+	args := []any{txid, id, pq.Array(types), limit}
+
+	dest = make([]event.Entity, 0)
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &dest, query, args...)
+
+	return
+}
+
+func (s *EventRepository) Position(ctx context.Context) (txid, id int64, err error) {
+	query := `
+		SELECT txid, id
+		FROM outbox
+		WHERE txid < txid_snapshot_xmin(txid_current_snapshot())
+		ORDER BY txid DESC, id DESC
+		LIMIT 1`
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query).Scan(&txid, &id)
+	if err == sql.ErrNoRows {
+		err = nil
+	}
+
+	return
+}
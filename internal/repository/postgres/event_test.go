@@ -0,0 +1,64 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"product/internal/domain/event"
+	"product/internal/repository/postgres"
+)
+
+// TestSelectPendingOneRelayAtATime checks that a relay gets no events while
+// another one holds the outbox, rather than the events after the ones locked.
+func TestSelectPendingOneRelayAtATime(t *testing.T) {
+	db := openDatabase(t)
+	events := postgres.NewEventRepository(db)
+	ctx := context.Background()
+
+	for range 2 {
+		_, err := events.Create(ctx, event.Entity{
+			Type:          ptr("product.updated"),
+			AggregateType: ptr("product"),
+			AggregateID:   ptr("p1"),
+			Payload:       ptr(`{}`),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	holding, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- db.Transaction(ctx, func(ctx context.Context) error {
+			pending, err := events.SelectPending(ctx, 1)
+			if err == nil && len(pending) != 1 {
+				t.Errorf("first relay got %d events, want 1", len(pending))
+			}
+			close(holding)
+			<-release
+			return err
+		})
+	}()
+	<-holding
+
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		pending, err := events.SelectPending(ctx, 10)
+		if err == nil && len(pending) != 0 {
+			t.Errorf("second relay got %d events while the first holds the outbox, want none", len(pending))
+		}
+		return err
+	})
+	close(release)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"product/internal/domain/imports"
+	"product/pkg/store"
+)
+
+type ImportRepository struct {
+	db *store.Database
+}
+
+func NewImportRepository(db *store.Database) *ImportRepository {
+	return &ImportRepository{
+		db: db,
+	}
+}
+
+func (s *ImportRepository) Create(ctx context.Context, data imports.Entity) (id string, err error) {
+	query := `
+		INSERT INTO imports (id, job_id, status, filename, format, mapping, dry_run, batch_size, file)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id`
+
+	args := []any{data.ID, data.JobID, data.Status, data.Filename, data.Format, data.Mapping, data.DryRun, data.BatchSize, data.File}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *ImportRepository) Get(ctx context.Context, id string) (dest imports.Entity, err error) {
+	query := `
+		SELECT id, created_at, job_id, status, filename, format, mapping, dry_run, batch_size,
+		       total_rows, processed_rows, upserted_rows, failed_rows, error, file
+		FROM imports
+		WHERE id=$1`
+
+	args := []any{id}
+
+	if err = store.Conn(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *ImportRepository) Update(ctx context.Context, id string, data imports.Entity) (err error) {
+	sets, args := s.prepareArgs(data)
+	if len(args) > 0 {
+		args = append(args, id)
+		sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+
+		query := fmt.Sprintf("UPDATE imports SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
+		result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return store.ErrorNotFound
+		}
+	}
+
+	return
+}
+
+func (s *ImportRepository) prepareArgs(data imports.Entity) (sets []string, args []any) {
+	if data.JobID != nil {
+		args = append(args, data.JobID)
+		sets = append(sets, fmt.Sprintf("job_id=$%d", len(args)))
+	}
+
+	if data.Status != nil {
+		args = append(args, data.Status)
+		sets = append(sets, fmt.Sprintf("status=$%d", len(args)))
+	}
+
+	if data.TotalRows != nil {
+		args = append(args, data.TotalRows)
+		sets = append(sets, fmt.Sprintf("total_rows=$%d", len(args)))
+	}
+
+	if data.ProcessedRows != nil {
+		args = append(args, data.ProcessedRows)
+		sets = append(sets, fmt.Sprintf("processed_rows=$%d", len(args)))
+	}
+
+	if data.UpsertedRows != nil {
+		args = append(args, data.UpsertedRows)
+		sets = append(sets, fmt.Sprintf("upserted_rows=$%d", len(args)))
+	}
+
+	if data.FailedRows != nil {
+		args = append(args, data.FailedRows)
+		sets = append(sets, fmt.Sprintf("failed_rows=$%d", len(args)))
+	}
+
+	if data.Error != nil {
+		args = append(args, data.Error)
+		sets = append(sets, fmt.Sprintf("error=NULLIF($%d, '')", len(args)))
+	}
+
+	return
+}
+
+func (s *ImportRepository) AddErrors(ctx context.Context, id string, errs []imports.RowError) (err error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	query := `
+		INSERT INTO import_errors (import_id, row, field, message)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING`
+
+	return store.WithTransaction(ctx, s.db, func(ctx context.Context) (err error) {
+		for _, e := range errs {
+			if _, err = store.Conn(ctx, s.db).ExecContext(ctx, query, id, e.Row, e.Field, e.Message); err != nil {
+				return
+			}
+		}
+		return
+	})
+}
+
+func (s *ImportRepository) SelectErrors(ctx context.Context, id string) (dest []imports.RowError, err error) {
+	query := `
+		SELECT row, field, message
+		FROM import_errors
+		WHERE import_id=$1
+		ORDER BY row, field`
+
+	dest = make([]imports.RowError, 0)
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &dest, query, id)
+
+	return
+}
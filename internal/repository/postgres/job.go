@@ -0,0 +1,227 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"product/internal/domain/job"
+	"product/pkg/store"
+)
+
+const jobColumns = `id, created_at, type, status, payload, checkpoint, result, progress, total, attempts, max_attempts,
+		run_at, last_error, cancel_requested, locked_by, actor, request_id, started_at, finished_at`
+
+type JobRepository struct {
+	db *store.Database
+}
+
+func NewJobRepository(db *store.Database) *JobRepository {
+	return &JobRepository{
+		db: db,
+	}
+}
+
+func (s *JobRepository) Create(ctx context.Context, data job.Entity) (id string, err error) {
+	query := `
+		INSERT INTO jobs (id, type, status, payload, max_attempts, actor, request_id)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''))
+		RETURNING id`
+
+	args := []any{data.ID, data.Type, job.StatusQueued, data.Payload, data.MaxAttempts, data.Actor, data.RequestID}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *JobRepository) Get(ctx context.Context, id string) (dest job.Entity, err error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE id=$1`
+
+	args := []any{id}
+
+	if err = store.Conn(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *JobRepository) Claim(ctx context.Context, worker string, types []string) (dest job.Entity, err error) {
+	query := `
+		UPDATE jobs
+		SET status=$1, attempts=attempts+1, locked_by=$2, locked_at=CURRENT_TIMESTAMP,
+		    started_at=COALESCE(started_at, CURRENT_TIMESTAMP), updated_at=CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id
+			FROM jobs
+			WHERE status=$3 AND run_at <= CURRENT_TIMESTAMP AND type = ANY($4)
+			ORDER BY run_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1)
+		RETURNING ` + jobColumns
+
+	args := []any{job.StatusRunning, worker, job.StatusQueued, pq.Array(types)}
+
+	if err = store.Conn(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *JobRepository) Heartbeat(ctx context.Context, id, worker string) (cancelRequested bool, err error) {
+	query := `
+		UPDATE jobs
+		SET locked_at=CURRENT_TIMESTAMP
+		WHERE id=$1 AND locked_by=$2 AND status=$3
+		RETURNING cancel_requested`
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, id, worker, job.StatusRunning).Scan(&cancelRequested)
+	if err == sql.ErrNoRows {
+		err = job.ErrLockLost
+	}
+
+	return
+}
+
+func (s *JobRepository) Progress(ctx context.Context, id, worker string, progress, total int, checkpoint *string) (err error) {
+	query := `
+		UPDATE jobs
+		SET progress=$1, total=$2, checkpoint=COALESCE($3, checkpoint), locked_at=CURRENT_TIMESTAMP, updated_at=CURRENT_TIMESTAMP
+		WHERE id=$4 AND locked_by=$5 AND status=$6`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, progress, total, checkpoint, id, worker, job.StatusRunning)
+
+	return lockHeld(result, err)
+}
+
+func (s *JobRepository) Finish(ctx context.Context, id, worker, status string, result, lastError *string) (err error) {
+	query := `
+		UPDATE jobs
+		SET status=$1, result=$2, last_error=$3, locked_by=NULL, locked_at=NULL,
+		    finished_at=CURRENT_TIMESTAMP, updated_at=CURRENT_TIMESTAMP
+		WHERE id=$4 AND locked_by=$5 AND status=$6`
+
+	res, err := store.Conn(ctx, s.db).ExecContext(ctx, query, status, result, lastError, id, worker, job.StatusRunning)
+
+	return lockHeld(res, err)
+}
+
+func (s *JobRepository) Retry(ctx context.Context, id, worker string, delay time.Duration, lastError string) (err error) {
+	query := `
+		UPDATE jobs
+		SET status=$1, run_at=CURRENT_TIMESTAMP + make_interval(secs => $2), last_error=$3,
+		    locked_by=NULL, locked_at=NULL, updated_at=CURRENT_TIMESTAMP
+		WHERE id=$4 AND locked_by=$5 AND status=$6`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, job.StatusQueued, delay.Seconds(), lastError, id, worker, job.StatusRunning)
+
+	return lockHeld(result, err)
+}
+
+func (s *JobRepository) Release(ctx context.Context, id, worker string) (err error) {
+	query := `
+		UPDATE jobs
+		SET status=$1, attempts=GREATEST(attempts-1, 0), locked_by=NULL, locked_at=NULL, updated_at=CURRENT_TIMESTAMP
+		WHERE id=$2 AND locked_by=$3 AND status=$4`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, job.StatusQueued, id, worker, job.StatusRunning)
+
+	return lockHeld(result, err)
+}
+
+// lockHeld turns an update of a running job that matched no row into
+// job.ErrLockLost.
+func (s *JobRepository) SaveFile(ctx context.Context, id, worker string, content []byte) (err error) {
+	query := `
+		INSERT INTO job_files (job_id, content)
+		SELECT id, $1
+		FROM jobs
+		WHERE id=$2 AND locked_by=$3 AND status=$4
+		ON CONFLICT (job_id) DO UPDATE SET content=EXCLUDED.content, created_at=CURRENT_TIMESTAMP`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, content, id, worker, job.StatusRunning)
+
+	return lockHeld(result, err)
+}
+
+func (s *JobRepository) File(ctx context.Context, id string) (content []byte, err error) {
+	query := `
+		SELECT content
+		FROM job_files
+		WHERE job_id=$1`
+
+	args := []any{id}
+
+	if err = store.Conn(ctx, s.db).GetContext(ctx, &content, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func lockHeld(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return job.ErrLockLost
+	}
+
+	return nil
+}
+
+// Cancel cancels a queued job right away and asks the worker running it to stop otherwise.
+func (s *JobRepository) Cancel(ctx context.Context, id string) (err error) {
+	query := `
+		UPDATE jobs
+		SET cancel_requested=TRUE,
+		    status=CASE WHEN status=$1 THEN $2 ELSE status END,
+		    finished_at=CASE WHEN status=$1 THEN CURRENT_TIMESTAMP ELSE finished_at END,
+		    updated_at=CURRENT_TIMESTAMP
+		WHERE id=$3`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, job.StatusQueued, job.StatusCanceled, id)
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *JobRepository) RequeueStale(ctx context.Context, timeout time.Duration) (count int64, err error) {
+	query := `
+		UPDATE jobs
+		SET status=$1, locked_by=NULL, locked_at=NULL, updated_at=CURRENT_TIMESTAMP
+		WHERE status=$2 AND locked_at < CURRENT_TIMESTAMP - make_interval(secs => $3)`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, job.StatusQueued, job.StatusRunning, timeout.Seconds())
+	if err != nil {
+		return
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"product/internal/domain/modifier"
+	"product/pkg/store"
+)
+
+type ModifierRepository struct {
+	db *store.Database
+}
+
+func NewModifierRepository(db *store.Database) *ModifierRepository {
+	return &ModifierRepository{
+		db: db,
+	}
+}
+
+func (s *ModifierRepository) Select(ctx context.Context) (dest []modifier.Entity, err error) {
+	query := `
+		SELECT id, name, min_select, max_select
+		FROM modifier_groups
+		ORDER BY name`
+
+	dest = make([]modifier.Entity, 0)
+	if err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query); err != nil {
+		return
+	}
+
+	err = s.loadOptions(ctx, dest)
+
+	return
+}
+
+func (s *ModifierRepository) Create(ctx context.Context, data modifier.Entity) (id string, err error) {
+	query := `
+		INSERT INTO modifier_groups (id, name, min_select, max_select)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id`
+
+	args := []any{data.ID, data.Name, data.MinSelect, data.MaxSelect}
+
+	err = store.WithTransaction(ctx, s.db, func(ctx context.Context) (err error) {
+		if err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id); err != nil {
+			return
+		}
+
+		return s.insertOptions(ctx, id, data.Options)
+	})
+
+	return
+}
+
+func (s *ModifierRepository) Get(ctx context.Context, id string) (dest modifier.Entity, err error) {
+	query := `
+		SELECT id, name, min_select, max_select
+		FROM modifier_groups
+		WHERE id=$1`
+
+	args := []any{id}
+
+	if err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+		return
+	}
+
+	groups := []modifier.Entity{dest}
+	if err = s.loadOptions(ctx, groups); err != nil {
+		return
+	}
+	dest = groups[0]
+
+	return
+}
+
+func (s *ModifierRepository) Update(ctx context.Context, id string, data modifier.Entity) (err error) {
+	return store.WithTransaction(ctx, s.db, func(ctx context.Context) (err error) {
+		sets, args := s.prepareArgs(data)
+		if len(args) > 0 {
+			args = append(args, id)
+			sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+
+			query := fmt.Sprintf("UPDATE modifier_groups SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
+			result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+
+			if rows, _ := result.RowsAffected(); rows == 0 {
+				return store.ErrorNotFound
+			}
+		}
+
+		if data.Options != nil {
+			if _, err = store.Conn(ctx, s.db).ExecContext(ctx, "DELETE FROM modifier_options WHERE group_id=$1", id); err != nil {
+				return
+			}
+
+			err = s.insertOptions(ctx, id, data.Options)
+		}
+
+		return
+	})
+}
+
+func (s *ModifierRepository) prepareArgs(data modifier.Entity) (sets []string, args []any) {
+	if data.Name != nil {
+		args = append(args, data.Name)
+		sets = append(sets, fmt.Sprintf("name=$%d", len(args)))
+	}
+
+	if data.MinSelect != nil {
+		args = append(args, data.MinSelect)
+		sets = append(sets, fmt.Sprintf("min_select=$%d", len(args)))
+	}
+
+	if data.MaxSelect != nil {
+		args = append(args, data.MaxSelect)
+		sets = append(sets, fmt.Sprintf("max_select=$%d", len(args)))
+	}
+
+	return
+}
+
+func (s *ModifierRepository) Delete(ctx context.Context, id string) (err error) {
+	query := `
+		DELETE
+		FROM modifier_groups
+		WHERE id=$1`
+
+	args := []any{id}
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *ModifierRepository) SelectByProduct(ctx context.Context, productID string) (dest []modifier.Entity, err error) {
+	query := `
+		SELECT g.id, g.name, g.min_select, g.max_select
+		FROM modifier_groups g
+		JOIN product_modifier_groups pg ON pg.group_id = g.id
+		WHERE pg.product_id=$1
+		ORDER BY pg.position`
+
+	dest = make([]modifier.Entity, 0)
+	if err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query, productID); err != nil {
+		return
+	}
+
+	err = s.loadOptions(ctx, dest)
+
+	return
+}
+
+func (s *ModifierRepository) Attach(ctx context.Context, productID string, groupIDs []string) (err error) {
+	query := `
+		INSERT INTO product_modifier_groups (product_id, group_id, position)
+		VALUES ($1, $2, $3)`
+
+	return store.WithTransaction(ctx, s.db, func(ctx context.Context) (err error) {
+		if _, err = store.Conn(ctx, s.db).ExecContext(ctx, "DELETE FROM product_modifier_groups WHERE product_id=$1", productID); err != nil {
+			return
+		}
+
+		for position, groupID := range groupIDs {
+			if _, err = store.Conn(ctx, s.db).ExecContext(ctx, query, productID, groupID, position); err != nil {
+				return
+			}
+		}
+
+		return
+	})
+}
+
+func (s *ModifierRepository) insertOptions(ctx context.Context, groupID string, options []modifier.Option) (err error) {
+	query := `
+		INSERT INTO modifier_options (id, group_id, name, price_delta, is_default, position)
+		VALUES ($1, $2, $3, $4, $5, $6)`
+
+	for position, option := range options {
+		if option.ID == "" {
+			option.ID = uuid.New().String()
+		}
+
+		args := []any{option.ID, groupID, option.Name, option.PriceDelta, option.IsDefault, position}
+		if _, err = store.Conn(ctx, s.db).ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *ModifierRepository) loadOptions(ctx context.Context, groups []modifier.Entity) (err error) {
+	if len(groups) == 0 {
+		return
+	}
+
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+	}
+
+	query := `
+		SELECT id, group_id, name, price_delta, is_default
+		FROM modifier_options
+		WHERE group_id = ANY($1)
+		ORDER BY position`
+
+	var options []modifier.Option
+	if err = store.Read(ctx, s.db).SelectContext(ctx, &options, query, pq.Array(ids)); err != nil {
+		return
+	}
+
+	byGroup := make(map[string][]modifier.Option, len(groups))
+	for _, option := range options {
+		byGroup[*option.GroupID] = append(byGroup[*option.GroupID], option)
+	}
+
+	for i := range groups {
+		groups[i].Options = byGroup[groups[i].ID]
+		if groups[i].Options == nil {
+			groups[i].Options = make([]modifier.Option, 0)
+		}
+	}
+
+	return
+}
@@ -4,84 +4,167 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"net/http"
-	"strconv"
 	"strings"
 
-	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"product/internal/domain/product"
 	"product/pkg/store"
 )
 
+const streamBatchSize = 500
+
 type ProductRepository struct {
-	db *sqlx.DB
+	db *store.Database
 }
 
-func NewProductRepository(db *sqlx.DB) *ProductRepository {
+func NewProductRepository(db *store.Database) *ProductRepository {
 	return &ProductRepository{
 		db: db,
 	}
 }
 
-func (s *ProductRepository) Select(ctx context.Context, r *http.Request) (dest []product.Entity, err error) {
-	filters, args := s.prepareFilters(r)
-	query := fmt.Sprintf(`SELECT id, category_id, barcode, name, measure, cost, producer_country, brand_name, description, image, is_weighted `+
-		`FROM products WHERE %s`, strings.Join(filters, " "))
+func (s *ProductRepository) Select(ctx context.Context, filters product.Filters) (dest []product.Entity, err error) {
+	conditions, args := s.prepareFilters(filters)
+	query := fmt.Sprintf(`SELECT p.id, p.category_id, p.barcode, p.name, p.measure, p.cost, p.producer_country, p.brand_id, b.name AS brand_name, p.supplier_id, p.description, p.image, p.is_weighted `+
+		`FROM products p LEFT JOIN brands b ON b.id = p.brand_id WHERE %s`, strings.Join(conditions, " "))
 	query += " 1=1"
 
-	fmt.Println(query, args)
-
 	dest = make([]product.Entity, 0)
-	err = s.db.SelectContext(ctx, &dest, query, args...)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query, args...)
 
 	return
 }
 
-func (s *ProductRepository) prepareFilters(r *http.Request) (filters []string, args []any) {
-	priceGTEFilter, err := strconv.Atoi(r.URL.Query().Get("cost_gte"))
-	if err == nil {
-		args = append(args, priceGTEFilter)
-		filters = append(filters, fmt.Sprintf("cost >= $%d AND", len(args)))
+// Stream passes every product matching the filters to fn. Rows are read through
+// a server-side cursor in batches of streamBatchSize, so memory use stays flat
+// no matter how large the catalog is.
+func (s *ProductRepository) Stream(ctx context.Context, filters product.Filters, fn func(product.Entity) error) (err error) {
+	return store.WithReadTransaction(ctx, s.db, func(ctx context.Context) (err error) {
+		conn := store.Read(ctx, s.db)
+
+		conditions, args := s.prepareFilters(filters)
+		query := fmt.Sprintf(`DECLARE products_stream NO SCROLL CURSOR FOR `+
+			`SELECT p.id, p.category_id, p.barcode, p.name, p.measure, p.cost, p.producer_country, p.brand_id, b.name AS brand_name, p.supplier_id, sp.name AS supplier_name, p.description, p.image, p.is_weighted `+
+			`FROM products p LEFT JOIN brands b ON b.id = p.brand_id LEFT JOIN suppliers sp ON sp.id = p.supplier_id WHERE %s 1=1 ORDER BY p.id`, strings.Join(conditions, " "))
+
+		if _, err = conn.ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+
+		fetch := fmt.Sprintf("FETCH %d FROM products_stream", streamBatchSize)
+		for {
+			batch := make([]product.Entity, 0, streamBatchSize)
+			if err = conn.SelectContext(ctx, &batch, fetch); err != nil {
+				return
+			}
+
+			for _, data := range batch {
+				if err = fn(data); err != nil {
+					return
+				}
+			}
+
+			if len(batch) < streamBatchSize {
+				break
+			}
+		}
+
+		// The cursor outlives this call when ctx carries a transaction of its own.
+		_, err = conn.ExecContext(ctx, "CLOSE products_stream")
+
+		return
+	})
+}
+
+func (s *ProductRepository) prepareFilters(filters product.Filters) (conditions []string, args []any) {
+	if filters.CostGTE != nil {
+		args = append(args, *filters.CostGTE)
+		conditions = append(conditions, fmt.Sprintf("p.cost >= $%d AND", len(args)))
+	}
+
+	if filters.CostLTE != nil {
+		args = append(args, *filters.CostLTE)
+		conditions = append(conditions, fmt.Sprintf("p.cost <= $%d AND", len(args)))
 	}
 
-	priceLTEFilter, err := strconv.Atoi(r.URL.Query().Get("cost_lte"))
-	if err == nil {
-		args = append(args, priceLTEFilter)
-		filters = append(filters, fmt.Sprintf("cost <= $%d AND", len(args)))
+	if filters.Search != "" {
+		args = append(args, "%"+filters.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("p.name LIKE $%d AND", len(args)))
 	}
 
-	searchFilter := strings.ToLower(r.URL.Query().Get("search"))
-	if searchFilter != "" {
-		args = append(args, "%"+searchFilter+"%")
-		filters = append(filters, fmt.Sprintf("name LIKE $%d AND", len(args)))
+	if filters.BrandID != "" {
+		args = append(args, filters.BrandID)
+		conditions = append(conditions, fmt.Sprintf("p.brand_id = $%d AND", len(args)))
 	}
+
+	if filters.SupplierID != "" {
+		args = append(args, filters.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("p.supplier_id = $%d AND", len(args)))
+	}
+
+	if filters.ProducerCountry != "" {
+		args = append(args, filters.ProducerCountry)
+		conditions = append(conditions, fmt.Sprintf("p.producer_country = $%d AND", len(args)))
+	}
+	return
+}
+
+func (s *ProductRepository) Count(ctx context.Context, filters product.Filters) (count int, err error) {
+	conditions, args := s.prepareFilters(filters)
+	query := fmt.Sprintf(`SELECT COUNT(*) FROM products p WHERE %s 1=1`, strings.Join(conditions, " "))
+
+	err = store.Read(ctx, s.db).GetContext(ctx, &count, query, args...)
+
+	return
+}
+
+// AdjustCosts rescales the cost of at most limit products matching the filters
+// whose id sorts after afterID, and returns the changes ordered by id.
+func (s *ProductRepository) AdjustCosts(ctx context.Context, filters product.Filters, percent float64, delta int, afterID string, limit int) (dest []product.CostChange, err error) {
+	conditions, args := s.prepareFilters(filters)
+	args = append(args, afterID, limit, percent, delta)
+	query := fmt.Sprintf(`
+		WITH batch AS (
+			SELECT p.id, p.cost FROM products p WHERE %s p.id > $%d ORDER BY p.id LIMIT $%d FOR UPDATE),
+		updated AS (
+			UPDATE products
+			SET cost=GREATEST(ROUND(COALESCE(products.cost, 0) * (1 + $%d::NUMERIC / 100)) + $%d, 0), updated_at=CURRENT_TIMESTAMP
+			FROM batch
+			WHERE products.id = batch.id
+			RETURNING products.id, COALESCE(batch.cost, 0) AS before, products.cost AS after)
+		SELECT id, before, after FROM updated ORDER BY id`, strings.Join(conditions, " "), len(args)-3, len(args)-2, len(args)-1, len(args))
+
+	dest = make([]product.CostChange, 0)
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &dest, query, args...)
+
 	return
 }
 
 func (s *ProductRepository) Create(ctx context.Context, data product.Entity) (id string, err error) {
 	query := `
-		INSERT INTO products (id,category_id, barcode, name, measure, cost, producer_country, brand_name, description, image, is_weighted)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		INSERT INTO products (id,category_id, barcode, name, measure, cost, producer_country, brand_id, supplier_id, description, image, is_weighted)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), $10, $11, $12)
 		RETURNING id`
 
 	args := []any{data.ID, data.CategoryID, data.Barcode, data.Name, data.Measure, data.Cost, data.ProducerCountry,
-		data.BrandName, data.Description, data.Image, data.IsWeighted}
+		data.BrandID, data.SupplierID, data.Description, data.Image, data.IsWeighted}
 
-	err = s.db.QueryRowContext(ctx, query, args...).Scan(&id)
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
 
 	return
 }
 
 func (s *ProductRepository) Get(ctx context.Context, id string) (dest product.Entity, err error) {
 	query := `
-		SELECT id, category_id, barcode, name, measure, cost, producer_country, brand_name, description, image, is_weighted
-		FROM products
-		WHERE id=$1`
+		SELECT p.id, p.category_id, p.barcode, p.name, p.measure, p.cost, p.producer_country, p.brand_id, b.name AS brand_name, p.supplier_id, p.description, p.image, p.is_weighted
+		FROM products p
+		LEFT JOIN brands b ON b.id = p.brand_id
+		WHERE p.id=$1`
 
 	args := []any{id}
 
-	if err = s.db.GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+	if err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
 		return
 	}
 
@@ -92,6 +175,71 @@ func (s *ProductRepository) Get(ctx context.Context, id string) (dest product.En
 	return
 }
 
+func (s *ProductRepository) GetByBarcode(ctx context.Context, barcode string) (dest product.Entity, err error) {
+	query := `
+		SELECT p.id, p.category_id, p.barcode, p.name, p.measure, p.cost, p.producer_country, p.brand_id, b.name AS brand_name, p.supplier_id, p.description, p.image, p.is_weighted
+		FROM products p
+		LEFT JOIN brands b ON b.id = p.brand_id
+		WHERE p.barcode=$1`
+
+	args := []any{barcode}
+
+	if err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *ProductRepository) SelectByBarcodes(ctx context.Context, barcodes []string) (dest []product.Entity, err error) {
+	query := `
+		SELECT p.id, p.category_id, p.barcode, p.name, p.measure, p.cost, p.producer_country, p.brand_id, b.name AS brand_name, p.supplier_id, p.description, p.image, p.is_weighted
+		FROM products p
+		LEFT JOIN brands b ON b.id = p.brand_id
+		WHERE p.barcode = ANY($1)`
+
+	dest = make([]product.Entity, 0)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query, pq.Array(barcodes))
+
+	return
+}
+
+// Upsert inserts the products or updates the ones whose barcode already exists.
+// All rows are written in a single transaction.
+func (s *ProductRepository) Upsert(ctx context.Context, data []product.Entity) (err error) {
+	query := `
+		INSERT INTO products (id, category_id, barcode, name, measure, cost, producer_country, brand_id, supplier_id, description, image, is_weighted)
+		VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), $10, $11, $12)
+		ON CONFLICT (barcode) DO UPDATE SET
+			category_id=EXCLUDED.category_id,
+			name=EXCLUDED.name,
+			measure=EXCLUDED.measure,
+			cost=EXCLUDED.cost,
+			producer_country=EXCLUDED.producer_country,
+			brand_id=EXCLUDED.brand_id,
+			supplier_id=EXCLUDED.supplier_id,
+			description=EXCLUDED.description,
+			image=EXCLUDED.image,
+			is_weighted=EXCLUDED.is_weighted,
+			updated_at=CURRENT_TIMESTAMP`
+
+	return store.WithTransaction(ctx, s.db, func(ctx context.Context) (err error) {
+		for _, row := range data {
+			args := []any{row.ID, row.CategoryID, row.Barcode, row.Name, row.Measure, row.Cost, row.ProducerCountry,
+				row.BrandID, row.SupplierID, row.Description, row.Image, row.IsWeighted}
+
+			if _, err = store.Conn(ctx, s.db).ExecContext(ctx, query, args...); err != nil {
+				return
+			}
+		}
+		return
+	})
+}
+
 func (s *ProductRepository) Update(ctx context.Context, id string, data product.Entity) (err error) {
 	sets, args := s.prepareArgs(data)
 	if len(args) > 0 {
@@ -99,14 +247,25 @@ func (s *ProductRepository) Update(ctx context.Context, id string, data product.
 		args = append(args, id)
 		sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
 		query := fmt.Sprintf("UPDATE products SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
-		_, err = s.db.ExecContext(ctx, query, args...)
-
+		result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return store.ErrorNotFound
+		}
 	}
 
 	return
 }
 
 func (s *ProductRepository) prepareArgs(data product.Entity) (sets []string, args []any) {
+	if data.CategoryID != nil {
+		args = append(args, data.CategoryID)
+		sets = append(sets, fmt.Sprintf("category_id=$%d", len(args)))
+	}
+
 	if data.Barcode != nil {
 		args = append(args, data.Barcode)
 		sets = append(sets, fmt.Sprintf("barcode=$%d", len(args)))
@@ -129,12 +288,17 @@ func (s *ProductRepository) prepareArgs(data product.Entity) (sets []string, arg
 
 	if data.ProducerCountry != nil {
 		args = append(args, data.ProducerCountry)
-		sets = append(sets, fmt.Sprintf("producer_country=$%d", len(args)))
+		sets = append(sets, fmt.Sprintf("producer_country=NULLIF($%d, '')", len(args)))
+	}
+
+	if data.BrandID != nil {
+		args = append(args, data.BrandID)
+		sets = append(sets, fmt.Sprintf("brand_id=NULLIF($%d, '')", len(args)))
 	}
 
-	if data.BrandName != nil {
-		args = append(args, data.BrandName)
-		sets = append(sets, fmt.Sprintf("brand_name=$%d", len(args)))
+	if data.SupplierID != nil {
+		args = append(args, data.SupplierID)
+		sets = append(sets, fmt.Sprintf("supplier_id=NULLIF($%d, '')", len(args)))
 	}
 
 	if data.Description != nil {
@@ -163,7 +327,26 @@ func (s *ProductRepository) Delete(ctx context.Context, id string) (err error) {
 
 	args := []any{id}
 
-	_, err = s.db.ExecContext(ctx, query, args...)
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *ProductRepository) SelectUnmatched(ctx context.Context) (dest []product.UnmatchedReference, err error) {
+	query := `
+		SELECT product_id, field, value
+		FROM unmatched_references
+		ORDER BY field, value`
+
+	dest = make([]product.UnmatchedReference, 0)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query)
 
 	return
 }
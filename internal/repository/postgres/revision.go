@@ -0,0 +1,69 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"product/internal/domain/revision"
+	"product/pkg/store"
+)
+
+type RevisionRepository struct {
+	db *store.Database
+}
+
+func NewRevisionRepository(db *store.Database) *RevisionRepository {
+	return &RevisionRepository{
+		db: db,
+	}
+}
+
+// Create numbers the revision after the last one of the entity. Concurrent
+// changes of the same entity are serialized by the row lock the change itself
+// takes, so the numbers stay gapless.
+func (s *RevisionRepository) Create(ctx context.Context, data revision.Entity) (number int, err error) {
+	query := `
+		INSERT INTO revisions (entity_type, entity_id, revision, actor, request_id, operation, snapshot)
+		SELECT $1, $2, COALESCE(MAX(revision), 0) + 1, $3, NULLIF($4, ''), $5, $6
+		FROM revisions
+		WHERE entity_type=$1 AND entity_id=$2
+		RETURNING revision`
+
+	args := []any{data.EntityType, data.EntityID, data.Actor, data.RequestID, data.Operation, data.Snapshot}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&number)
+
+	return
+}
+
+func (s *RevisionRepository) Select(ctx context.Context, entityType, entityID string) (dest []revision.Entity, err error) {
+	query := `
+		SELECT entity_type, entity_id, revision, created_at, actor, request_id, operation, snapshot
+		FROM revisions
+		WHERE entity_type=$1 AND entity_id=$2
+		ORDER BY revision DESC`
+
+	dest = make([]revision.Entity, 0)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query, entityType, entityID)
+
+	return
+}
+
+func (s *RevisionRepository) Get(ctx context.Context, entityType, entityID string, number int) (dest revision.Entity, err error) {
+	query := `
+		SELECT entity_type, entity_id, revision, created_at, actor, request_id, operation, snapshot
+		FROM revisions
+		WHERE entity_type=$1 AND entity_id=$2 AND revision=$3`
+
+	args := []any{entityType, entityID, number}
+
+	if err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
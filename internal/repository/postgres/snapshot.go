@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"product/internal/domain/snapshot"
+	"product/pkg/store"
+)
+
+type SnapshotRepository struct {
+	db *store.Database
+}
+
+func NewSnapshotRepository(db *store.Database) *SnapshotRepository {
+	return &SnapshotRepository{
+		db: db,
+	}
+}
+
+func (s *SnapshotRepository) Select(ctx context.Context, limit int) (dest []snapshot.Entity, err error) {
+	query := `
+		SELECT version, created_at, hash, size, compressed_size, sync_token, categories, products
+		FROM snapshots
+		ORDER BY version DESC
+		LIMIT $1`
+
+	dest = make([]snapshot.Entity, 0)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query, limit)
+
+	return
+}
+
+func (s *SnapshotRepository) Create(ctx context.Context, data snapshot.Entity) (version int64, err error) {
+	query := `
+		INSERT INTO snapshots (hash, size, compressed_size, sync_token, categories, products, bundle)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING version`
+
+	args := []any{data.Hash, data.Size, data.CompressedSize, data.SyncToken, data.Categories, data.Products, data.Bundle}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&version)
+
+	return
+}
+
+func (s *SnapshotRepository) Get(ctx context.Context, version int64) (dest snapshot.Entity, err error) {
+	query := `
+		SELECT version, created_at, hash, size, compressed_size, sync_token, categories, products
+		FROM snapshots
+		WHERE version=$1`
+
+	args := []any{version}
+
+	if err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *SnapshotRepository) Latest(ctx context.Context) (dest snapshot.Entity, err error) {
+	query := `
+		SELECT version, created_at, hash, size, compressed_size, sync_token, categories, products
+		FROM snapshots
+		ORDER BY version DESC
+		LIMIT 1`
+
+	if err = store.Conn(ctx, s.db).GetContext(ctx, &dest, query); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *SnapshotRepository) Bundle(ctx context.Context, version int64) (bundle []byte, err error) {
+	query := `
+		SELECT bundle
+		FROM snapshots
+		WHERE version=$1`
+
+	args := []any{version}
+
+	if err = store.Conn(ctx, s.db).GetContext(ctx, &bundle, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *SnapshotRepository) Delta(ctx context.Context, from, to int64) (patch []byte, err error) {
+	query := `
+		SELECT patch
+		FROM snapshot_deltas
+		WHERE from_version=$1 AND to_version=$2`
+
+	args := []any{from, to}
+
+	if err = store.Read(ctx, s.db).GetContext(ctx, &patch, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *SnapshotRepository) CreateDelta(ctx context.Context, from, to int64, patch []byte) (err error) {
+	query := `
+		INSERT INTO snapshot_deltas (from_version, to_version, patch)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`
+
+	args := []any{from, to, patch}
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+
+	return
+}
+
+func (s *SnapshotRepository) Prune(ctx context.Context, keep int) (versions []int64, err error) {
+	query := `
+		DELETE
+		FROM snapshots
+		WHERE version NOT IN (
+			SELECT version
+			FROM snapshots
+			ORDER BY version DESC
+			LIMIT $1)
+		RETURNING version`
+
+	versions = make([]int64, 0)
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &versions, query, keep)
+
+	return
+}
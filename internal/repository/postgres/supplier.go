@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"product/internal/domain/supplier"
+	"product/pkg/store"
+)
+
+type SupplierRepository struct {
+	db *store.Database
+}
+
+func NewSupplierRepository(db *store.Database) *SupplierRepository {
+	return &SupplierRepository{
+		db: db,
+	}
+}
+
+func (s *SupplierRepository) Select(ctx context.Context) (dest []supplier.Entity, err error) {
+	query := `
+		SELECT id, name, country, email, phone
+		FROM suppliers
+		ORDER BY name`
+
+	dest = make([]supplier.Entity, 0)
+	err = store.Read(ctx, s.db).SelectContext(ctx, &dest, query)
+
+	return
+}
+
+func (s *SupplierRepository) Create(ctx context.Context, data supplier.Entity) (id string, err error) {
+	query := `
+		INSERT INTO suppliers (id, name, country, email, phone)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''))
+		RETURNING id`
+
+	args := []any{data.ID, data.Name, data.Country, data.Email, data.Phone}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *SupplierRepository) Get(ctx context.Context, id string) (dest supplier.Entity, err error) {
+	query := `
+		SELECT id, name, country, email, phone
+		FROM suppliers
+		WHERE id=$1`
+
+	args := []any{id}
+
+	if err = store.Read(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *SupplierRepository) Update(ctx context.Context, id string, data supplier.Entity) (err error) {
+	sets, args := s.prepareArgs(data)
+	if len(args) > 0 {
+		args = append(args, id)
+		sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+
+		query := fmt.Sprintf("UPDATE suppliers SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
+		result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return store.ErrorNotFound
+		}
+	}
+
+	return
+}
+
+func (s *SupplierRepository) prepareArgs(data supplier.Entity) (sets []string, args []any) {
+	if data.Name != nil {
+		args = append(args, data.Name)
+		sets = append(sets, fmt.Sprintf("name=$%d", len(args)))
+	}
+
+	if data.Country != nil {
+		args = append(args, data.Country)
+		sets = append(sets, fmt.Sprintf("country=NULLIF($%d, '')", len(args)))
+	}
+
+	if data.Email != nil {
+		args = append(args, data.Email)
+		sets = append(sets, fmt.Sprintf("email=NULLIF($%d, '')", len(args)))
+	}
+
+	if data.Phone != nil {
+		args = append(args, data.Phone)
+		sets = append(sets, fmt.Sprintf("phone=NULLIF($%d, '')", len(args)))
+	}
+
+	return
+}
+
+func (s *SupplierRepository) Delete(ctx context.Context, id string) (err error) {
+	query := `
+		DELETE
+		FROM suppliers
+		WHERE id=$1`
+
+	args := []any{id}
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
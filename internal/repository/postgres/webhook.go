@@ -0,0 +1,218 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"product/internal/domain/webhook"
+	"product/pkg/store"
+)
+
+const deliveryColumns = `d.id, d.created_at, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+		d.next_attempt_at, d.last_status_code, d.last_error, d.delivered_at`
+
+type WebhookRepository struct {
+	db *store.Database
+}
+
+func NewWebhookRepository(db *store.Database) *WebhookRepository {
+	return &WebhookRepository{
+		db: db,
+	}
+}
+
+func (s *WebhookRepository) Select(ctx context.Context) (dest []webhook.Entity, err error) {
+	query := `
+		SELECT id, created_at, url, event_types, secret, active
+		FROM webhooks
+		ORDER BY created_at`
+
+	dest = make([]webhook.Entity, 0)
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &dest, query)
+
+	return
+}
+
+func (s *WebhookRepository) Create(ctx context.Context, data webhook.Entity) (id string, err error) {
+	query := `
+		INSERT INTO webhooks (id, url, event_types, secret, active)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id`
+
+	args := []any{data.ID, data.URL, data.EventTypes, data.Secret, data.Active}
+
+	err = store.Conn(ctx, s.db).QueryRowContext(ctx, query, args...).Scan(&id)
+
+	return
+}
+
+func (s *WebhookRepository) Get(ctx context.Context, id string) (dest webhook.Entity, err error) {
+	query := `
+		SELECT id, created_at, url, event_types, secret, active
+		FROM webhooks
+		WHERE id=$1`
+
+	args := []any{id}
+
+	if err = store.Conn(ctx, s.db).GetContext(ctx, &dest, query, args...); err != nil && err != sql.ErrNoRows {
+		return
+	}
+
+	if err == sql.ErrNoRows {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *WebhookRepository) Update(ctx context.Context, id string, data webhook.Entity) (err error) {
+	sets, args := s.prepareArgs(data)
+	if len(args) > 0 {
+		args = append(args, id)
+		sets = append(sets, "updated_at=CURRENT_TIMESTAMP")
+
+		query := fmt.Sprintf("UPDATE webhooks SET %s WHERE id=$%d", strings.Join(sets, ", "), len(args))
+		result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if rows, _ := result.RowsAffected(); rows == 0 {
+			return store.ErrorNotFound
+		}
+	}
+
+	return
+}
+
+func (s *WebhookRepository) prepareArgs(data webhook.Entity) (sets []string, args []any) {
+	if data.URL != nil {
+		args = append(args, data.URL)
+		sets = append(sets, fmt.Sprintf("url=$%d", len(args)))
+	}
+
+	if data.EventTypes != nil {
+		args = append(args, data.EventTypes)
+		sets = append(sets, fmt.Sprintf("event_types=$%d", len(args)))
+	}
+
+	if data.Secret != nil {
+		args = append(args, data.Secret)
+		sets = append(sets, fmt.Sprintf("secret=$%d", len(args)))
+	}
+
+	if data.Active != nil {
+		args = append(args, data.Active)
+		sets = append(sets, fmt.Sprintf("active=$%d", len(args)))
+	}
+
+	return
+}
+
+func (s *WebhookRepository) Delete(ctx context.Context, id string) (err error) {
+	query := `
+		DELETE
+		FROM webhooks
+		WHERE id=$1`
+
+	args := []any{id}
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
+
+func (s *WebhookRepository) AddDeliveries(ctx context.Context, data []webhook.Delivery) (err error) {
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING`
+
+	for _, delivery := range data {
+		args := []any{delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.Payload}
+		if _, err = store.Conn(ctx, s.db).ExecContext(ctx, query, args...); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *WebhookRepository) SelectDeliveries(ctx context.Context, webhookID string, limit int) (dest []webhook.Delivery, err error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries d
+		WHERE d.webhook_id=$1
+		ORDER BY d.id DESC
+		LIMIT $2`
+
+	dest = make([]webhook.Delivery, 0)
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &dest, query, webhookID, limit)
+
+	return
+}
+
+func (s *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) (dest []webhook.Delivery, err error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET attempts=d.attempts+1, next_attempt_at=CURRENT_TIMESTAMP + make_interval(secs => $1)
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND d.id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status=$2 AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY next_attempt_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT $3)
+		RETURNING ` + deliveryColumns + `, w.url, w.secret`
+
+	args := []any{lease.Seconds(), webhook.DeliveryPending, limit}
+
+	dest = make([]webhook.Delivery, 0)
+	err = store.Conn(ctx, s.db).SelectContext(ctx, &dest, query, args...)
+
+	return
+}
+
+func (s *WebhookRepository) FinishDelivery(ctx context.Context, id int64, status string, statusCode int, lastError string, delay time.Duration) (err error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status=$1, last_status_code=NULLIF($2, 0), last_error=NULLIF($3, ''),
+		    next_attempt_at=CURRENT_TIMESTAMP + make_interval(secs => $4),
+		    delivered_at=CASE WHEN $1=$5 THEN CURRENT_TIMESTAMP ELSE delivered_at END
+		WHERE id=$6`
+
+	args := []any{status, statusCode, lastError, delay.Seconds(), webhook.DeliverySucceeded, id}
+
+	_, err = store.Conn(ctx, s.db).ExecContext(ctx, query, args...)
+
+	return
+}
+
+func (s *WebhookRepository) Redeliver(ctx context.Context, webhookID string, id int64) (err error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status=$1, attempts=0, next_attempt_at=CURRENT_TIMESTAMP
+		WHERE webhook_id=$2 AND id=$3`
+
+	result, err := store.Conn(ctx, s.db).ExecContext(ctx, query, webhook.DeliveryPending, webhookID, id)
+	if err != nil {
+		return
+	}
+
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		err = store.ErrorNotFound
+	}
+
+	return
+}
@@ -1,9 +1,27 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"product/internal/domain/apikey"
+	"product/internal/domain/audit"
+	"product/internal/domain/brand"
 	"product/internal/domain/category"
+	"product/internal/domain/event"
+	"product/internal/domain/imports"
+	"product/internal/domain/job"
+	"product/internal/domain/modifier"
 	"product/internal/domain/product"
+	"product/internal/domain/revision"
+	"product/internal/domain/snapshot"
+	"product/internal/domain/supplier"
+	"product/internal/domain/webhook"
+	"product/internal/repository/cache"
+	"product/internal/repository/memory"
 	"product/internal/repository/postgres"
+	"product/migrations"
 	"product/pkg/store"
 )
 
@@ -13,9 +31,23 @@ type Configuration func(r *Repository) error
 // Repository is an implementation of the Repository
 type Repository struct {
 	postgres *store.Database
+	cache    *cache.Cache
 
 	Category category.Repository
 	Product  product.Repository
+	Modifier modifier.Repository
+	Brand    brand.Repository
+	Supplier supplier.Repository
+	Import   imports.Repository
+	Job      job.Repository
+	Audit    audit.Repository
+	Revision revision.Repository
+	Event    event.Repository
+	Webhook  webhook.Repository
+	Snapshot snapshot.Repository
+	APIKey   apikey.Repository
+
+	Transactor store.Transactor
 }
 
 // New takes a variable amount of Configuration functions and returns a new Repository
@@ -38,26 +70,141 @@ func New(configs ...Configuration) (s *Repository, err error) {
 // Close closes the repository and prevents new queries from starting.
 // Close then waits for all queries that have started processing on the server to finish.
 func (r *Repository) Close() {
+	if r.cache != nil {
+		r.cache.Close()
+	}
 	if r.postgres != nil {
-		r.postgres.Client.Close()
+		r.postgres.Close()
+	}
+}
+
+// DB returns the database client, or nil when there is no postgres store.
+func (r *Repository) DB() *sql.DB {
+	if r.postgres == nil {
+		return nil
+	}
+	return r.postgres.Client.DB
+}
+
+// Replicas returns the clients of the read replicas of the postgres store.
+func (r *Repository) Replicas() (dest []*sql.DB) {
+	if r.postgres == nil {
+		return
+	}
+	for _, client := range r.postgres.Replicas() {
+		dest = append(dest, client.DB)
+	}
+	return
+}
+
+// Ping checks that the database answers. It always passes without a postgres store.
+func (r *Repository) Ping(ctx context.Context) error {
+	if r.postgres == nil {
+		return nil
+	}
+	return r.postgres.Client.PingContext(ctx)
+}
+
+// CheckMigrations checks that every embedded migration is applied and the
+// schema is not dirty, see store.Database.VerifyMigrations. It always passes
+// without a postgres store.
+func (r *Repository) CheckMigrations(ctx context.Context) error {
+	if r.postgres == nil {
+		return nil
 	}
+	return r.postgres.VerifyMigrations(ctx, migrations.FS)
 }
 
-// WithPostgresStore applies a postgres store to the Repository
-func WithPostgresStore(schema, dataSourceName string) Configuration {
+// PingCache checks that the cache backend answers, when there is one.
+func (r *Repository) PingCache(ctx context.Context) error {
+	if r.cache == nil {
+		return nil
+	}
+	return r.cache.Ping(ctx)
+}
+
+// WithPostgresStore applies a postgres store to the Repository, set up by
+// configs. The schema is first brought up to date according to migrate, one
+// of store.MigrateAuto, store.MigrateVerify or store.MigrateOff.
+func WithPostgresStore(schema, dataSourceName, migrate string, configs ...store.Configuration) Configuration {
 	return func(s *Repository) (err error) {
 		// Create the postgres store, if we needed parameters, such as connection strings they could be inputted here
-		s.postgres, err = store.NewDatabase(schema, dataSourceName)
+		s.postgres, err = store.NewDatabase(schema, dataSourceName, configs...)
 		if err != nil {
 			return
 		}
-		err = s.postgres.Migrate()
+
+		switch migrate {
+		case store.MigrateAuto:
+			err = s.postgres.Migrate(migrations.FS)
+		case store.MigrateVerify:
+			err = s.postgres.VerifyMigrations(context.Background(), migrations.FS)
+		case store.MigrateOff:
+		default:
+			err = fmt.Errorf("unknown migration mode %q", migrate)
+		}
 		if err != nil {
+			s.postgres.Close()
 			return
 		}
 
-		s.Category = postgres.NewCategoryRepository(s.postgres.Client)
-		s.Product = postgres.NewProductRepository(s.postgres.Client)
+		s.Transactor = s.postgres
+		s.Category = postgres.NewCategoryRepository(s.postgres)
+		s.Product = postgres.NewProductRepository(s.postgres)
+		s.Modifier = postgres.NewModifierRepository(s.postgres)
+		s.Brand = postgres.NewBrandRepository(s.postgres)
+		s.Supplier = postgres.NewSupplierRepository(s.postgres)
+		s.Import = postgres.NewImportRepository(s.postgres)
+		s.Job = postgres.NewJobRepository(s.postgres)
+		s.Audit = postgres.NewAuditRepository(s.postgres)
+		s.Revision = postgres.NewRevisionRepository(s.postgres)
+		s.Event = postgres.NewEventRepository(s.postgres)
+		s.Webhook = postgres.NewWebhookRepository(s.postgres)
+		s.Snapshot = postgres.NewSnapshotRepository(s.postgres)
+		s.APIKey = postgres.NewAPIKeyRepository(s.postgres)
+
+		return
+	}
+}
+
+// WithMemoryStore keeps the catalog in memory, with the same filtering,
+// uniqueness and not-found semantics as the postgres store, along with its
+// audit log, revisions, outbox and snapshots. Jobs, webhooks, modifier groups
+// and API keys are not kept: their repositories find nothing and refuse
+// writes. It is meant for tests and for trying out the catalog without a
+// database.
+func WithMemoryStore() Configuration {
+	return func(s *Repository) (err error) {
+		db := memory.NewStore()
+
+		s.Transactor = db
+		s.Category = memory.NewCategoryRepository(db)
+		s.Product = memory.NewProductRepository(db)
+		s.Brand = memory.NewBrandRepository(db)
+		s.Supplier = memory.NewSupplierRepository(db)
+		s.Audit = memory.NewAuditRepository(db)
+		s.Revision = memory.NewRevisionRepository(db)
+		s.Event = memory.NewEventRepository(db)
+		s.Modifier = memory.ModifierRepository{}
+		s.Import = memory.ImportRepository{}
+		s.Job = memory.JobRepository{}
+		s.Webhook = memory.WebhookRepository{}
+		s.Snapshot = memory.NewSnapshotRepository(db)
+		s.APIKey = memory.APIKeyRepository{}
+
+		return
+	}
+}
+
+// WithCache puts a read-through cache in front of the product and category
+// repositories, and drops cached products when their brand is renamed. It
+// must come after the store they read from.
+func WithCache(c *cache.Cache) Configuration {
+	return func(s *Repository) (err error) {
+		s.cache = c
+		s.Brand = cache.NewBrandRepository(s.Brand, s.Product, c)
+		s.Product = cache.NewProductRepository(s.Product, c)
+		s.Category = cache.NewCategoryRepository(s.Category, c)
 
 		return
 	}
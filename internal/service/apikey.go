@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/apikey"
+	"product/internal/domain/auth"
+	"product/pkg/apperror"
+	"product/pkg/store"
+)
+
+// apiKeyTouchInterval bounds how often the last use of a key is written.
+const apiKeyTouchInterval = time.Minute
+
+var (
+	// ErrInvalidAPIKey is returned for unknown, malformed, revoked and expired keys alike.
+	ErrInvalidAPIKey = apperror.New(apperror.Unauthorized, "apikey: invalid or expired key")
+	ErrAPIKeyRevoked = apperror.New(apperror.PreconditionFailed, "apikey: key was revoked")
+)
+
+func (s *Service) ListAPIKeys(ctx context.Context) (res []apikey.Response, err error) {
+	ctx, span := startSpan(ctx, "ListAPIKeys")
+	defer endSpan(span, &err)
+
+	data, err := s.apiKeyRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = apikey.ParseFromEntities(data)
+
+	return
+}
+
+// IssueAPIKey creates a key. The response is the only one carrying it.
+func (s *Service) IssueAPIKey(ctx context.Context, req apikey.Request) (res apikey.IssuedResponse, err error) {
+	ctx, span := startSpan(ctx, "IssueAPIKey")
+	defer endSpan(span, &err)
+
+	data := apikey.Entity{
+		Name:    &req.Name,
+		Scopes:  req.Scopes,
+		StoreID: &req.StoreID,
+	}
+	if req.ExpiresAt != nil {
+		expiresAt := req.ExpiresAt.UTC()
+		data.ExpiresAt = &expiresAt
+	}
+
+	return s.issueAPIKey(ctx, data)
+}
+
+func (s *Service) GetAPIKey(ctx context.Context, id string) (res apikey.Response, err error) {
+	ctx, span := startSpan(ctx, "GetAPIKey")
+	defer endSpan(span, &err)
+
+	data, err := s.apiKeyRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = apikey.ParseFromEntity(data)
+
+	return
+}
+
+// RotateAPIKey issues a key with the same name, scopes, store and expiry as
+// the key id, which keeps working for the grace period of req.
+func (s *Service) RotateAPIKey(ctx context.Context, id string, req apikey.RotateRequest) (res apikey.IssuedResponse, err error) {
+	ctx, span := startSpan(ctx, "RotateAPIKey")
+	defer endSpan(span, &err)
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		data, err := s.apiKeyRepository.Get(ctx, id)
+		if err != nil {
+			return
+		}
+		if data.RevokedAt != nil {
+			return ErrAPIKeyRevoked
+		}
+
+		data.RotatedFrom = &data.ID
+		if res, err = s.issueAPIKey(ctx, data); err != nil {
+			return
+		}
+
+		return s.apiKeyRepository.Expire(ctx, id, time.Duration(req.GracePeriod)*time.Second)
+	})
+
+	return
+}
+
+// RevokeAPIKey stops a key from working at once. Revoked keys are kept, so
+// the changes made with them can still be traced.
+func (s *Service) RevokeAPIKey(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "RevokeAPIKey")
+	defer endSpan(span, &err)
+
+	return s.apiKeyRepository.Revoke(ctx, id)
+}
+
+// AuthenticateAPIKey returns the identity of the device holding key. The
+// scopes of the key are granted as roles, so the key passes the same checks
+// as a bearer token.
+func (s *Service) AuthenticateAPIKey(ctx context.Context, key string) (identity auth.Identity, err error) {
+	ctx, span := startSpan(ctx, "AuthenticateAPIKey")
+	defer endSpan(span, &err)
+
+	prefix, err := apikey.Split(key)
+	if err != nil {
+		return identity, ErrInvalidAPIKey
+	}
+
+	data, err := s.apiKeyRepository.GetActive(ctx, prefix)
+	if apperror.Is(err, apperror.NotFound) {
+		return identity, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return
+	}
+
+	if !apikey.Matches(key, *data.Hash) {
+		return identity, ErrInvalidAPIKey
+	}
+
+	// Recording the use is not a write of the request, whose reads may
+	// still go to the replicas.
+	if err = s.apiKeyRepository.Touch(store.WithoutSession(ctx), data.ID, apiKeyTouchInterval); err != nil {
+		return
+	}
+
+	identity = auth.Identity{
+		Subject: "apikey:" + data.ID,
+		Name:    *data.Name,
+		Roles:   apikey.Roles(data.Scopes),
+		Scopes:  data.Scopes,
+	}
+	if data.StoreID != nil {
+		identity.StoreID = *data.StoreID
+	}
+
+	return
+}
+
+func (s *Service) issueAPIKey(ctx context.Context, data apikey.Entity) (res apikey.IssuedResponse, err error) {
+	key, prefix, err := apikey.Generate()
+	if err != nil {
+		return
+	}
+
+	hash := apikey.Hash(key)
+	data.ID = uuid.New().String()
+	data.Prefix = &prefix
+	data.Hash = &hash
+	data.LastUsedAt = nil
+
+	if data.ID, err = s.apiKeyRepository.Create(ctx, data); err != nil {
+		return
+	}
+
+	if data, err = s.apiKeyRepository.Get(ctx, data.ID); err != nil {
+		return
+	}
+	res = apikey.IssuedResponse{Response: apikey.ParseFromEntity(data), Key: key}
+
+	return
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"product/internal/domain/apikey"
+	"product/internal/domain/auth"
+	"product/internal/repository/memory"
+	"product/pkg/apperror"
+	"product/pkg/store"
+)
+
+// apiKeyRepository keeps keys in memory, with the expiry rules of the
+// api_keys queries.
+type apiKeyRepository struct {
+	mu   sync.Mutex
+	keys map[string]apikey.Entity
+}
+
+func (r *apiKeyRepository) Select(ctx context.Context) (dest []apikey.Entity, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, data := range r.keys {
+		dest = append(dest, data)
+	}
+	return
+}
+
+func (r *apiKeyRepository) Create(ctx context.Context, data apikey.Entity) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	data.CreatedAt = &now
+	r.keys[data.ID] = data
+	return data.ID, nil
+}
+
+func (r *apiKeyRepository) Get(ctx context.Context, id string) (apikey.Entity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, ok := r.keys[id]
+	if !ok {
+		return data, store.ErrorNotFound
+	}
+	return data, nil
+}
+
+func (r *apiKeyRepository) GetActive(ctx context.Context, prefix string) (apikey.Entity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, data := range r.keys {
+		if *data.Prefix == prefix && data.RevokedAt == nil && (data.ExpiresAt == nil || data.ExpiresAt.After(time.Now())) {
+			return data, nil
+		}
+	}
+	return apikey.Entity{}, store.ErrorNotFound
+}
+
+func (r *apiKeyRepository) Touch(ctx context.Context, id string, interval time.Duration) error {
+	return r.update(id, func(data *apikey.Entity) {
+		now := time.Now()
+		data.LastUsedAt = &now
+	})
+}
+
+func (r *apiKeyRepository) Expire(ctx context.Context, id string, after time.Duration) error {
+	return r.update(id, func(data *apikey.Entity) {
+		if expiresAt := time.Now().Add(after); data.ExpiresAt == nil || expiresAt.Before(*data.ExpiresAt) {
+			data.ExpiresAt = &expiresAt
+		}
+	})
+}
+
+func (r *apiKeyRepository) Revoke(ctx context.Context, id string) error {
+	return r.update(id, func(data *apikey.Entity) {
+		now := time.Now()
+		data.RevokedAt = &now
+	})
+}
+
+func (r *apiKeyRepository) update(id string, change func(data *apikey.Entity)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, ok := r.keys[id]
+	if !ok {
+		return store.ErrorNotFound
+	}
+	change(&data)
+	r.keys[id] = data
+	return nil
+}
+
+func newAPIKeyService(t *testing.T) *Service {
+	t.Helper()
+
+	s, err := New(
+		WithAPIKeyRepository(&apiKeyRepository{keys: make(map[string]apikey.Entity)}),
+		WithTransactor(memory.NewStore()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	s := newAPIKeyService(t)
+	ctx := context.Background()
+
+	issued, err := s.IssueAPIKey(ctx, apikey.Request{Name: "till 1", Scopes: []string{apikey.ScopeCatalogRead}, StoreID: "store-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity, err := s.AuthenticateAPIKey(ctx, issued.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := auth.Identity{
+		Subject: "apikey:" + issued.ID,
+		Name:    "till 1",
+		Roles:   []string{auth.RoleKioskReadOnly},
+		Scopes:  []string{apikey.ScopeCatalogRead},
+		StoreID: "store-1",
+	}
+	if !reflect.DeepEqual(identity, want) {
+		t.Errorf("got identity %+v, want %+v", identity, want)
+	}
+
+	used, err := s.GetAPIKey(ctx, issued.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used.LastUsedAt == nil {
+		t.Error("the use of the key is not recorded")
+	}
+
+	for _, key := range []string{"", "pk_0123abcd_secret", issued.Key[:len(issued.Key)-1] + "x"} {
+		if _, err = s.AuthenticateAPIKey(ctx, key); err != ErrInvalidAPIKey {
+			t.Errorf("AuthenticateAPIKey(%q) error = %v, want %v", key, err, ErrInvalidAPIKey)
+		}
+	}
+}
+
+func TestRotateAPIKey(t *testing.T) {
+	s := newAPIKeyService(t)
+	ctx := context.Background()
+
+	issued, err := s.IssueAPIKey(ctx, apikey.Request{Name: "till 1", Scopes: []string{apikey.ScopeCatalogWrite}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("with a grace period", func(t *testing.T) {
+		rotated, err := s.RotateAPIKey(ctx, issued.ID, apikey.RotateRequest{GracePeriod: 60})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rotated.Name != issued.Name || !reflect.DeepEqual(rotated.Scopes, issued.Scopes) {
+			t.Errorf("got %q with %v, want the name and scopes of the old key", rotated.Name, rotated.Scopes)
+		}
+		for _, key := range []string{issued.Key, rotated.Key} {
+			if _, err = s.AuthenticateAPIKey(ctx, key); err != nil {
+				t.Errorf("key rejected during the grace period: %v", err)
+			}
+		}
+
+		// Rotating again with no grace period stops the new key at once.
+		if _, err = s.RotateAPIKey(ctx, rotated.ID, apikey.RotateRequest{}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = s.AuthenticateAPIKey(ctx, rotated.Key); err != ErrInvalidAPIKey {
+			t.Errorf("got error %v after the rotation, want %v", err, ErrInvalidAPIKey)
+		}
+	})
+
+	t.Run("revoked", func(t *testing.T) {
+		if err := s.RevokeAPIKey(ctx, issued.ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.AuthenticateAPIKey(ctx, issued.Key); err != ErrInvalidAPIKey {
+			t.Errorf("got error %v for a revoked key, want %v", err, ErrInvalidAPIKey)
+		}
+		if _, err := s.RotateAPIKey(ctx, issued.ID, apikey.RotateRequest{}); !apperror.Is(err, apperror.PreconditionFailed) {
+			t.Errorf("got error %v rotating a revoked key, want %s", err, apperror.PreconditionFailed)
+		}
+	})
+}
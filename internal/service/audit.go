@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"product/internal/domain/audit"
+)
+
+// snapshotFunc reads the current state of an entity in the shape it is audited in.
+type snapshotFunc func(ctx context.Context, id string) (any, error)
+
+func (s *Service) ListAudit(ctx context.Context, filters audit.Filters) (res []audit.Response, err error) {
+	ctx, span := startSpan(ctx, "ListAudit")
+	defer endSpan(span, &err)
+
+	data, err := s.auditRepository.Select(ctx, filters)
+	if err != nil {
+		return
+	}
+	res = audit.ParseFromEntities(data)
+
+	return
+}
+
+// audited runs change in a transaction together with the audit record of it.
+// The entity is read through snapshot before and after change, except before
+// a create and after a delete; change returns the id of the entity it touched.
+func (s *Service) audited(ctx context.Context, entity, operation, id string, snapshot snapshotFunc, change func(ctx context.Context) (string, error)) (changedID string, err error) {
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		var before, after any
+		if operation != audit.OperationCreate {
+			if before, err = snapshot(ctx, id); err != nil {
+				return
+			}
+		}
+
+		if changedID, err = change(ctx); err != nil {
+			return
+		}
+
+		if operation != audit.OperationDelete {
+			if after, err = snapshot(ctx, changedID); err != nil {
+				return
+			}
+		}
+
+		return s.record(ctx, entity, operation, changedID, before, after)
+	})
+
+	return
+}
+
+// record writes the audit record of a change within the transaction carried by ctx,
+// along with a new revision of products and categories and the domain events of
+// the change. Updates that did not change any field are not recorded.
+func (s *Service) record(ctx context.Context, entity, operation, id string, before, after any) (err error) {
+	changes, err := audit.Diff(before, after)
+	if err != nil {
+		return
+	}
+
+	if operation == audit.OperationUpdate && len(changes) == 0 {
+		return
+	}
+
+	encoded, err := json.Marshal(changes)
+	if err != nil {
+		return
+	}
+
+	metadata, value := audit.FromContext(ctx), string(encoded)
+	data := audit.Entity{
+		Actor:      &metadata.Actor,
+		RequestID:  &metadata.RequestID,
+		EntityType: &entity,
+		EntityID:   &id,
+		Operation:  &operation,
+		Changes:    &value,
+	}
+
+	if _, err = s.auditRepository.Create(ctx, data); err != nil {
+		return
+	}
+
+	state, err := s.currentState(ctx, entity, operation, id, after)
+	if err != nil {
+		return
+	}
+
+	if err = s.saveRevision(ctx, entity, operation, id, metadata, state); err != nil {
+		return
+	}
+
+	return s.publishEvents(ctx, entity, operation, id, metadata, state, changes)
+}
+
+// currentState encodes the full state of an entity after a change, or returns
+// nil when it was deleted. after is used as is for the entities whose changes
+// are audited in full.
+func (s *Service) currentState(ctx context.Context, entity, operation, id string, after any) (state []byte, err error) {
+	if operation == audit.OperationDelete {
+		return
+	}
+
+	switch entity {
+	case audit.EntityProduct:
+		after, err = s.productSnapshot(ctx, id)
+	case audit.EntityCategory:
+		after, err = s.categorySnapshot(ctx, id)
+	}
+	if err != nil {
+		return
+	}
+
+	return json.Marshal(after)
+}
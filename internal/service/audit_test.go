@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/category"
+	"product/pkg/apperror"
+)
+
+func TestCategoryChangesAreAudited(t *testing.T) {
+	s, _ := newCatalogService(t)
+	ctx := audit.WithMetadata(context.Background(), audit.Metadata{Actor: "alice", RequestID: "req-1"})
+
+	created, err := s.AddCategory(ctx, category.Request{Name: "Drinks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An update that changes nothing is not recorded.
+	if err = s.UpdateCategory(ctx, created.ID, category.Request{Name: "Drinks"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.UpdateCategory(ctx, created.ID, category.Request{Name: "Cold drinks"}); err != nil {
+		t.Fatal(err)
+	}
+	// A change that fails leaves no record behind.
+	if err = s.DeleteCategory(ctx, "missing"); !apperror.Is(err, apperror.NotFound) {
+		t.Fatalf("got error %v, want %s", err, apperror.NotFound)
+	}
+	if err = s.DeleteCategory(context.Background(), created.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.ListAudit(context.Background(), audit.Filters{EntityType: audit.EntityCategory, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, record := range res {
+		if record.EntityID != created.ID {
+			t.Errorf("got a record of %q, want %q", record.EntityID, created.ID)
+		}
+		got = append(got, record.Operation+" by "+record.Actor)
+	}
+	want := []string{"delete by " + audit.SystemActor, "update by alice", "create by alice"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got records %v, want %v", got, want)
+	}
+
+	update := res[1]
+	if update.RequestID != "req-1" {
+		t.Errorf("got request id %q, want \"req-1\"", update.RequestID)
+	}
+	wantChanges := map[string]audit.Change{"name": {Before: []byte(`"Drinks"`), After: []byte(`"Cold drinks"`)}}
+	if !reflect.DeepEqual(update.Changes, wantChanges) {
+		t.Errorf("got changes %v, want %v", update.Changes, wantChanges)
+	}
+}
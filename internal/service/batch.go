@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+
+	"product/internal/domain/batch"
+	"product/internal/domain/category"
+	"product/internal/domain/product"
+)
+
+// BatchProducts applies a list of product creates, updates and deletes.
+func (s *Service) BatchProducts(ctx context.Context, req product.BatchRequest) (res batch.Response, err error) {
+	ctx, span := startSpan(ctx, "BatchProducts")
+	defer endSpan(span, &err)
+
+	ops := make([]batch.Operation, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = op.Operation
+	}
+
+	res = s.runBatch(ctx, req.Mode, ops, func(ctx context.Context, i int) (id string, err error) {
+		op := req.Operations[i]
+		switch op.Op {
+		case batch.OpCreate:
+			data, err := s.AddProduct(ctx, *op.Data)
+			return data.ID, err
+		case batch.OpUpdate:
+			_, err = s.UpdateProduct(ctx, op.ID, *op.Data)
+		case batch.OpDelete:
+			err = s.DeleteProduct(ctx, op.ID)
+		}
+		return op.ID, err
+	})
+
+	return
+}
+
+// BatchCategories applies a list of category creates, updates and deletes.
+func (s *Service) BatchCategories(ctx context.Context, req category.BatchRequest) (res batch.Response, err error) {
+	ctx, span := startSpan(ctx, "BatchCategories")
+	defer endSpan(span, &err)
+
+	ops := make([]batch.Operation, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = op.Operation
+	}
+
+	res = s.runBatch(ctx, req.Mode, ops, func(ctx context.Context, i int) (id string, err error) {
+		op := req.Operations[i]
+		switch op.Op {
+		case batch.OpCreate:
+			data, err := s.AddCategory(ctx, *op.Data)
+			return data.ID, err
+		case batch.OpUpdate:
+			err = s.UpdateCategory(ctx, op.ID, *op.Data)
+		case batch.OpDelete:
+			err = s.DeleteCategory(ctx, op.ID)
+		}
+		return op.ID, err
+	})
+
+	return
+}
+
+// runBatch calls apply for every operation. In atomic mode all of them share
+// one transaction and the first failure rolls back the ones before it; in
+// best-effort mode each operation gets a transaction of its own.
+func (s *Service) runBatch(ctx context.Context, mode string, ops []batch.Operation, apply func(ctx context.Context, i int) (string, error)) (res batch.Response) {
+	res = batch.Response{
+		Mode:    mode,
+		Results: make([]batch.Result, len(ops)),
+	}
+	for i, op := range ops {
+		res.Results[i] = batch.Result{Index: i, Op: op.Op, ID: op.ID, Status: batch.StatusSkipped}
+	}
+
+	run := func(ctx context.Context, i int) error {
+		id, err := apply(ctx, i)
+		if err != nil {
+			res.Results[i].Status = batch.StatusFailed
+			res.Results[i].Error = err.Error()
+			return err
+		}
+		res.Results[i].ID = id
+		res.Results[i].Status = batch.StatusOK
+		return nil
+	}
+
+	if mode == batch.ModeAtomic {
+		err := s.transactor.Transaction(ctx, func(ctx context.Context) error {
+			for i := range ops {
+				if err := run(ctx, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			for i := range res.Results {
+				if res.Results[i].Status == batch.StatusOK {
+					res.Results[i].Status = batch.StatusRolledBack
+				}
+			}
+		}
+	} else {
+		for i := range ops {
+			s.transactor.Transaction(ctx, func(ctx context.Context) error {
+				return run(ctx, i)
+			})
+		}
+	}
+
+	for _, result := range res.Results {
+		if result.Status == batch.StatusOK {
+			res.Succeeded++
+		} else {
+			res.Failed++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/batch"
+	"product/internal/domain/category"
+	"product/internal/repository/memory"
+)
+
+// newCatalogService returns a service over the memory store that audits and
+// keeps revisions of every catalog change.
+func newCatalogService(t *testing.T) (*Service, *memory.Store) {
+	t.Helper()
+
+	db := memory.NewStore()
+	s, err := New(
+		WithCategoryRepository(memory.NewCategoryRepository(db)),
+		WithProductRepository(memory.NewProductRepository(db)),
+		WithEventRepository(memory.NewEventRepository(db)),
+		WithAuditRepository(memory.NewAuditRepository(db)),
+		WithRevisionRepository(memory.NewRevisionRepository(db)),
+		WithTransactor(db),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, db
+}
+
+func TestBatchCategories(t *testing.T) {
+	existing, missing := uuid.NewString(), uuid.NewString()
+
+	tests := []struct {
+		mode      string
+		statuses  []string
+		succeeded int
+		kept      bool
+	}{
+		{batch.ModeAtomic, []string{batch.StatusRolledBack, batch.StatusFailed, batch.StatusSkipped}, 0, false},
+		{batch.ModeBestEffort, []string{batch.StatusOK, batch.StatusFailed, batch.StatusOK}, 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			s, db := newCatalogService(t)
+			categories := memory.NewCategoryRepository(db)
+			addCategory(t, categories, existing, "Drinks")
+
+			req := category.BatchRequest{Mode: tt.mode, Operations: []category.BatchOperation{
+				{Operation: batch.Operation{Op: batch.OpCreate}, Data: &category.Request{Name: "Snacks"}},
+				{Operation: batch.Operation{Op: batch.OpDelete, ID: missing}},
+				{Operation: batch.Operation{Op: batch.OpUpdate, ID: existing}, Data: &category.Request{Name: "Cold drinks"}},
+			}}
+			res, err := s.BatchCategories(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var statuses []string
+			for _, result := range res.Results {
+				statuses = append(statuses, result.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.statuses) {
+				t.Errorf("got statuses %v, want %v", statuses, tt.statuses)
+			}
+			if res.Succeeded != tt.succeeded || res.Failed != len(req.Operations)-tt.succeeded {
+				t.Errorf("got %d succeeded and %d failed, want %d succeeded", res.Succeeded, res.Failed, tt.succeeded)
+			}
+			if res.Results[1].Error == "" {
+				t.Error("the failed operation has no error")
+			}
+
+			all, err := categories.Select(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if kept := len(all) == 2; kept != tt.kept {
+				t.Errorf("got %d categories, want the created one kept: %t", len(all), tt.kept)
+			}
+			drinks, err := categories.Get(context.Background(), existing)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if renamed := *drinks.Name == "Cold drinks"; renamed != tt.kept {
+				t.Errorf("got name %q, want it renamed: %t", *drinks.Name, tt.kept)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/brand"
+	"product/pkg/country"
+)
+
+func (s *Service) ListBrands(ctx context.Context) (res []brand.Response, err error) {
+	ctx, span := startSpan(ctx, "ListBrands")
+	defer endSpan(span, &err)
+
+	data, err := s.brandRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = brand.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) AddBrand(ctx context.Context, req brand.Request) (res brand.Response, err error) {
+	ctx, span := startSpan(ctx, "AddBrand")
+	defer endSpan(span, &err)
+
+	data := brand.Entity{
+		ID:      uuid.New().String(),
+		Name:    &req.Name,
+		Country: &req.Country,
+	}
+
+	data.ID, err = s.audited(ctx, audit.EntityBrand, audit.OperationCreate, "", s.brandSnapshot, func(ctx context.Context) (string, error) {
+		return s.brandRepository.Create(ctx, data)
+	})
+	if err != nil {
+		return
+	}
+	res = brand.ParseFromEntity(data)
+
+	return
+}
+
+func (s *Service) GetBrand(ctx context.Context, id string) (res brand.Response, err error) {
+	ctx, span := startSpan(ctx, "GetBrand")
+	defer endSpan(span, &err)
+
+	data, err := s.brandRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = brand.ParseFromEntity(data)
+
+	return
+}
+
+func (s *Service) UpdateBrand(ctx context.Context, id string, req brand.Request) (err error) {
+	ctx, span := startSpan(ctx, "UpdateBrand")
+	defer endSpan(span, &err)
+
+	data := brand.Entity{
+		ID:      id,
+		Name:    &req.Name,
+		Country: &req.Country,
+	}
+	_, err = s.audited(ctx, audit.EntityBrand, audit.OperationUpdate, id, s.brandSnapshot, func(ctx context.Context) (string, error) {
+		return id, s.brandRepository.Update(ctx, id, data)
+	})
+
+	return
+}
+
+func (s *Service) DeleteBrand(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteBrand")
+	defer endSpan(span, &err)
+
+	_, err = s.audited(ctx, audit.EntityBrand, audit.OperationDelete, id, s.brandSnapshot, func(ctx context.Context) (string, error) {
+		return id, s.brandRepository.Delete(ctx, id)
+	})
+
+	return
+}
+
+func (s *Service) brandSnapshot(ctx context.Context, id string) (any, error) {
+	return s.GetBrand(ctx, id)
+}
+
+// ListCountries returns the built-in ISO 3166-1 table used to validate country codes.
+func (s *Service) ListCountries() []country.Country {
+	return country.All()
+}
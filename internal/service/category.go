@@ -4,10 +4,17 @@ import (
 	"context"
 	"fmt"
 	"github.com/google/uuid"
+	"product/internal/domain/audit"
 	"product/internal/domain/category"
+	"product/internal/domain/product"
+	"product/pkg/apperror"
+	"product/pkg/validate"
 )
 
 func (s *Service) ListCategories(ctx context.Context) (res []category.Response, err error) {
+	ctx, span := startSpan(ctx, "ListCategories")
+	defer endSpan(span, &err)
+
 	data, err := s.categoryRepository.Select(ctx)
 	if err != nil {
 		return
@@ -17,13 +24,33 @@ func (s *Service) ListCategories(ctx context.Context) (res []category.Response,
 }
 
 func (s *Service) AddCategory(ctx context.Context, req category.Request) (res category.Response, err error) {
+	ctx, span := startSpan(ctx, "AddCategory")
+	defer endSpan(span, &err)
+
+	return s.createCategory(ctx, uuid.New().String(), req)
+}
+
+func (s *Service) createCategory(ctx context.Context, id string, req category.Request) (res category.Response, err error) {
+	if err = s.checkCategory(ctx, "parent_id", req.ParentId); err != nil {
+		return
+	}
+
 	data := category.Entity{
-		ID:       uuid.New().String(),
+		ID:       id,
 		ParentId: &req.ParentId,
 		Name:     &req.Name,
 	}
 
-	data.ID, err = s.categoryRepository.Create(ctx, data)
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		data.ID, err = s.audited(ctx, audit.EntityCategory, audit.OperationCreate, "", s.categorySnapshot, func(ctx context.Context) (string, error) {
+			return s.categoryRepository.Create(ctx, data)
+		})
+		if err != nil {
+			return
+		}
+
+		return s.moveProducts(ctx, data.ID, req.ProductIDs)
+	})
 	if err != nil {
 		return
 	}
@@ -33,6 +60,9 @@ func (s *Service) AddCategory(ctx context.Context, req category.Request) (res ca
 }
 
 func (s *Service) GetCategory(ctx context.Context, id string) (res category.Response, err error) {
+	ctx, span := startSpan(ctx, "GetCategory")
+	defer endSpan(span, &err)
+
 	data, err := s.categoryRepository.Get(ctx, id)
 	if err != nil {
 		return
@@ -48,14 +78,85 @@ func (s *Service) GetCategory(ctx context.Context, id string) (res category.Resp
 }
 
 func (s *Service) UpdateCategory(ctx context.Context, id string, req category.Request) (err error) {
+	ctx, span := startSpan(ctx, "UpdateCategory")
+	defer endSpan(span, &err)
+
+	if err = s.checkCategory(ctx, "parent_id", req.ParentId); err != nil {
+		return
+	}
+
 	data := category.Entity{
 		ID:       id,
 		ParentId: &req.ParentId,
 		Name:     &req.Name,
 	}
-	return s.categoryRepository.Update(ctx, id, data)
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		_, err = s.audited(ctx, audit.EntityCategory, audit.OperationUpdate, id, s.categorySnapshot, func(ctx context.Context) (string, error) {
+			return id, s.categoryRepository.Update(ctx, id, data)
+		})
+		if err != nil {
+			return
+		}
+
+		return s.moveProducts(ctx, id, req.ProductIDs)
+	})
+
+	return
 }
 
 func (s *Service) DeleteCategory(ctx context.Context, id string) (err error) {
-	return s.categoryRepository.Delete(ctx, id)
+	ctx, span := startSpan(ctx, "DeleteCategory")
+	defer endSpan(span, &err)
+
+	_, err = s.audited(ctx, audit.EntityCategory, audit.OperationDelete, id, s.categorySnapshot, func(ctx context.Context) (string, error) {
+		return id, s.categoryRepository.Delete(ctx, id)
+	})
+
+	return
+}
+
+// moveProducts moves the products into the category within the transaction
+// carried by ctx, with an audit record each. Products that do not exist are
+// reported as invalid fields; each move runs in a savepoint, so the others
+// are still checked.
+func (s *Service) moveProducts(ctx context.Context, categoryID string, ids []string) (err error) {
+	var errs validate.Errors
+	for i, id := range ids {
+		_, err = s.audited(ctx, audit.EntityProduct, audit.OperationUpdate, id, s.productSnapshot, func(ctx context.Context) (string, error) {
+			return id, s.productRepository.Update(ctx, id, product.Entity{CategoryID: &categoryID})
+		})
+		if apperror.Is(err, apperror.NotFound) {
+			errs.Add(fmt.Sprintf("product_ids[%d]", i), validate.CodeNotFound, "product does not exist")
+			continue
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	return errs.Err()
+}
+
+// checkCategory reports a category id that does not exist as an invalid
+// field. An empty id passes, requests check for those themselves.
+func (s *Service) checkCategory(ctx context.Context, field, id string) (err error) {
+	if id == "" {
+		return
+	}
+
+	if _, err = s.categoryRepository.Get(ctx, id); apperror.Is(err, apperror.NotFound) {
+		var errs validate.Errors
+		errs.Add(field, validate.CodeNotFound, "category does not exist")
+		return errs.Err()
+	}
+
+	return
+}
+
+func (s *Service) categorySnapshot(ctx context.Context, id string) (any, error) {
+	data, err := s.categoryRepository.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return category.ParseFromEntity(data), nil
 }
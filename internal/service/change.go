@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/change"
+	"product/internal/domain/event"
+)
+
+// feedEntities are the aggregates kiosks keep a local copy of.
+var feedEntities = []string{audit.EntityProduct, audit.EntityCategory}
+
+// ListChanges returns the products and categories created, updated or deleted
+// after the since token. The feed is read from the outbox, whose events carry
+// the full state of the entity after every change.
+func (s *Service) ListChanges(ctx context.Context, req change.Request) (res change.Response, err error) {
+	ctx, span := startSpan(ctx, "ListChanges")
+	defer endSpan(span, &err)
+
+	types := make([]string, 0, 3*len(feedEntities))
+	for _, entity := range feedEntities {
+		types = append(types, entity+"."+event.Created, entity+"."+event.Updated, entity+"."+event.Deleted)
+	}
+
+	data, err := s.eventRepository.SelectSince(ctx, req.Since.TxID, req.Since.ID, types, req.Limit+1)
+	if err != nil {
+		return
+	}
+
+	res.HasMore = len(data) > req.Limit
+	if res.HasMore {
+		data = data[:req.Limit]
+	}
+
+	next := req.Since
+	if len(data) > 0 {
+		last := data[len(data)-1]
+		next = change.Token{TxID: *last.TxID, ID: last.ID}
+	}
+	res.Next = next.String()
+
+	// Only the last change of an entity within the page matters.
+	latest := make(map[string]int, len(data))
+	for i, object := range data {
+		latest[*object.AggregateType+"/"+*object.AggregateID] = i
+	}
+
+	res.Changes = make([]change.Change, 0, len(latest))
+	for i, object := range data {
+		if latest[*object.AggregateType+"/"+*object.AggregateID] != i {
+			continue
+		}
+
+		message, err := event.ParseToMessage(object)
+		if err != nil {
+			return res, err
+		}
+
+		item := change.Change{
+			Entity:    message.AggregateType,
+			ID:        message.AggregateID,
+			Operation: change.OperationUpsert,
+			Data:      message.Data,
+		}
+		if message.Type == message.AggregateType+"."+event.Deleted {
+			item.Operation, item.Data = change.OperationDelete, nil
+		}
+		res.Changes = append(res.Changes, item)
+	}
+
+	return
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"product/internal/domain/category"
+	"product/internal/domain/change"
+)
+
+func TestListChanges(t *testing.T) {
+	s, _ := newCatalogService(t)
+	ctx := context.Background()
+
+	drinks, err := s.AddCategory(ctx, category.Request{Name: "Drinks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snacks, err := s.AddCategory(ctx, category.Request{Name: "Snacks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.UpdateCategory(ctx, drinks.ID, category.Request{Name: "Cold drinks"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.DeleteCategory(ctx, snacks.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("latest state", func(t *testing.T) {
+		res, err := s.ListChanges(ctx, change.Request{Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.HasMore || len(res.Changes) != 2 {
+			t.Fatalf("got %d changes and more: %t, want 2 and no more", len(res.Changes), res.HasMore)
+		}
+
+		updated, deleted := res.Changes[0], res.Changes[1]
+		var data struct {
+			Name string `json:"name"`
+		}
+		if err = json.Unmarshal(updated.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		if updated.ID != drinks.ID || updated.Operation != change.OperationUpsert || data.Name != "Cold drinks" {
+			t.Errorf("got %s of %q named %q, want the upsert of %q named \"Cold drinks\"", updated.Operation, updated.ID, data.Name, drinks.ID)
+		}
+		if deleted.ID != snacks.ID || deleted.Operation != change.OperationDelete || deleted.Data != nil {
+			t.Errorf("got %s of %q with %s, want a tombstone of %q", deleted.Operation, deleted.ID, deleted.Data, snacks.ID)
+		}
+
+		again, err := s.ListChanges(ctx, change.Request{Since: mustParseToken(t, res.Next), Limit: 10})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(again.Changes) != 0 || again.Next != res.Next {
+			t.Errorf("got %d changes and next %q after the last page, want none and %q", len(again.Changes), again.Next, res.Next)
+		}
+	})
+
+	t.Run("pages", func(t *testing.T) {
+		var pages [][]string
+		for req := (change.Request{Limit: 2}); ; {
+			res, err := s.ListChanges(ctx, req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var page []string
+			for _, item := range res.Changes {
+				page = append(page, item.Operation+" "+item.ID)
+			}
+			pages = append(pages, page)
+			if !res.HasMore {
+				break
+			}
+			req.Since = mustParseToken(t, res.Next)
+		}
+
+		want := [][]string{
+			{"upsert " + drinks.ID, "upsert " + snacks.ID},
+			{"upsert " + drinks.ID, "delete " + snacks.ID},
+		}
+		if !reflect.DeepEqual(pages, want) {
+			t.Errorf("got pages %v, want %v", pages, want)
+		}
+	})
+}
+
+func mustParseToken(t *testing.T, value string) change.Token {
+	t.Helper()
+
+	token, err := change.ParseToken(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/event"
+)
+
+// publishEvents writes the domain events of a change to the outbox, within
+// the transaction of the change itself.
+func (s *Service) publishEvents(ctx context.Context, entity, operation, id string, metadata audit.Metadata, state []byte, changes map[string]audit.Change) (err error) {
+	payload := event.Payload{
+		Actor:     metadata.Actor,
+		RequestID: metadata.RequestID,
+		Data:      state,
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	if operation == audit.OperationUpdate {
+		if payload.Changes, err = json.Marshal(changes); err != nil {
+			return
+		}
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+	value := string(encoded)
+
+	for _, eventType := range event.Types(entity, operation, fields) {
+		data := event.Entity{
+			Type:          &eventType,
+			AggregateType: &entity,
+			AggregateID:   &id,
+			Payload:       &value,
+		}
+		if _, err = s.eventRepository.Create(ctx, data); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+
+	"product/internal/domain/category"
+	"product/internal/domain/job"
+	"product/internal/domain/product"
+	"product/internal/worker"
+	"product/pkg/apperror"
+	"product/pkg/sheet"
+)
+
+const exportProgressInterval = 500
+
+// ExportProducts streams the products matching the filters to w in the requested format.
+func (s *Service) ExportProducts(ctx context.Context, req product.ExportRequest, w io.Writer) (err error) {
+	ctx, span := startSpan(ctx, "ExportProducts")
+	defer endSpan(span, &err)
+
+	_, err = s.exportProducts(ctx, req, w, nil)
+	return
+}
+
+// QueueExport queues a job that writes the export to a file kept with the job,
+// for catalogs too large to stream within a single request.
+func (s *Service) QueueExport(ctx context.Context, req product.ExportRequest) (res job.Response, err error) {
+	ctx, span := startSpan(ctx, "QueueExport")
+	defer endSpan(span, &err)
+
+	id, err := s.enqueueJob(ctx, job.TypeExport, req)
+	if err != nil {
+		return
+	}
+
+	return s.GetJob(ctx, id)
+}
+
+// ExportFile returns the file written by a finished export job.
+func (s *Service) ExportFile(ctx context.Context, id string) (content []byte, res product.ExportResult, err error) {
+	ctx, span := startSpan(ctx, "ExportFile")
+	defer endSpan(span, &err)
+
+	data, err := s.jobRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if *data.Type != job.TypeExport || *data.Status != job.StatusSucceeded || data.Result == nil {
+		err = ErrJobResultUnavailable
+		return
+	}
+
+	if err = json.Unmarshal([]byte(*data.Result), &res); err != nil {
+		return
+	}
+
+	content, err = s.jobRepository.File(ctx, id)
+	if apperror.Is(err, apperror.NotFound) {
+		err = ErrJobResultUnavailable
+	}
+
+	return
+}
+
+func (s *Service) exportJob(ctx context.Context, task *worker.Task) (err error) {
+	var req product.ExportRequest
+	if err = task.Decode(&req); err != nil {
+		return worker.Permanent(err)
+	}
+
+	total, err := s.productRepository.Count(ctx, req.Filters)
+	if err != nil {
+		return
+	}
+
+	// The file is saved only once complete, so a retried or interrupted job
+	// never leaves a truncated export behind.
+	var file bytes.Buffer
+	var out io.Writer = &file
+	var gz *gzip.Writer
+	if req.Gzip {
+		gz = gzip.NewWriter(&file)
+		out = gz
+	}
+
+	rows, err := s.exportProducts(ctx, req, out, func(rows int) error {
+		return task.Report(ctx, rows, total, nil)
+	})
+	if err != nil {
+		return
+	}
+
+	if gz != nil {
+		if err = gz.Close(); err != nil {
+			return
+		}
+	}
+	if err = task.SaveFile(ctx, file.Bytes()); err != nil {
+		return
+	}
+
+	if err = task.Report(ctx, rows, rows, nil); err != nil {
+		return
+	}
+
+	return task.SetResult(product.ExportResult{
+		Filename:    req.Filename(),
+		ContentType: req.ContentType(),
+		Rows:        rows,
+	})
+}
+
+// exportProducts writes the export to w and returns the number of products in it.
+// When progress is set it is called every exportProgressInterval products.
+func (s *Service) exportProducts(ctx context.Context, req product.ExportRequest, w io.Writer, progress func(rows int) error) (rows int, err error) {
+	categories, err := s.categoryRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	paths := category.Paths(categories)
+
+	counted := func(fn func(data product.Entity) error) func(product.Entity) error {
+		return func(data product.Entity) (err error) {
+			if err = fn(data); err != nil {
+				return
+			}
+			rows++
+			if progress != nil && rows%exportProgressInterval == 0 {
+				err = progress(rows)
+			}
+			return
+		}
+	}
+
+	if req.Format == product.ExportFormatJSONL {
+		encoder := json.NewEncoder(w)
+		err = s.productRepository.Stream(ctx, req.Filters, counted(func(data product.Entity) error {
+			return encoder.Encode(product.ParseToExport(data, paths[*data.CategoryID]))
+		}))
+		return
+	}
+
+	writer, err := sheet.NewWriter(req.Format, w)
+	if err != nil {
+		return
+	}
+
+	if err = writer.Write(product.ExportColumns); err != nil {
+		return
+	}
+
+	err = s.productRepository.Stream(ctx, req.Filters, counted(func(data product.Entity) error {
+		return writer.Write(product.ParseToExport(data, paths[*data.CategoryID]).Values())
+	}))
+	if err != nil {
+		return
+	}
+
+	err = writer.Close()
+
+	return
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"product/internal/domain/job"
+	"product/internal/domain/product"
+	"product/internal/repository/memory"
+	"product/internal/worker"
+	"product/pkg/apperror"
+	"product/pkg/store"
+)
+
+// jobRepository keeps jobs and their files in memory for the instances of a
+// test to share, the way they share the jobs table.
+type jobRepository struct {
+	job.Repository
+
+	mu       sync.Mutex
+	jobs     map[string]job.Entity
+	files    map[string][]byte
+	finished chan string
+}
+
+func newJobRepository() *jobRepository {
+	return &jobRepository{
+		jobs:     make(map[string]job.Entity),
+		files:    make(map[string][]byte),
+		finished: make(chan string, 1),
+	}
+}
+
+func (r *jobRepository) Create(ctx context.Context, data job.Entity) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// The defaults of the jobs table.
+	data.Status, data.CancelRequested = ptr(job.StatusQueued), ptr(false)
+	data.Progress, data.Total, data.Attempts = ptr(0), ptr(0), ptr(0)
+	r.jobs[data.ID] = data
+	return data.ID, nil
+}
+
+func (r *jobRepository) Get(ctx context.Context, id string) (job.Entity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, ok := r.jobs[id]
+	if !ok {
+		return data, store.ErrorNotFound
+	}
+	return data, nil
+}
+
+func (r *jobRepository) Claim(ctx context.Context, worker string, types []string) (job.Entity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, data := range r.jobs {
+		if *data.Status == job.StatusQueued {
+			data.Status, data.LockedBy = ptr(job.StatusRunning), &worker
+			data.Attempts = ptr(*data.Attempts + 1)
+			r.jobs[id] = data
+			return data, nil
+		}
+	}
+	return job.Entity{}, store.ErrorNotFound
+}
+
+func (r *jobRepository) Heartbeat(ctx context.Context, id, worker string) (bool, error) {
+	return false, nil
+}
+
+func (r *jobRepository) Progress(ctx context.Context, id, worker string, progress, total int, checkpoint *string) error {
+	return nil
+}
+
+func (r *jobRepository) SaveFile(ctx context.Context, id, worker string, content []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.files[id] = bytes.Clone(content)
+	return nil
+}
+
+func (r *jobRepository) File(ctx context.Context, id string) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	content, ok := r.files[id]
+	if !ok {
+		return nil, store.ErrorNotFound
+	}
+	return content, nil
+}
+
+func (r *jobRepository) Finish(ctx context.Context, id, worker, status string, result, lastError *string) error {
+	r.mu.Lock()
+	data := r.jobs[id]
+	data.Status, data.Result, data.LastError, data.LockedBy = &status, result, lastError, nil
+	r.jobs[id] = data
+	r.mu.Unlock()
+
+	r.finished <- id
+	return nil
+}
+
+func TestExportIsServedByAnyInstance(t *testing.T) {
+	ctx := context.Background()
+
+	db := memory.NewStore()
+	jobs := newJobRepository()
+	newInstance := func() *Service {
+		s, err := New(
+			WithCategoryRepository(memory.NewCategoryRepository(db)),
+			WithProductRepository(memory.NewProductRepository(db)),
+			WithJobRepository(jobs),
+			WithTransactor(db),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	api, runner := newInstance(), newInstance()
+
+	categories := memory.NewCategoryRepository(db)
+	addCategory(t, categories, "c1", "Drinks")
+	_, err := memory.NewProductRepository(db).Create(ctx, product.Entity{
+		ID:          "p1",
+		CategoryID:  ptr("c1"),
+		Barcode:     ptr("4600000000001"),
+		Name:        ptr("Cola"),
+		Description: ptr(""),
+		Image:       ptr(""),
+		IsWeighted:  ptr(false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := api.QueueExport(ctx, product.ExportRequest{Format: product.ExportFormatJSONL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := api.ExportFile(ctx, res.ID); err != ErrJobResultUnavailable {
+		t.Errorf("ExportFile() error = %v before the job ran, want %v", err, ErrJobResultUnavailable)
+	}
+
+	pool, err := worker.New(jobs, worker.WithHandler(job.TypeExport, runner.exportJob), worker.WithPollInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.Start()
+	select {
+	case <-jobs.finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the export job did not finish")
+	}
+	if err := pool.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	content, result, err := api.ExportFile(ctx, res.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rows != 1 || result.ContentType != "application/x-ndjson" {
+		t.Errorf("ExportFile() result = %+v, want 1 row of application/x-ndjson", result)
+	}
+
+	var row map[string]any
+	if err := json.Unmarshal(content, &row); err != nil {
+		t.Fatalf("exported file %q: %v", content, err)
+	}
+	if !strings.Contains(string(content), `"Cola"`) {
+		t.Errorf("exported file = %s, want the product in it", content)
+	}
+
+	if _, _, err := api.ExportFile(ctx, "missing"); !apperror.Is(err, apperror.NotFound) {
+		t.Errorf("ExportFile() error = %v for an unknown job, want not found", err)
+	}
+}
@@ -0,0 +1,405 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/imports"
+	"product/internal/domain/job"
+	"product/internal/domain/product"
+	"product/internal/worker"
+	"product/pkg/apperror"
+	"product/pkg/sheet"
+)
+
+var (
+	ErrImportFinished   = apperror.New(apperror.Conflict, "import: already finished")
+	ErrImportInProgress = apperror.New(apperror.Conflict, "import: already in progress")
+)
+
+type importPayload struct {
+	ImportID string `json:"import_id"`
+}
+
+// AddImport stores the uploaded file and queues a job to process it.
+func (s *Service) AddImport(ctx context.Context, req imports.Request) (res imports.Response, err error) {
+	ctx, span := startSpan(ctx, "AddImport")
+	defer endSpan(span, &err)
+
+	data, err := newImport(req)
+	if err != nil {
+		return
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		jobID, err := s.enqueueJob(ctx, job.TypeImport, importPayload{ImportID: data.ID})
+		if err != nil {
+			return
+		}
+		data.JobID = &jobID
+
+		data.ID, err = s.importRepository.Create(ctx, data)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	return s.GetImport(ctx, data.ID)
+}
+
+// ImportFile imports a file right away instead of queuing a job, for the
+// command line. report is told the progress after every batch.
+func (s *Service) ImportFile(ctx context.Context, req imports.Request, report func(processed, total int) error) (res imports.Response, err error) {
+	ctx, span := startSpan(ctx, "ImportFile")
+	defer endSpan(span, &err)
+
+	data, err := newImport(req)
+	if err != nil {
+		return
+	}
+
+	if data.ID, err = s.importRepository.Create(ctx, data); err != nil {
+		return
+	}
+
+	runErr := s.runImport(ctx, data.ID, report)
+
+	res, err = s.GetImport(context.WithoutCancel(ctx), data.ID)
+	if runErr != nil {
+		err = runErr
+	}
+
+	return
+}
+
+// newImport builds the import of the uploaded file, still pending.
+func newImport(req imports.Request) (data imports.Entity, err error) {
+	mapping, err := json.Marshal(req.Mapping)
+	if err != nil {
+		return
+	}
+
+	status := imports.StatusPending
+	mappingValue := string(mapping)
+	data = imports.Entity{
+		ID:        uuid.New().String(),
+		Status:    &status,
+		Filename:  &req.Filename,
+		Format:    &req.Format,
+		Mapping:   &mappingValue,
+		DryRun:    &req.DryRun,
+		BatchSize: &req.BatchSize,
+		File:      req.File,
+	}
+
+	return
+}
+
+func (s *Service) GetImport(ctx context.Context, id string) (res imports.Response, err error) {
+	ctx, span := startSpan(ctx, "GetImport")
+	defer endSpan(span, &err)
+
+	data, err := s.importRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	errs, err := s.importRepository.SelectErrors(ctx, id)
+	if err != nil {
+		return
+	}
+	res = imports.ParseFromEntity(data, errs)
+
+	return
+}
+
+// ResumeImport queues a job that continues an interrupted or failed import
+// from its last committed batch.
+func (s *Service) ResumeImport(ctx context.Context, id string) (res imports.Response, err error) {
+	ctx, span := startSpan(ctx, "ResumeImport")
+	defer endSpan(span, &err)
+
+	data, err := s.importRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	if *data.Status == imports.StatusCompleted || *data.Status == imports.StatusValidated {
+		err = ErrImportFinished
+		return
+	}
+
+	if data.JobID != nil {
+		current, err := s.jobRepository.Get(ctx, *data.JobID)
+		if err == nil && (*current.Status == job.StatusQueued || *current.Status == job.StatusRunning) {
+			return res, ErrImportInProgress
+		}
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		jobID, err := s.enqueueJob(ctx, job.TypeImport, importPayload{ImportID: id})
+		if err != nil {
+			return
+		}
+
+		return s.importRepository.Update(ctx, id, imports.Entity{JobID: &jobID})
+	})
+	if err != nil {
+		return
+	}
+
+	return s.GetImport(ctx, id)
+}
+
+func (s *Service) importJob(ctx context.Context, task *worker.Task) (err error) {
+	var payload importPayload
+	if err = task.Decode(&payload); err != nil {
+		return worker.Permanent(err)
+	}
+
+	return s.runImport(ctx, payload.ImportID, func(processed, total int) error {
+		return task.Report(ctx, processed, total, nil)
+	})
+}
+
+// runImport reads the stored file and upserts it batch by batch, skipping the
+// rows a previous run has already committed. Every batch is written in its own
+// transaction and the progress is checkpointed after it, so a failed import can
+// be resumed; upserting by barcode makes replaying a batch harmless.
+// Failures are recorded on the import itself. An import interrupted by a
+// canceled context goes back to pending, so it can be picked up again.
+func (s *Service) runImport(ctx context.Context, id string, report func(processed, total int) error) (err error) {
+	data, err := s.importRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	running, cleared := imports.StatusRunning, ""
+	if err = s.importRepository.Update(ctx, id, imports.Entity{Status: &running, Error: &cleared}); err != nil {
+		return
+	}
+
+	processErr := s.processImport(ctx, data, report)
+	if processErr == nil {
+		return
+	}
+
+	if ctx.Err() != nil {
+		pending := imports.StatusPending
+		if err = s.importRepository.Update(context.WithoutCancel(ctx), id, imports.Entity{Status: &pending}); err != nil {
+			return
+		}
+		return processErr
+	}
+
+	failed, message := imports.StatusFailed, processErr.Error()
+	if err = s.importRepository.Update(ctx, id, imports.Entity{Status: &failed, Error: &message}); err != nil {
+		return
+	}
+
+	return worker.Permanent(processErr)
+}
+
+func (s *Service) processImport(ctx context.Context, data imports.Entity, report func(processed, total int) error) (err error) {
+	var mapping imports.Mapping
+	if err = json.Unmarshal([]byte(*data.Mapping), &mapping); err != nil {
+		return
+	}
+
+	reader, err := sheet.NewReader(*data.Format, data.File)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+
+	header, err := reader.Read()
+	if err != nil {
+		return
+	}
+
+	columns, errs := mapping.Columns(header)
+	if len(errs) > 0 {
+		if err = s.importRepository.AddErrors(ctx, data.ID, errs); err != nil {
+			return
+		}
+		return errors.New("import: required columns are missing")
+	}
+
+	resolver, err := s.importResolver(ctx, columns)
+	if err != nil {
+		return
+	}
+
+	processed, upserted, failed := *data.ProcessedRows, *data.UpsertedRows, *data.FailedRows
+	batch := make([]product.Entity, 0, *data.BatchSize)
+	batchErrs := make([]imports.RowError, 0)
+	pending := 0
+
+	flush := func(total int) (err error) {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		nextProcessed, nextUpserted := processed+pending, upserted
+		if !*data.DryRun {
+			nextUpserted += len(batch)
+		}
+
+		err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+			if !*data.DryRun && len(batch) > 0 {
+				if err = s.upsertProducts(ctx, batch); err != nil {
+					return
+				}
+			}
+			if err = s.importRepository.AddErrors(ctx, data.ID, batchErrs); err != nil {
+				return
+			}
+
+			return s.importRepository.Update(ctx, data.ID, imports.Entity{
+				TotalRows:     &total,
+				ProcessedRows: &nextProcessed,
+				UpsertedRows:  &nextUpserted,
+				FailedRows:    &failed,
+			})
+		})
+		if err != nil {
+			return
+		}
+
+		processed, upserted = nextProcessed, nextUpserted
+		batch, batchErrs, pending = batch[:0], batchErrs[:0], 0
+		return report(processed, total)
+	}
+
+	total := 0
+	for {
+		row, readErr := reader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return readErr
+		}
+
+		total++
+		if total <= processed || isBlankRow(row) {
+			if total > processed {
+				pending++
+			}
+			continue
+		}
+
+		entity, rowErrs := resolver.Product(total+1, row)
+		pending++
+		if len(rowErrs) > 0 {
+			failed++
+			batchErrs = append(batchErrs, rowErrs...)
+		} else {
+			entity.ID = uuid.New().String()
+			batch = append(batch, entity)
+		}
+
+		if pending >= *data.BatchSize {
+			if err = flush(total); err != nil {
+				return
+			}
+		}
+	}
+
+	if err = flush(total); err != nil {
+		return
+	}
+
+	status := imports.StatusCompleted
+	if *data.DryRun {
+		status = imports.StatusValidated
+	}
+
+	return s.importRepository.Update(ctx, data.ID, imports.Entity{Status: &status})
+}
+
+// upsertProducts writes a batch of imported products and audits each of them
+// as a create or an update, depending on whether its barcode existed before.
+func (s *Service) upsertProducts(ctx context.Context, data []product.Entity) (err error) {
+	barcodes := make([]string, 0, len(data))
+	for _, row := range data {
+		barcodes = append(barcodes, *row.Barcode)
+	}
+
+	existing, err := s.productRepository.SelectByBarcodes(ctx, barcodes)
+	if err != nil {
+		return
+	}
+	before := make(map[string]product.Response, len(existing))
+	for _, row := range existing {
+		before[*row.Barcode] = product.ParseFromEntity(row)
+	}
+
+	if err = s.productRepository.Upsert(ctx, data); err != nil {
+		return
+	}
+
+	current, err := s.productRepository.SelectByBarcodes(ctx, barcodes)
+	if err != nil {
+		return
+	}
+
+	for _, row := range current {
+		after := product.ParseFromEntity(row)
+		if previous, ok := before[*row.Barcode]; ok {
+			err = s.record(ctx, audit.EntityProduct, audit.OperationUpdate, row.ID, previous, after)
+		} else {
+			err = s.record(ctx, audit.EntityProduct, audit.OperationCreate, row.ID, nil, after)
+		}
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *Service) importResolver(ctx context.Context, columns map[string]int) (resolver imports.Resolver, err error) {
+	categories, err := s.categoryRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+
+	brandList, err := s.brandRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	brands := make(map[string]string, len(brandList))
+	for _, b := range brandList {
+		brands[strings.ToLower(*b.Name)] = b.ID
+	}
+
+	supplierList, err := s.supplierRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	suppliers := make(map[string]string, len(supplierList))
+	for _, supplier := range supplierList {
+		suppliers[strings.ToLower(*supplier.Name)] = supplier.ID
+	}
+
+	resolver = imports.NewResolver(columns, categories, brands, suppliers)
+
+	return
+}
+
+func isBlankRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
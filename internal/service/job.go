@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/job"
+	"product/internal/worker"
+	"product/pkg/apperror"
+)
+
+const defaultJobMaxAttempts = 5
+
+var ErrJobResultUnavailable = apperror.New(apperror.PreconditionFailed, "job: result is not available")
+
+// JobHandlers returns the worker handlers of every job type the service queues.
+func (s *Service) JobHandlers() map[string]worker.Handler {
+	return map[string]worker.Handler{
+		job.TypeImport:      withJobMetadata(s.importJob),
+		job.TypeExport:      withJobMetadata(s.exportJob),
+		job.TypePriceUpdate: withJobMetadata(s.priceUpdateJob),
+		job.TypeSnapshot:    withJobMetadata(s.snapshotJob),
+	}
+}
+
+// withJobMetadata attributes the changes a job makes to whoever queued it.
+func withJobMetadata(handler worker.Handler) worker.Handler {
+	return func(ctx context.Context, task *worker.Task) error {
+		var metadata audit.Metadata
+		if task.Actor != nil {
+			metadata.Actor = *task.Actor
+		}
+		if task.RequestID != nil {
+			metadata.RequestID = *task.RequestID
+		}
+
+		return handler(audit.WithMetadata(ctx, metadata), task)
+	}
+}
+
+func (s *Service) GetJob(ctx context.Context, id string) (res job.Response, err error) {
+	ctx, span := startSpan(ctx, "GetJob")
+	defer endSpan(span, &err)
+
+	data, err := s.jobRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = job.ParseFromEntity(data)
+
+	return
+}
+
+// CancelJob drops a queued job and asks the worker running it otherwise to stop
+// at its next checkpoint.
+func (s *Service) CancelJob(ctx context.Context, id string) (res job.Response, err error) {
+	ctx, span := startSpan(ctx, "CancelJob")
+	defer endSpan(span, &err)
+
+	if err = s.jobRepository.Cancel(ctx, id); err != nil {
+		return
+	}
+
+	return s.GetJob(ctx, id)
+}
+
+// enqueueJob stores a new job of the given type with payload encoded as JSON.
+func (s *Service) enqueueJob(ctx context.Context, jobType string, payload any) (id string, err error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	value, maxAttempts := string(encoded), defaultJobMaxAttempts
+	metadata := audit.FromContext(ctx)
+	data := job.Entity{
+		ID:          uuid.New().String(),
+		Type:        &jobType,
+		Payload:     &value,
+		MaxAttempts: &maxAttempts,
+		Actor:       &metadata.Actor,
+		RequestID:   &metadata.RequestID,
+	}
+
+	return s.jobRepository.Create(ctx, data)
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/modifier"
+	"product/pkg/validate"
+)
+
+func (s *Service) ListModifierGroups(ctx context.Context) (res []modifier.Response, err error) {
+	ctx, span := startSpan(ctx, "ListModifierGroups")
+	defer endSpan(span, &err)
+
+	data, err := s.modifierRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = modifier.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) AddModifierGroup(ctx context.Context, req modifier.Request) (res modifier.Response, err error) {
+	ctx, span := startSpan(ctx, "AddModifierGroup")
+	defer endSpan(span, &err)
+
+	data, err := parseModifierRequest(uuid.New().String(), req, nil)
+	if err != nil {
+		return
+	}
+
+	data.ID, err = s.audited(ctx, audit.EntityModifierGroup, audit.OperationCreate, "", s.modifierGroupSnapshot, func(ctx context.Context) (string, error) {
+		return s.modifierRepository.Create(ctx, data)
+	})
+	if err != nil {
+		return
+	}
+	res = modifier.ParseFromEntity(data)
+
+	return
+}
+
+func (s *Service) GetModifierGroup(ctx context.Context, id string) (res modifier.Response, err error) {
+	ctx, span := startSpan(ctx, "GetModifierGroup")
+	defer endSpan(span, &err)
+
+	data, err := s.modifierRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = modifier.ParseFromEntity(data)
+
+	return
+}
+
+func (s *Service) UpdateModifierGroup(ctx context.Context, id string, req modifier.Request) (res modifier.Response, err error) {
+	ctx, span := startSpan(ctx, "UpdateModifierGroup")
+	defer endSpan(span, &err)
+
+	current, err := s.modifierRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	data, err := parseModifierRequest(id, req, current.Options)
+	if err != nil {
+		return
+	}
+
+	_, err = s.audited(ctx, audit.EntityModifierGroup, audit.OperationUpdate, id, s.modifierGroupSnapshot, func(ctx context.Context) (string, error) {
+		return id, s.modifierRepository.Update(ctx, id, data)
+	})
+	if err != nil {
+		return
+	}
+
+	return s.GetModifierGroup(ctx, id)
+}
+
+func (s *Service) DeleteModifierGroup(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteModifierGroup")
+	defer endSpan(span, &err)
+
+	_, err = s.audited(ctx, audit.EntityModifierGroup, audit.OperationDelete, id, s.modifierGroupSnapshot, func(ctx context.Context) (string, error) {
+		return id, s.modifierRepository.Delete(ctx, id)
+	})
+
+	return
+}
+
+func (s *Service) modifierGroupSnapshot(ctx context.Context, id string) (any, error) {
+	return s.GetModifierGroup(ctx, id)
+}
+
+func (s *Service) ListProductModifiers(ctx context.Context, productID string) (res []modifier.Response, err error) {
+	ctx, span := startSpan(ctx, "ListProductModifiers")
+	defer endSpan(span, &err)
+
+	if _, err = s.productRepository.Get(ctx, productID); err != nil {
+		return
+	}
+
+	data, err := s.modifierRepository.SelectByProduct(ctx, productID)
+	if err != nil {
+		return
+	}
+	res = modifier.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) AttachProductModifiers(ctx context.Context, productID string, req modifier.AttachRequest) (res []modifier.Response, err error) {
+	ctx, span := startSpan(ctx, "AttachProductModifiers")
+	defer endSpan(span, &err)
+
+	if _, err = s.productRepository.Get(ctx, productID); err != nil {
+		return
+	}
+
+	for _, groupID := range req.GroupIDs {
+		if _, err = s.modifierRepository.Get(ctx, groupID); err != nil {
+			return
+		}
+	}
+
+	_, err = s.audited(ctx, audit.EntityProduct, audit.OperationUpdate, productID, s.productModifiersSnapshot, func(ctx context.Context) (string, error) {
+		return productID, s.modifierRepository.Attach(ctx, productID, req.GroupIDs)
+	})
+	if err != nil {
+		return
+	}
+
+	return s.ListProductModifiers(ctx, productID)
+}
+
+// productModifiersSnapshot audits the modifier groups attached to a product
+// as a single field of it.
+func (s *Service) productModifiersSnapshot(ctx context.Context, id string) (any, error) {
+	groups, err := s.modifierRepository.SelectByProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+	}
+
+	return map[string][]string{"modifier_groups": ids}, nil
+}
+
+// ValidateConfiguration prices a product with the chosen modifier options.
+// Invalid selections are reported as a validation error listing validate.Errors.
+func (s *Service) ValidateConfiguration(ctx context.Context, productID string, req modifier.ConfigurationRequest) (res modifier.ConfigurationResponse, err error) {
+	ctx, span := startSpan(ctx, "ValidateConfiguration")
+	defer endSpan(span, &err)
+
+	data, err := s.productRepository.Get(ctx, productID)
+	if err != nil {
+		return
+	}
+
+	groups, err := s.modifierRepository.SelectByProduct(ctx, productID)
+	if err != nil {
+		return
+	}
+
+	selected, err := modifier.Configure(groups, req)
+	if err != nil {
+		return
+	}
+
+	res = modifier.ConfigurationResponse{
+		ProductID: data.ID,
+		Options:   selected,
+	}
+	if data.Cost != nil {
+		res.BasePrice = *data.Cost
+	}
+
+	res.Price = res.BasePrice
+	for _, option := range selected {
+		res.Price += option.PriceDelta
+	}
+
+	return
+}
+
+// parseModifierRequest builds a group from req. Options that are among
+// current keep their IDs, so that kiosks and carts holding them still work
+// after the group is edited; only new options get a new ID.
+func parseModifierRequest(id string, req modifier.Request, current []modifier.Option) (data modifier.Entity, err error) {
+	data = modifier.Entity{
+		ID:        id,
+		Name:      &req.Name,
+		MinSelect: &req.MinSelect,
+		MaxSelect: &req.MaxSelect,
+		Options:   make([]modifier.Option, 0, len(req.Options)),
+	}
+
+	byID := make(map[string]bool, len(current))
+	byName := make(map[string]string, len(current))
+	for _, option := range current {
+		byID[option.ID] = true
+		byName[*option.Name] = option.ID
+	}
+
+	// Options named by id keep it even when an earlier one has the same name.
+	used := make(map[string]bool, len(req.Options))
+	for _, option := range req.Options {
+		if option.ID != "" {
+			used[option.ID] = true
+		}
+	}
+
+	var errs validate.Errors
+	seen := make(map[string]bool, len(req.Options))
+	for i := range req.Options {
+		optionID := req.Options[i].ID
+		switch {
+		case optionID != "" && !byID[optionID]:
+			errs.Add(fmt.Sprintf("options[%d].id", i), validate.CodeNotFound, "option does not belong to the group")
+			continue
+		case optionID != "" && seen[optionID]:
+			errs.Add(fmt.Sprintf("options[%d].id", i), validate.CodeDuplicate, "option listed more than once")
+			continue
+		case optionID == "":
+			if existing, ok := byName[req.Options[i].Name]; ok && !used[existing] {
+				optionID = existing
+			} else {
+				optionID = uuid.New().String()
+			}
+		}
+		used[optionID], seen[optionID] = true, true
+
+		data.Options = append(data.Options, modifier.Option{
+			ID:         optionID,
+			GroupID:    &data.ID,
+			Name:       &req.Options[i].Name,
+			PriceDelta: &req.Options[i].PriceDelta,
+			IsDefault:  &req.Options[i].IsDefault,
+		})
+	}
+
+	return data, errs.Err()
+}
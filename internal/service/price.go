@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/job"
+	"product/internal/domain/product"
+	"product/internal/worker"
+)
+
+const priceUpdateBatchSize = 500
+
+// priceUpdateCheckpoint is where a price update resumes from: every product
+// up to AfterID has already been changed.
+type priceUpdateCheckpoint struct {
+	AfterID string `json:"after_id"`
+	Updated int    `json:"updated"`
+}
+
+// UpdatePrices queues a job that changes the cost of every product matching the filters.
+func (s *Service) UpdatePrices(ctx context.Context, req product.PriceUpdateRequest) (res job.Response, err error) {
+	ctx, span := startSpan(ctx, "UpdatePrices")
+	defer endSpan(span, &err)
+
+	id, err := s.enqueueJob(ctx, job.TypePriceUpdate, req)
+	if err != nil {
+		return
+	}
+
+	return s.GetJob(ctx, id)
+}
+
+// priceUpdateJob changes the costs batch by batch in id order. Each batch is
+// committed together with the checkpoint, so a resumed job never applies the
+// adjustment to the same product twice.
+func (s *Service) priceUpdateJob(ctx context.Context, task *worker.Task) (err error) {
+	var req product.PriceUpdateRequest
+	if err = task.Decode(&req); err != nil {
+		return worker.Permanent(err)
+	}
+
+	var checkpoint priceUpdateCheckpoint
+	restored, err := task.Restore(&checkpoint)
+	if err != nil {
+		return worker.Permanent(err)
+	}
+
+	total := *task.Total
+	if !restored {
+		if total, err = s.productRepository.Count(ctx, req.Filters); err != nil {
+			return
+		}
+	}
+
+	for {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		next := checkpoint
+		err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+			changes, err := s.productRepository.AdjustCosts(ctx, req.Filters, req.Percent, req.Delta, checkpoint.AfterID, priceUpdateBatchSize)
+			if err != nil || len(changes) == 0 {
+				return
+			}
+
+			for _, change := range changes {
+				before, after := map[string]int{"cost": change.Before}, map[string]int{"cost": change.After}
+				if err = s.record(ctx, audit.EntityProduct, audit.OperationUpdate, change.ID, before, after); err != nil {
+					return
+				}
+			}
+
+			next.AfterID, next.Updated = changes[len(changes)-1].ID, checkpoint.Updated+len(changes)
+			return task.Report(ctx, next.Updated, total, next)
+		})
+		if err != nil {
+			return
+		}
+
+		if next == checkpoint {
+			break
+		}
+		checkpoint = next
+	}
+
+	return task.SetResult(checkpoint)
+}
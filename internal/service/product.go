@@ -3,12 +3,15 @@ package service
 import (
 	"context"
 	"github.com/google/uuid"
-	"net/http"
+	"product/internal/domain/audit"
 	"product/internal/domain/product"
 )
 
-func (s *Service) ListProduct(ctx context.Context, r *http.Request) (res []product.Response, err error) {
-	data, err := s.productRepository.Select(ctx, r)
+func (s *Service) ListProduct(ctx context.Context, filters product.Filters) (res []product.Response, err error) {
+	ctx, span := startSpan(ctx, "ListProduct")
+	defer endSpan(span, &err)
+
+	data, err := s.productRepository.Select(ctx, filters)
 	if err != nil {
 		return
 	}
@@ -18,30 +21,46 @@ func (s *Service) ListProduct(ctx context.Context, r *http.Request) (res []produ
 }
 
 func (s *Service) AddProduct(ctx context.Context, req product.Request) (res product.Response, err error) {
+	ctx, span := startSpan(ctx, "AddProduct")
+	defer endSpan(span, &err)
+
+	return s.createProduct(ctx, uuid.New().String(), req)
+}
+
+func (s *Service) createProduct(ctx context.Context, id string, req product.Request) (res product.Response, err error) {
+	if err = s.checkCategory(ctx, "category_id", req.CategoryID); err != nil {
+		return
+	}
+
 	data := product.Entity{
-		ID:              uuid.New().String(),
+		ID:              id,
 		CategoryID:      &req.CategoryID,
 		Barcode:         &req.Barcode,
 		Name:            &req.Name,
 		Measure:         &req.Measure,
 		Cost:            &req.Cost,
 		ProducerCountry: &req.ProducerCountry,
-		BrandName:       &req.BrandName,
+		BrandID:         &req.BrandID,
+		SupplierID:      &req.SupplierID,
 		Description:     &req.Description,
 		Image:           &req.Image,
 		IsWeighted:      &req.IsWeighted,
 	}
 
-	data.ID, err = s.productRepository.Create(ctx, data)
+	data.ID, err = s.audited(ctx, audit.EntityProduct, audit.OperationCreate, "", s.productSnapshot, func(ctx context.Context) (string, error) {
+		return s.productRepository.Create(ctx, data)
+	})
 	if err != nil {
 		return
 	}
-	res = product.ParseFromEntity(data)
 
-	return
+	return s.GetProduct(ctx, data.ID)
 }
 
 func (s *Service) GetProduct(ctx context.Context, id string) (res product.Response, err error) {
+	ctx, span := startSpan(ctx, "GetProduct")
+	defer endSpan(span, &err)
+
 	data, err := s.productRepository.Get(ctx, id)
 	if err != nil {
 		return
@@ -52,6 +71,13 @@ func (s *Service) GetProduct(ctx context.Context, id string) (res product.Respon
 }
 
 func (s *Service) UpdateProduct(ctx context.Context, id string, req product.Request) (res product.Response, err error) {
+	ctx, span := startSpan(ctx, "UpdateProduct")
+	defer endSpan(span, &err)
+
+	if err = s.checkCategory(ctx, "category_id", req.CategoryID); err != nil {
+		return
+	}
+
 	data := product.Entity{
 		ID:              id,
 		CategoryID:      &req.CategoryID,
@@ -60,22 +86,77 @@ func (s *Service) UpdateProduct(ctx context.Context, id string, req product.Requ
 		Measure:         &req.Measure,
 		Cost:            &req.Cost,
 		ProducerCountry: &req.ProducerCountry,
-		BrandName:       &req.BrandName,
+		BrandID:         &req.BrandID,
+		SupplierID:      &req.SupplierID,
 		Description:     &req.Description,
 		Image:           &req.Image,
 		IsWeighted:      &req.IsWeighted,
 	}
 
-	err = s.productRepository.Update(ctx, id, data)
+	_, err = s.audited(ctx, audit.EntityProduct, audit.OperationUpdate, id, s.productSnapshot, func(ctx context.Context) (string, error) {
+		return id, s.productRepository.Update(ctx, id, data)
+	})
+	if err != nil {
+		return
+	}
+
+	return s.GetProduct(ctx, id)
+}
+
+func (s *Service) DeleteProduct(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteProduct")
+	defer endSpan(span, &err)
+
+	_, err = s.audited(ctx, audit.EntityProduct, audit.OperationDelete, id, s.productSnapshot, func(ctx context.Context) (string, error) {
+		return id, s.productRepository.Delete(ctx, id)
+	})
+
+	return
+}
+
+// ListProductHistory returns the audit records of a product, newest first.
+func (s *Service) ListProductHistory(ctx context.Context, id string, filters audit.Filters) (res []audit.Response, err error) {
+	ctx, span := startSpan(ctx, "ListProductHistory")
+	defer endSpan(span, &err)
+
+	filters.EntityType, filters.EntityID = audit.EntityProduct, id
+
+	return s.ListAudit(ctx, filters)
+}
+
+func (s *Service) productSnapshot(ctx context.Context, id string) (any, error) {
+	return s.GetProduct(ctx, id)
+}
+
+// ListUnmatchedReferences returns the legacy brand and country strings that
+// the brands and suppliers migration could not map to an entity.
+func (s *Service) ListUnmatchedReferences(ctx context.Context) (res []product.UnmatchedReferenceResponse, err error) {
+	ctx, span := startSpan(ctx, "ListUnmatchedReferences")
+	defer endSpan(span, &err)
 
+	data, err := s.productRepository.SelectUnmatched(ctx)
 	if err != nil {
 		return
 	}
-	res = product.ParseFromEntity(data)
+	res = product.ParseFromUnmatched(data)
 
 	return
 }
 
-func (s *Service) DeleteProduct(ctx context.Context, id string) (err error) {
-	return s.productRepository.Delete(ctx, id)
+// CountCatalog returns how many products and categories the catalog holds.
+func (s *Service) CountCatalog(ctx context.Context) (products, categories int, err error) {
+	ctx, span := startSpan(ctx, "CountCatalog")
+	defer endSpan(span, &err)
+
+	if products, err = s.productRepository.Count(ctx, product.Filters{}); err != nil {
+		return
+	}
+
+	data, err := s.categoryRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	categories = len(data)
+
+	return
 }
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/category"
+	"product/internal/domain/product"
+	"product/internal/domain/revision"
+	"product/pkg/apperror"
+)
+
+var ErrRevisionDeleted = apperror.New(apperror.Validation, "revision: the entity was deleted in this revision")
+
+func (s *Service) ListRevisions(ctx context.Context, entityType, id string) (res []revision.Response, err error) {
+	ctx, span := startSpan(ctx, "ListRevisions")
+	defer endSpan(span, &err)
+
+	data, err := s.revisionRepository.Select(ctx, entityType, id)
+	if err != nil {
+		return
+	}
+	res = revision.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) GetRevision(ctx context.Context, entityType, id string, number int) (res revision.Response, err error) {
+	ctx, span := startSpan(ctx, "GetRevision")
+	defer endSpan(span, &err)
+
+	data, err := s.revisionRepository.Get(ctx, entityType, id, number)
+	if err != nil {
+		return
+	}
+	res = revision.ParseFromEntity(data)
+
+	return
+}
+
+// DiffRevisions compares the snapshots of two revisions of an entity field by field.
+func (s *Service) DiffRevisions(ctx context.Context, entityType, id string, from, to int) (res revision.DiffResponse, err error) {
+	ctx, span := startSpan(ctx, "DiffRevisions")
+	defer endSpan(span, &err)
+
+	left, err := s.GetRevision(ctx, entityType, id, from)
+	if err != nil {
+		return
+	}
+
+	right, err := s.GetRevision(ctx, entityType, id, to)
+	if err != nil {
+		return
+	}
+
+	res = revision.DiffResponse{From: from, To: to}
+	res.Changes, err = audit.Diff(left.Snapshot, right.Snapshot)
+
+	return
+}
+
+// RestoreProduct writes the field values of an old revision back as a new
+// revision, recreating the product when it has been deleted since.
+func (s *Service) RestoreProduct(ctx context.Context, id string, number int) (res product.Response, err error) {
+	ctx, span := startSpan(ctx, "RestoreProduct")
+	defer endSpan(span, &err)
+
+	var req product.Request
+	if err = s.loadRevision(ctx, audit.EntityProduct, id, number, &req); err != nil {
+		return
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		if _, err = s.productRepository.Get(ctx, id); apperror.Is(err, apperror.NotFound) {
+			res, err = s.createProduct(ctx, id, req)
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		res, err = s.UpdateProduct(ctx, id, req)
+		return
+	})
+
+	return
+}
+
+// RestoreCategory writes the field values of an old revision back as a new
+// revision, recreating the category when it has been deleted since.
+func (s *Service) RestoreCategory(ctx context.Context, id string, number int) (res category.Response, err error) {
+	ctx, span := startSpan(ctx, "RestoreCategory")
+	defer endSpan(span, &err)
+
+	var req category.Request
+	if err = s.loadRevision(ctx, audit.EntityCategory, id, number, &req); err != nil {
+		return
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		if _, err = s.categoryRepository.Get(ctx, id); apperror.Is(err, apperror.NotFound) {
+			_, err = s.createCategory(ctx, id, req)
+		} else if err == nil {
+			err = s.UpdateCategory(ctx, id, req)
+		}
+		if err != nil {
+			return
+		}
+
+		res, err = s.GetCategory(ctx, id)
+		return
+	})
+
+	return
+}
+
+// loadRevision unmarshals the snapshot of a revision into req.
+func (s *Service) loadRevision(ctx context.Context, entityType, id string, number int, req any) (err error) {
+	data, err := s.revisionRepository.Get(ctx, entityType, id, number)
+	if err != nil {
+		return
+	}
+
+	if data.Snapshot == nil {
+		return ErrRevisionDeleted
+	}
+
+	return json.Unmarshal([]byte(*data.Snapshot), req)
+}
+
+// saveRevision stores the state of a product or category after a change.
+// Other entities have no revisions.
+func (s *Service) saveRevision(ctx context.Context, entityType, operation, id string, metadata audit.Metadata, state []byte) (err error) {
+	if entityType != audit.EntityProduct && entityType != audit.EntityCategory {
+		return
+	}
+
+	data := revision.Entity{
+		EntityType: &entityType,
+		EntityID:   &id,
+		Actor:      &metadata.Actor,
+		RequestID:  &metadata.RequestID,
+		Operation:  &operation,
+	}
+	if state != nil {
+		value := string(state)
+		data.Snapshot = &value
+	}
+
+	_, err = s.revisionRepository.Create(ctx, data)
+
+	return
+}
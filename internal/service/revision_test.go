@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/category"
+)
+
+func TestCategoryRevisions(t *testing.T) {
+	s, _ := newCatalogService(t)
+	ctx := context.Background()
+
+	created, err := s.AddCategory(ctx, category.Request{Name: "Drinks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.UpdateCategory(ctx, created.ID, category.Request{Name: "Cold drinks"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = s.DeleteCategory(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("diff", func(t *testing.T) {
+		res, err := s.DiffRevisions(ctx, audit.EntityCategory, created.ID, 1, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]audit.Change{"name": {Before: []byte(`"Drinks"`), After: []byte(`"Cold drinks"`)}}
+		if !reflect.DeepEqual(res.Changes, want) {
+			t.Errorf("got changes %v, want %v", res.Changes, want)
+		}
+	})
+
+	t.Run("restore a deletion", func(t *testing.T) {
+		if _, err := s.RestoreCategory(ctx, created.ID, 3); !errors.Is(err, ErrRevisionDeleted) {
+			t.Errorf("got error %v, want %v", err, ErrRevisionDeleted)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		res, err := s.RestoreCategory(ctx, created.ID, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.ID != created.ID || res.Name != "Drinks" {
+			t.Errorf("got %q named %q, want %q named \"Drinks\"", res.ID, res.Name, created.ID)
+		}
+
+		revisions, err := s.ListRevisions(ctx, audit.EntityCategory, created.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, data := range revisions {
+			got = append(got, data.Operation)
+		}
+		// Revisions are listed latest first.
+		want := []string{audit.OperationCreate, audit.OperationDelete, audit.OperationUpdate, audit.OperationCreate}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got revisions %v, want %v", got, want)
+		}
+	})
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/category"
+	"product/internal/domain/fixture"
+	"product/internal/domain/product"
+)
+
+// categoryKey identifies a category by its name under a parent.
+type categoryKey struct {
+	parentID string
+	name     string
+}
+
+// SeedCatalog loads a fixture in a single transaction. Categories already
+// present under the same parent are reused and products with a known barcode
+// are updated in place.
+func (s *Service) SeedCatalog(ctx context.Context, catalog fixture.Catalog) (res fixture.Result, err error) {
+	ctx, span := startSpan(ctx, "SeedCatalog")
+	defer endSpan(span, &err)
+
+	if err = catalog.Validate(); err != nil {
+		return
+	}
+
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) (err error) {
+		res = fixture.Result{}
+
+		existing, err := s.categoryRepository.Select(ctx)
+		if err != nil {
+			return
+		}
+
+		ids := make(map[categoryKey]string, len(existing))
+		for _, data := range existing {
+			key := categoryKey{name: *data.Name}
+			if data.ParentId != nil {
+				key.parentID = *data.ParentId
+			}
+			ids[key] = data.ID
+		}
+
+		return s.seedCategories(ctx, catalog.Categories, "", ids, &res)
+	})
+
+	return
+}
+
+func (s *Service) seedCategories(ctx context.Context, categories []fixture.Category, parentID string, ids map[categoryKey]string, res *fixture.Result) (err error) {
+	for _, data := range categories {
+		key := categoryKey{parentID: parentID, name: data.Name}
+		id, ok := ids[key]
+		if !ok {
+			created, err := s.createCategory(ctx, uuid.New().String(), category.Request{Name: data.Name, ParentId: parentID})
+			if err != nil {
+				return err
+			}
+			id, ids[key] = created.ID, created.ID
+			res.CategoriesCreated++
+		}
+
+		products := make([]product.Entity, 0, len(data.Products))
+		for _, req := range data.Products {
+			req.CategoryID = id
+			if err = req.Bind(nil); err != nil {
+				return fmt.Errorf("product %s: %w", req.Barcode, err)
+			}
+
+			products = append(products, product.Entity{
+				ID:              uuid.New().String(),
+				CategoryID:      &req.CategoryID,
+				Barcode:         &req.Barcode,
+				Name:            &req.Name,
+				Measure:         &req.Measure,
+				Cost:            &req.Cost,
+				ProducerCountry: &req.ProducerCountry,
+				BrandID:         &req.BrandID,
+				SupplierID:      &req.SupplierID,
+				Description:     &req.Description,
+				Image:           &req.Image,
+				IsWeighted:      &req.IsWeighted,
+			})
+		}
+
+		if len(products) > 0 {
+			if err = s.upsertProducts(ctx, products); err != nil {
+				return
+			}
+			res.ProductsUpserted += len(products)
+		}
+
+		if err = s.seedCategories(ctx, data.Children, id, ids, res); err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -1,8 +1,20 @@
 package service
 
 import (
+	"product/internal/domain/apikey"
+	"product/internal/domain/audit"
+	"product/internal/domain/brand"
 	"product/internal/domain/category"
+	"product/internal/domain/event"
+	"product/internal/domain/imports"
+	"product/internal/domain/job"
+	"product/internal/domain/modifier"
 	"product/internal/domain/product"
+	"product/internal/domain/revision"
+	"product/internal/domain/snapshot"
+	"product/internal/domain/supplier"
+	"product/internal/domain/webhook"
+	"product/pkg/store"
 )
 
 // Configuration is an alias for a function that will take in a pointer to a Service and modify it
@@ -12,6 +24,19 @@ type Configuration func(s *Service) error
 type Service struct {
 	categoryRepository category.Repository
 	productRepository  product.Repository
+	modifierRepository modifier.Repository
+	brandRepository    brand.Repository
+	supplierRepository supplier.Repository
+	importRepository   imports.Repository
+	jobRepository      job.Repository
+	auditRepository    audit.Repository
+	revisionRepository revision.Repository
+	eventRepository    event.Repository
+	webhookRepository  webhook.Repository
+	snapshotRepository snapshot.Repository
+	apiKeyRepository   apikey.Repository
+	transactor         store.Transactor
+	snapshotRetention  int
 }
 
 // New takes a variable amount of Configuration functions and returns a new Service
@@ -48,3 +73,107 @@ func WithProductRepository(productRepository product.Repository) Configuration {
 		return nil
 	}
 }
+
+// WithModifierRepository applies a given modifier group repository to the Service
+func WithModifierRepository(modifierRepository modifier.Repository) Configuration {
+	return func(s *Service) error {
+		s.modifierRepository = modifierRepository
+		return nil
+	}
+}
+
+// WithBrandRepository applies a given brand repository to the Service
+func WithBrandRepository(brandRepository brand.Repository) Configuration {
+	return func(s *Service) error {
+		s.brandRepository = brandRepository
+		return nil
+	}
+}
+
+// WithSupplierRepository applies a given supplier repository to the Service
+func WithSupplierRepository(supplierRepository supplier.Repository) Configuration {
+	return func(s *Service) error {
+		s.supplierRepository = supplierRepository
+		return nil
+	}
+}
+
+// WithImportRepository applies a given import repository to the Service
+func WithImportRepository(importRepository imports.Repository) Configuration {
+	return func(s *Service) error {
+		s.importRepository = importRepository
+		return nil
+	}
+}
+
+// WithJobRepository applies a given job repository to the Service
+func WithJobRepository(jobRepository job.Repository) Configuration {
+	return func(s *Service) error {
+		s.jobRepository = jobRepository
+		return nil
+	}
+}
+
+// WithAuditRepository applies a given audit log repository to the Service
+func WithAuditRepository(auditRepository audit.Repository) Configuration {
+	return func(s *Service) error {
+		s.auditRepository = auditRepository
+		return nil
+	}
+}
+
+// WithRevisionRepository applies a given revision repository to the Service
+func WithRevisionRepository(revisionRepository revision.Repository) Configuration {
+	return func(s *Service) error {
+		s.revisionRepository = revisionRepository
+		return nil
+	}
+}
+
+// WithEventRepository applies a given outbox repository to the Service
+func WithEventRepository(eventRepository event.Repository) Configuration {
+	return func(s *Service) error {
+		s.eventRepository = eventRepository
+		return nil
+	}
+}
+
+// WithWebhookRepository applies a given webhook repository to the Service
+func WithWebhookRepository(webhookRepository webhook.Repository) Configuration {
+	return func(s *Service) error {
+		s.webhookRepository = webhookRepository
+		return nil
+	}
+}
+
+// WithSnapshotRepository applies a given snapshot repository to the Service
+func WithSnapshotRepository(snapshotRepository snapshot.Repository) Configuration {
+	return func(s *Service) error {
+		s.snapshotRepository = snapshotRepository
+		return nil
+	}
+}
+
+// WithAPIKeyRepository applies a given device API key repository to the Service
+func WithAPIKeyRepository(apiKeyRepository apikey.Repository) Configuration {
+	return func(s *Service) error {
+		s.apiKeyRepository = apiKeyRepository
+		return nil
+	}
+}
+
+// WithSnapshotRetention sets how many catalog snapshots are kept
+func WithSnapshotRetention(retention int) Configuration {
+	return func(s *Service) error {
+		s.snapshotRetention = retention
+		return nil
+	}
+}
+
+// WithTransactor applies a given transaction runner to the Service
+func WithTransactor(transactor store.Transactor) Configuration {
+	return func(s *Service) error {
+		s.transactor = transactor
+		return nil
+	}
+}
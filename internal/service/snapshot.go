@@ -0,0 +1,276 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"product/internal/domain/category"
+	"product/internal/domain/change"
+	"product/internal/domain/job"
+	"product/internal/domain/product"
+	"product/internal/domain/snapshot"
+	"product/internal/worker"
+	"product/pkg/apperror"
+	"product/pkg/delta"
+)
+
+const (
+	defaultSnapshotRetention = 10
+	snapshotListLimit        = 100
+)
+
+var ErrSnapshotUnavailable = apperror.New(apperror.NotFound, "snapshot: no snapshot was generated yet")
+
+func (s *Service) ListSnapshots(ctx context.Context) (res []snapshot.Response, err error) {
+	ctx, span := startSpan(ctx, "ListSnapshots")
+	defer endSpan(span, &err)
+
+	data, err := s.snapshotRepository.Select(ctx, snapshotListLimit)
+	if err != nil {
+		return
+	}
+	res = snapshot.ParseFromEntities(data)
+
+	return
+}
+
+// QueueSnapshot queues a job that writes a new snapshot of the catalog. No new
+// version is written when the catalog did not change since the latest one.
+func (s *Service) QueueSnapshot(ctx context.Context) (res job.Response, err error) {
+	ctx, span := startSpan(ctx, "QueueSnapshot")
+	defer endSpan(span, &err)
+
+	id, err := s.enqueueJob(ctx, job.TypeSnapshot, struct{}{})
+	if err != nil {
+		return
+	}
+
+	return s.GetJob(ctx, id)
+}
+
+// LatestSnapshot returns the gzipped bundle of the latest snapshot.
+func (s *Service) LatestSnapshot(ctx context.Context) (bundle []byte, res snapshot.Response, err error) {
+	ctx, span := startSpan(ctx, "LatestSnapshot")
+	defer endSpan(span, &err)
+
+	data, err := s.snapshotRepository.Latest(ctx)
+	if apperror.Is(err, apperror.NotFound) {
+		err = ErrSnapshotUnavailable
+	}
+	if err != nil {
+		return
+	}
+	res = snapshot.ParseFromEntity(data)
+
+	bundle, err = s.snapshotRepository.Bundle(ctx, data.Version)
+
+	return
+}
+
+// DiffSnapshot returns a binary delta that turns the uncompressed bundle of
+// version from into the one of the latest snapshot, see package delta. Deltas
+// are kept in the database once computed.
+func (s *Service) DiffSnapshot(ctx context.Context, from int64) (patch []byte, res snapshot.Response, err error) {
+	ctx, span := startSpan(ctx, "DiffSnapshot")
+	defer endSpan(span, &err)
+
+	latest, err := s.snapshotRepository.Latest(ctx)
+	if apperror.Is(err, apperror.NotFound) {
+		err = ErrSnapshotUnavailable
+	}
+	if err != nil {
+		return
+	}
+	res = snapshot.ParseFromEntity(latest)
+
+	if _, err = s.snapshotRepository.Get(ctx, from); err != nil {
+		return
+	}
+
+	if patch, err = s.snapshotRepository.Delta(ctx, from, latest.Version); !apperror.Is(err, apperror.NotFound) {
+		return
+	}
+
+	base, err := s.readSnapshot(ctx, from)
+	if err != nil {
+		return
+	}
+	target, err := s.readSnapshot(ctx, latest.Version)
+	if err != nil {
+		return
+	}
+	patch = delta.Diff(base, target)
+
+	err = s.snapshotRepository.CreateDelta(ctx, from, latest.Version, patch)
+
+	return
+}
+
+func (s *Service) snapshotJob(ctx context.Context, task *worker.Task) (err error) {
+	// The feed position is taken before the catalog is read: changes made in
+	// between are in the bundle and replayed harmlessly from the feed.
+	txid, id, err := s.eventRepository.Position(ctx)
+	if err != nil {
+		return
+	}
+	token := change.Token{TxID: txid, ID: id}.String()
+
+	var bundle bytes.Buffer
+	data, err := s.writeSnapshot(ctx, &bundle)
+	if err != nil {
+		return
+	}
+	data.SyncToken = &token
+	data.Bundle = bundle.Bytes()
+
+	latest, err := s.snapshotRepository.Latest(ctx)
+	if err != nil && !apperror.Is(err, apperror.NotFound) {
+		return
+	}
+	if err == nil && *latest.Hash == *data.Hash {
+		return task.SetResult(snapshot.Result{Version: latest.Version, Unchanged: true})
+	}
+
+	if data.Version, err = s.snapshotRepository.Create(ctx, data); err != nil {
+		return
+	}
+
+	if err = task.SetResult(snapshot.Result{Version: data.Version}); err != nil {
+		return
+	}
+
+	// Should pruning fail, the retry finds the catalog unchanged and prunes again.
+	return s.pruneSnapshots(ctx)
+}
+
+// writeSnapshot writes the gzipped bundle to w. It holds one JSON document
+// with an entity per line, sorted by id, so that consecutive bundles differ
+// only where the catalog did and deltas between them stay small.
+func (s *Service) writeSnapshot(ctx context.Context, w io.Writer) (data snapshot.Entity, err error) {
+	compressed := &countingWriter{w: w}
+	gz := gzip.NewWriter(compressed)
+	hash := sha256.New()
+	raw := &countingWriter{w: io.MultiWriter(gz, hash)}
+	out := bufio.NewWriter(raw)
+
+	categories, err := s.categoryRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	paths := category.Paths(categories)
+
+	writeItems := func(name string, first bool, next func(fn func(item any) error) error) (count int, err error) {
+		if !first {
+			out.WriteString("\n],")
+		}
+		fmt.Fprintf(out, "%q:[", name)
+
+		err = next(func(item any) error {
+			if count > 0 {
+				out.WriteByte(',')
+			}
+			count++
+			out.WriteByte('\n')
+
+			encoded, err := json.Marshal(item)
+			if err != nil {
+				return err
+			}
+			_, err = out.Write(encoded)
+			return err
+		})
+		return
+	}
+
+	fmt.Fprintf(out, `{"format":%d,`, snapshot.BundleFormat)
+
+	categoryCount, err := writeItems("categories", true, func(fn func(item any) error) error {
+		for _, object := range categories {
+			item := snapshot.Category{ID: object.ID, Name: *object.Name, Path: paths[object.ID]}
+			if object.ParentId != nil {
+				item.ParentID = *object.ParentId
+			}
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	productCount, err := writeItems("products", false, func(fn func(item any) error) error {
+		return s.productRepository.Stream(ctx, product.Filters{}, func(object product.Entity) error {
+			return fn(product.ParseFromEntity(object))
+		})
+	})
+	if err != nil {
+		return
+	}
+	out.WriteString("\n]}\n")
+
+	if err = out.Flush(); err != nil {
+		return
+	}
+	if err = gz.Close(); err != nil {
+		return
+	}
+
+	digest := hex.EncodeToString(hash.Sum(nil))
+	data = snapshot.Entity{
+		Hash:           &digest,
+		Size:           &raw.n,
+		CompressedSize: &compressed.n,
+		Categories:     &categoryCount,
+		Products:       &productCount,
+	}
+
+	return
+}
+
+// pruneSnapshots drops the snapshots past the retention limit along with the
+// deltas computed from them.
+func (s *Service) pruneSnapshots(ctx context.Context) (err error) {
+	keep := s.snapshotRetention
+	if keep <= 0 {
+		keep = defaultSnapshotRetention
+	}
+
+	_, err = s.snapshotRepository.Prune(ctx, keep)
+
+	return
+}
+
+func (s *Service) readSnapshot(ctx context.Context, version int64) (data []byte, err error) {
+	bundle, err := s.snapshotRepository.Bundle(ctx, version)
+	if err != nil {
+		return
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	if err != nil {
+		return
+	}
+	defer gz.Close()
+
+	return io.ReadAll(gz)
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (n int, err error) {
+	n, err = c.w.Write(p)
+	c.n += int64(n)
+	return
+}
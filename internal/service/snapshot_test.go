@@ -0,0 +1,172 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	"product/internal/domain/category"
+	"product/internal/repository/memory"
+	"product/internal/worker"
+	"product/pkg/apperror"
+	"product/pkg/delta"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func newSnapshotService(t *testing.T, retention int) (*Service, *memory.CategoryRepository) {
+	t.Helper()
+
+	db := memory.NewStore()
+	categories := memory.NewCategoryRepository(db)
+	s, err := New(
+		WithCategoryRepository(categories),
+		WithProductRepository(memory.NewProductRepository(db)),
+		WithEventRepository(memory.NewEventRepository(db)),
+		WithSnapshotRepository(memory.NewSnapshotRepository(db)),
+		WithTransactor(db),
+		WithSnapshotRetention(retention),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, categories
+}
+
+func addCategory(t *testing.T, categories *memory.CategoryRepository, id, name string) {
+	t.Helper()
+
+	if _, err := categories.Create(context.Background(), category.Entity{ID: id, Name: &name, ParentId: ptr("")}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// runSnapshotJob runs the snapshot job and returns the latest version after it.
+func runSnapshotJob(t *testing.T, s *Service) int64 {
+	t.Helper()
+
+	ctx := context.Background()
+	if err := s.snapshotJob(ctx, &worker.Task{}); err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := s.snapshotRepository.Latest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return latest.Version
+}
+
+func gunzip(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gz.Close()
+
+	raw, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+func TestSnapshotsAreServedFromTheRepository(t *testing.T) {
+	ctx := context.Background()
+	s, categories := newSnapshotService(t, 0)
+
+	if _, _, err := s.LatestSnapshot(ctx); err != ErrSnapshotUnavailable {
+		t.Fatalf("LatestSnapshot() error = %v before any snapshot, want %v", err, ErrSnapshotUnavailable)
+	}
+
+	addCategory(t, categories, "c1", "Drinks")
+	first := runSnapshotJob(t, s)
+	if unchanged := runSnapshotJob(t, s); unchanged != first {
+		t.Errorf("snapshot of an unchanged catalog is version %d, want %d kept", unchanged, first)
+	}
+
+	addCategory(t, categories, "c2", "Snacks")
+	second := runSnapshotJob(t, s)
+	if second == first {
+		t.Fatalf("snapshot of a changed catalog is version %d, want a new version", second)
+	}
+
+	bundle, res, err := s.LatestSnapshot(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Version != second {
+		t.Errorf("LatestSnapshot() version = %d, want %d", res.Version, second)
+	}
+	latest := gunzip(t, bundle)
+	if !bytes.Contains(latest, []byte(`"Snacks"`)) {
+		t.Errorf("latest bundle misses the new category:\n%s", latest)
+	}
+
+	// The delta is computed once and then read back from the repository.
+	for range 2 {
+		patch, _, err := s.DiffSnapshot(ctx, first)
+		if err != nil {
+			t.Fatal(err)
+		}
+		base, err := s.readSnapshot(ctx, first)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := delta.Patch(base, patch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, latest) {
+			t.Errorf("patched bundle = %s, want %s", got, latest)
+		}
+	}
+	if _, err := s.snapshotRepository.Delta(ctx, first, second); err != nil {
+		t.Errorf("Delta() error = %v, want the delta kept", err)
+	}
+
+	if _, _, err := s.DiffSnapshot(ctx, 99); !apperror.Is(err, apperror.NotFound) {
+		t.Errorf("DiffSnapshot() error = %v for an unknown version, want not found", err)
+	}
+}
+
+func TestSnapshotsArePruned(t *testing.T) {
+	ctx := context.Background()
+	s, categories := newSnapshotService(t, 2)
+
+	addCategory(t, categories, "c1", "Drinks")
+	first := runSnapshotJob(t, s)
+	addCategory(t, categories, "c2", "Snacks")
+	second := runSnapshotJob(t, s)
+	if _, _, err := s.DiffSnapshot(ctx, first); err != nil {
+		t.Fatal(err)
+	}
+
+	addCategory(t, categories, "c3", "Frozen")
+	third := runSnapshotJob(t, s)
+
+	list, err := s.ListSnapshots(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []int64
+	for _, item := range list {
+		versions = append(versions, item.Version)
+	}
+	if len(versions) != 2 || versions[0] != third || versions[1] != second {
+		t.Errorf("snapshots = %v, want [%d %d]", versions, third, second)
+	}
+	if _, err := s.snapshotRepository.Bundle(ctx, first); !apperror.Is(err, apperror.NotFound) {
+		t.Errorf("Bundle() error = %v for a pruned snapshot, want not found", err)
+	}
+	if _, err := s.snapshotRepository.Delta(ctx, first, second); !apperror.Is(err, apperror.NotFound) {
+		t.Errorf("Delta() error = %v from a pruned snapshot, want not found", err)
+	}
+}
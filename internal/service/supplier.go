@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/audit"
+	"product/internal/domain/supplier"
+)
+
+func (s *Service) ListSuppliers(ctx context.Context) (res []supplier.Response, err error) {
+	ctx, span := startSpan(ctx, "ListSuppliers")
+	defer endSpan(span, &err)
+
+	data, err := s.supplierRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = supplier.ParseFromEntities(data)
+
+	return
+}
+
+func (s *Service) AddSupplier(ctx context.Context, req supplier.Request) (res supplier.Response, err error) {
+	ctx, span := startSpan(ctx, "AddSupplier")
+	defer endSpan(span, &err)
+
+	data := supplier.Entity{
+		ID:      uuid.New().String(),
+		Name:    &req.Name,
+		Country: &req.Country,
+		Email:   &req.Email,
+		Phone:   &req.Phone,
+	}
+
+	data.ID, err = s.audited(ctx, audit.EntitySupplier, audit.OperationCreate, "", s.supplierSnapshot, func(ctx context.Context) (string, error) {
+		return s.supplierRepository.Create(ctx, data)
+	})
+	if err != nil {
+		return
+	}
+	res = supplier.ParseFromEntity(data)
+
+	return
+}
+
+func (s *Service) GetSupplier(ctx context.Context, id string) (res supplier.Response, err error) {
+	ctx, span := startSpan(ctx, "GetSupplier")
+	defer endSpan(span, &err)
+
+	data, err := s.supplierRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = supplier.ParseFromEntity(data)
+
+	return
+}
+
+func (s *Service) UpdateSupplier(ctx context.Context, id string, req supplier.Request) (err error) {
+	ctx, span := startSpan(ctx, "UpdateSupplier")
+	defer endSpan(span, &err)
+
+	data := supplier.Entity{
+		ID:      id,
+		Name:    &req.Name,
+		Country: &req.Country,
+		Email:   &req.Email,
+		Phone:   &req.Phone,
+	}
+	_, err = s.audited(ctx, audit.EntitySupplier, audit.OperationUpdate, id, s.supplierSnapshot, func(ctx context.Context) (string, error) {
+		return id, s.supplierRepository.Update(ctx, id, data)
+	})
+
+	return
+}
+
+func (s *Service) DeleteSupplier(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteSupplier")
+	defer endSpan(span, &err)
+
+	_, err = s.audited(ctx, audit.EntitySupplier, audit.OperationDelete, id, s.supplierSnapshot, func(ctx context.Context) (string, error) {
+		return id, s.supplierRepository.Delete(ctx, id)
+	})
+
+	return
+}
+
+func (s *Service) supplierSnapshot(ctx context.Context, id string) (any, error) {
+	return s.GetSupplier(ctx, id)
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"product/pkg/apperror"
+)
+
+var tracer = otel.Tracer("product/internal/service")
+
+// startSpan starts the span of a service method, to be ended with endSpan.
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "Service."+name)
+}
+
+// endSpan ends span with the error the method returned. Only internal errors
+// mark the span as failed, the others are the caller's fault.
+func endSpan(span trace.Span, err *error) {
+	if *err != nil {
+		span.RecordError(*err)
+		if apperror.KindOf(*err) == apperror.Internal {
+			span.SetStatus(codes.Error, (*err).Error())
+		}
+	}
+	span.End()
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/google/uuid"
+
+	"product/internal/domain/webhook"
+)
+
+func (s *Service) ListWebhooks(ctx context.Context) (res []webhook.Response, err error) {
+	ctx, span := startSpan(ctx, "ListWebhooks")
+	defer endSpan(span, &err)
+
+	data, err := s.webhookRepository.Select(ctx)
+	if err != nil {
+		return
+	}
+	res = webhook.ParseFromEntities(data)
+
+	return
+}
+
+// AddWebhook registers a webhook. The response is the only one carrying the
+// secret deliveries are signed with; one is generated when none is given.
+func (s *Service) AddWebhook(ctx context.Context, req webhook.Request) (res webhook.Response, err error) {
+	ctx, span := startSpan(ctx, "AddWebhook")
+	defer endSpan(span, &err)
+
+	if req.Secret == "" {
+		if req.Secret, err = generateSecret(); err != nil {
+			return
+		}
+	}
+
+	data := webhook.Entity{
+		ID:         uuid.New().String(),
+		URL:        &req.URL,
+		EventTypes: req.EventTypes,
+		Secret:     &req.Secret,
+		Active:     req.Active,
+	}
+
+	data.ID, err = s.webhookRepository.Create(ctx, data)
+	if err != nil {
+		return
+	}
+
+	if data, err = s.webhookRepository.Get(ctx, data.ID); err != nil {
+		return
+	}
+	res = webhook.ParseFromEntity(data)
+	res.Secret = *data.Secret
+
+	return
+}
+
+func (s *Service) GetWebhook(ctx context.Context, id string) (res webhook.Response, err error) {
+	ctx, span := startSpan(ctx, "GetWebhook")
+	defer endSpan(span, &err)
+
+	data, err := s.webhookRepository.Get(ctx, id)
+	if err != nil {
+		return
+	}
+	res = webhook.ParseFromEntity(data)
+
+	return
+}
+
+// UpdateWebhook replaces the settings of a webhook, keeping its secret unless a new one is given.
+func (s *Service) UpdateWebhook(ctx context.Context, id string, req webhook.Request) (err error) {
+	ctx, span := startSpan(ctx, "UpdateWebhook")
+	defer endSpan(span, &err)
+
+	data := webhook.Entity{
+		URL:        &req.URL,
+		EventTypes: req.EventTypes,
+		Active:     req.Active,
+	}
+	if req.Secret != "" {
+		data.Secret = &req.Secret
+	}
+
+	return s.webhookRepository.Update(ctx, id, data)
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteWebhook")
+	defer endSpan(span, &err)
+
+	return s.webhookRepository.Delete(ctx, id)
+}
+
+// ListWebhookDeliveries returns the latest deliveries of a webhook, newest first.
+func (s *Service) ListWebhookDeliveries(ctx context.Context, id string, limit int) (res []webhook.DeliveryResponse, err error) {
+	ctx, span := startSpan(ctx, "ListWebhookDeliveries")
+	defer endSpan(span, &err)
+
+	if _, err = s.webhookRepository.Get(ctx, id); err != nil {
+		return
+	}
+
+	data, err := s.webhookRepository.SelectDeliveries(ctx, id, limit)
+	if err != nil {
+		return
+	}
+	res = webhook.ParseFromDeliveries(data)
+
+	return
+}
+
+// RedeliverWebhook sends a delivery again as soon as possible, with a fresh
+// set of attempts, including one that was dead-lettered.
+func (s *Service) RedeliverWebhook(ctx context.Context, id string, deliveryID int64) (err error) {
+	ctx, span := startSpan(ctx, "RedeliverWebhook")
+	defer endSpan(span, &err)
+
+	return s.webhookRepository.Redeliver(ctx, id, deliveryID)
+}
+
+func generateSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
@@ -0,0 +1,81 @@
+package tracing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recorder collects the spans ended by the tests of the package. The global
+// provider can only be replaced once, so the tests share it.
+var recorder = tracetest.NewSpanRecorder()
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+}
+
+// serve runs a request through the middleware and returns the span it ended.
+func serve(t *testing.T, r *http.Request) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	router := chi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/products/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !trace.SpanContextFromContext(r.Context()).IsValid() {
+			t.Error("the handler context carries no span")
+		}
+		if chi.URLParam(r, "id") == "broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+
+	before := len(recorder.Ended())
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	ended := recorder.Ended()
+	if len(ended) != before+1 {
+		t.Fatalf("got %d spans, want 1", len(ended)-before)
+	}
+	return ended[len(ended)-1]
+}
+
+func TestMiddlewareNamesSpansAfterRoutes(t *testing.T) {
+	span := serve(t, httptest.NewRequest(http.MethodGet, "/products/1", nil))
+
+	if span.Name() != "GET /products/{id}" {
+		t.Errorf("got span %q, want \"GET /products/{id}\"", span.Name())
+	}
+	if span.SpanKind() != trace.SpanKindServer || span.Status().Code == codes.Error {
+		t.Errorf("got a %s span with status %s", span.SpanKind(), span.Status().Code)
+	}
+}
+
+func TestMiddlewareContinuesTraces(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/products/1", nil)
+	r.Header.Set("traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	span := serve(t, r)
+	if got := span.SpanContext().TraceID().String(); got != "0af7651916cd43dd8448eb211c80319c" {
+		t.Errorf("got trace %s, want the one of the traceparent header", got)
+	}
+	if got := span.Parent().SpanID().String(); got != "b7ad6b7169203331" {
+		t.Errorf("got parent %s, want the span of the traceparent header", got)
+	}
+}
+
+func TestMiddlewareMarksServerErrors(t *testing.T) {
+	span := serve(t, httptest.NewRequest(http.MethodGet, "/products/broken", nil))
+
+	if span.Status().Code != codes.Error {
+		t.Errorf("got status %s for a 500, want %s", span.Status().Code, codes.Error)
+	}
+}
@@ -0,0 +1,138 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Tracing and modify it
+type Configuration func(t *Tracing) error
+
+// Tracing owns the tracer provider spans are exported with.
+type Tracing struct {
+	exporter    string
+	endpoint    string
+	insecure    bool
+	sampleRatio float64
+	service     string
+	version     string
+
+	provider *sdktrace.TracerProvider
+}
+
+// New takes a variable amount of Configuration functions and returns a new Tracing
+// Each Configuration will be called in the order they are passed in. The
+// provider is installed globally, along with the W3C trace context and
+// baggage propagators, so every otel.Tracer reports to it.
+func New(configs ...Configuration) (t *Tracing, err error) {
+	t = &Tracing{
+		exporter:    ExporterNone,
+		sampleRatio: 1,
+	}
+
+	for _, cfg := range configs {
+		if err = cfg(t); err != nil {
+			return
+		}
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, err := t.newExporter()
+	if err != nil || exporter == nil {
+		return
+	}
+
+	res, err := resource.New(context.Background(),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(semconv.ServiceName(t.service), semconv.ServiceVersion(t.version)))
+	if err != nil {
+		return
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(t.sampleRatio))))
+	otel.SetTracerProvider(t.provider)
+
+	return
+}
+
+// WithExporter sets where spans are sent: "otlp" to an OTLP/HTTP collector at
+// endpoint, "stdout" to the standard output, or "none" to drop them. An empty
+// endpoint falls back to the OTEL_EXPORTER_OTLP_* environment variables.
+func WithExporter(exporter, endpoint string, insecure bool) Configuration {
+	return func(t *Tracing) error {
+		switch exporter {
+		case ExporterNone, ExporterOTLP, ExporterStdout:
+		default:
+			return fmt.Errorf("unknown trace exporter %q", exporter)
+		}
+
+		t.exporter, t.endpoint, t.insecure = exporter, endpoint, insecure
+		return nil
+	}
+}
+
+// WithSampleRatio sets the share of new traces that are recorded. Requests
+// continuing a trace follow the decision of their caller.
+func WithSampleRatio(ratio float64) Configuration {
+	return func(t *Tracing) error {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("trace sample ratio %v is not between 0 and 1", ratio)
+		}
+		t.sampleRatio = ratio
+		return nil
+	}
+}
+
+// WithService sets the name and version spans are attributed to.
+func WithService(name, version string) Configuration {
+	return func(t *Tracing) error {
+		t.service, t.version = name, version
+		return nil
+	}
+}
+
+// Shutdown flushes the spans still buffered.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+	return t.provider.Shutdown(ctx)
+}
+
+func (t *Tracing) newExporter() (sdktrace.SpanExporter, error) {
+	switch t.exporter {
+	case ExporterOTLP:
+		var options []otlptracehttp.Option
+		if t.endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpointURL(t.endpoint))
+		}
+		if t.insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), options...)
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, nil
+	}
+}
@@ -0,0 +1,266 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"product/internal/domain/webhook"
+)
+
+const (
+	defaultConcurrency   = 4
+	defaultPollInterval  = time.Second
+	defaultMaxAttempts   = 8
+	defaultTimeout       = 10 * time.Second
+	defaultRetryBase     = 10 * time.Second
+	defaultRetryMaxDelay = 6 * time.Hour
+
+	// maxErrorBody is how much of a failed response is kept in the delivery log.
+	maxErrorBody = 1024
+)
+
+// Configuration is an alias for a function that will take in a pointer to a Dispatcher and modify it
+type Configuration func(d *Dispatcher) error
+
+// Dispatcher sends queued deliveries to the webhooks. A delivery that fails is
+// retried with exponential backoff and dead-lettered once it runs out of attempts.
+type Dispatcher struct {
+	repository   webhook.Repository
+	client       *http.Client
+	concurrency  int
+	pollInterval time.Duration
+	maxAttempts  int
+	logger       *zap.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New takes a variable amount of Configuration functions and returns a new Dispatcher
+// Each Configuration will be called in the order they are passed in
+func New(repository webhook.Repository, configs ...Configuration) (d *Dispatcher, err error) {
+	d = &Dispatcher{
+		repository:   repository,
+		client:       &http.Client{Timeout: defaultTimeout},
+		concurrency:  defaultConcurrency,
+		pollInterval: defaultPollInterval,
+		maxAttempts:  defaultMaxAttempts,
+		logger:       zap.NewNop(),
+	}
+
+	for _, cfg := range configs {
+		if err = cfg(d); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WithConcurrency sets how many deliveries are sent at the same time.
+func WithConcurrency(concurrency int) Configuration {
+	return func(d *Dispatcher) error {
+		if concurrency > 0 {
+			d.concurrency = concurrency
+		}
+		return nil
+	}
+}
+
+// WithPollInterval sets how long the dispatcher waits when nothing is due.
+func WithPollInterval(interval time.Duration) Configuration {
+	return func(d *Dispatcher) error {
+		if interval > 0 {
+			d.pollInterval = interval
+		}
+		return nil
+	}
+}
+
+// WithMaxAttempts sets after how many failed attempts a delivery is dead-lettered.
+func WithMaxAttempts(attempts int) Configuration {
+	return func(d *Dispatcher) error {
+		if attempts > 0 {
+			d.maxAttempts = attempts
+		}
+		return nil
+	}
+}
+
+// WithTimeout sets how long a webhook has to answer a delivery.
+func WithTimeout(timeout time.Duration) Configuration {
+	return func(d *Dispatcher) error {
+		if timeout > 0 {
+			d.client.Timeout = timeout
+		}
+		return nil
+	}
+}
+
+// WithHTTPClient replaces the client deliveries are sent with.
+func WithHTTPClient(client *http.Client) Configuration {
+	return func(d *Dispatcher) error {
+		d.client = client
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the dispatcher.
+func WithLogger(logger *zap.Logger) Configuration {
+	return func(d *Dispatcher) error {
+		d.logger = logger
+		return nil
+	}
+}
+
+// Start sends due deliveries in the background until Stop is called.
+func (d *Dispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		d.loop(ctx)
+	}()
+
+	d.logger.Info("webhook dispatcher started", zap.Int("concurrency", d.concurrency))
+}
+
+// Stop waits for the deliveries in flight, or until ctx is done.
+func (d *Dispatcher) Stop(ctx context.Context) error {
+	if d.cancel == nil {
+		return nil
+	}
+	d.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) loop(ctx context.Context) {
+	for {
+		sent, err := d.Dispatch(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.logger.Error("ERR_DISPATCH_WEBHOOKS", zap.Error(err))
+		}
+
+		// A full batch means more deliveries are probably due.
+		if err == nil && sent == d.concurrency {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(d.pollInterval):
+		}
+	}
+}
+
+// Dispatch sends one batch of due deliveries and returns how many were attempted.
+func (d *Dispatcher) Dispatch(ctx context.Context) (sent int, err error) {
+	// A claimed delivery is put off until the request times out, so it is
+	// retried if this instance dies before recording the outcome.
+	deliveries, err := d.repository.ClaimDeliveries(ctx, d.concurrency, 2*d.client.Timeout+time.Minute)
+	if err != nil {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.deliver(ctx, delivery)
+		}()
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery webhook.Delivery) {
+	logger := d.logger.With(zap.Int64("delivery", delivery.ID), zap.String("webhook", *delivery.WebhookID))
+
+	// Deliveries in flight are let finish when the dispatcher stops, the
+	// client timeout bounds how long that takes.
+	ctx = context.WithoutCancel(ctx)
+	statusCode, err := d.send(ctx, delivery)
+
+	status, message, delay := webhook.DeliverySucceeded, "", time.Duration(0)
+	if err != nil {
+		message = err.Error()
+		if *delivery.Attempts >= d.maxAttempts {
+			status = webhook.DeliveryDead
+			logger.Error("ERR_DELIVER_WEBHOOK", zap.Int("attempts", *delivery.Attempts), zap.Error(err))
+		} else {
+			status, delay = webhook.DeliveryPending, backoff(*delivery.Attempts)
+			logger.Warn("webhook delivery failed, retrying", zap.Error(err), zap.Duration("delay", delay))
+		}
+	}
+
+	if err = d.repository.FinishDelivery(ctx, delivery.ID, status, statusCode, message, delay); err != nil {
+		logger.Error("ERR_FINISH_DELIVERY", zap.Error(err))
+	}
+}
+
+// send posts the delivery payload and treats any status other than 2xx as a failure.
+func (d *Dispatcher) send(ctx context.Context, delivery webhook.Delivery) (statusCode int, err error) {
+	body := []byte(*delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, *delivery.URL, bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, *delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(*delivery.Secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return
+	}
+	defer res.Body.Close()
+
+	statusCode = res.StatusCode
+	if statusCode < 200 || statusCode > 299 {
+		excerpt, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBody))
+		err = fmt.Errorf("webhook responded %s: %s", res.Status, bytes.TrimSpace(excerpt))
+		return
+	}
+	io.Copy(io.Discard, io.LimitReader(res.Body, maxErrorBody))
+
+	return
+}
+
+// backoff doubles the retry delay with every attempt and adds some jitter.
+func backoff(attempt int) time.Duration {
+	delay := defaultRetryBase << (attempt - 1)
+	if delay <= 0 || delay > defaultRetryMaxDelay {
+		delay = defaultRetryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
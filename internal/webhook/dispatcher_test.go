@@ -0,0 +1,255 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"product/internal/domain/webhook"
+)
+
+const testSecret = "s3cret"
+
+// finish is the outcome of an attempt, as recorded by FinishDelivery.
+type finish struct {
+	status     string
+	statusCode int
+	lastError  string
+	delay      time.Duration
+}
+
+// fakeRepository queues deliveries in memory. Every pending delivery is due,
+// the tests call Dispatch once per attempt.
+type fakeRepository struct {
+	webhook.Repository
+
+	mu         sync.Mutex
+	deliveries []webhook.Delivery
+	finishes   []finish
+}
+
+func newFakeRepository(url string) *fakeRepository {
+	status, attempts := webhook.DeliveryPending, 0
+	return &fakeRepository{
+		deliveries: []webhook.Delivery{{
+			ID:        42,
+			WebhookID: ptr("w1"),
+			EventType: ptr("product.updated"),
+			Payload:   ptr(`{"id":"p1"}`),
+			Status:    &status,
+			Attempts:  &attempts,
+			URL:       &url,
+			Secret:    ptr(testSecret),
+		}},
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func (r *fakeRepository) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) (dest []webhook.Delivery, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.deliveries {
+		if d := &r.deliveries[i]; *d.Status == webhook.DeliveryPending && len(dest) < limit {
+			d.Attempts = ptr(*d.Attempts + 1)
+			dest = append(dest, *d)
+		}
+	}
+	return
+}
+
+func (r *fakeRepository) FinishDelivery(ctx context.Context, id int64, status string, statusCode int, lastError string, delay time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == id {
+			r.deliveries[i].Status = &status
+		}
+	}
+	r.finishes = append(r.finishes, finish{status, statusCode, lastError, delay})
+	return nil
+}
+
+func (r *fakeRepository) last(t *testing.T) finish {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.finishes) == 0 {
+		t.Fatal("no attempt was recorded")
+	}
+	return r.finishes[len(r.finishes)-1]
+}
+
+func dispatch(t *testing.T, d *Dispatcher, want int) {
+	t.Helper()
+	sent, err := d.Dispatch(context.Background())
+	if err != nil || sent != want {
+		t.Fatalf("Dispatch = %d, %v, want %d sent", sent, err, want)
+	}
+}
+
+func TestDispatchSignsDeliveries(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received, body = r, must(io.ReadAll(r.Body))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repository := newFakeRepository(server.URL)
+	d, _ := New(repository)
+
+	dispatch(t, d, 1)
+
+	if got := received.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q", got)
+	}
+	if got := received.Header.Get(EventHeader); got != "product.updated" {
+		t.Errorf("%s = %q", EventHeader, got)
+	}
+	if got := received.Header.Get(DeliveryHeader); got != "42" {
+		t.Errorf("%s = %q", DeliveryHeader, got)
+	}
+	if string(body) != `{"id":"p1"}` {
+		t.Errorf("body = %s", body)
+	}
+
+	timestamp, err := strconv.ParseInt(received.Header.Get(TimestampHeader), 10, 64)
+	if err != nil || time.Since(time.Unix(timestamp, 0)).Abs() > time.Minute {
+		t.Fatalf("%s = %q", TimestampHeader, received.Header.Get(TimestampHeader))
+	}
+	signature := received.Header.Get(SignatureHeader)
+	if !strings.HasPrefix(signature, "sha256=") || !Verify(testSecret, timestamp, body, signature) {
+		t.Errorf("%s = %q does not verify", SignatureHeader, signature)
+	}
+	if Verify("other", timestamp, body, signature) || Verify(testSecret, timestamp+1, body, signature) {
+		t.Error("the signature verifies with another secret or timestamp")
+	}
+
+	if got := repository.last(t); got != (finish{status: webhook.DeliverySucceeded, statusCode: http.StatusNoContent}) {
+		t.Errorf("recorded %+v, want a success", got)
+	}
+}
+
+func TestDispatchRetriesServerErrors(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+	}))
+	defer server.Close()
+
+	repository := newFakeRepository(server.URL)
+	d, _ := New(repository, WithMaxAttempts(5))
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		dispatch(t, d, 1)
+
+		got := repository.last(t)
+		if got.status != webhook.DeliveryPending || got.statusCode != http.StatusServiceUnavailable || !strings.Contains(got.lastError, "try later") {
+			t.Fatalf("attempt %d recorded %+v, want a retry after a 503", attempt, got)
+		}
+
+		// The delay doubles with every attempt, give or take the jitter.
+		ceiling := defaultRetryBase << (attempt - 1)
+		if got.delay < ceiling/2 || got.delay > ceiling {
+			t.Fatalf("attempt %d is retried after %s, want between %s and %s", attempt, got.delay, ceiling/2, ceiling)
+		}
+	}
+
+	dispatch(t, d, 1)
+	if got := repository.last(t); got.status != webhook.DeliverySucceeded || got.statusCode != http.StatusOK {
+		t.Fatalf("third attempt recorded %+v, want a success", got)
+	}
+	dispatch(t, d, 0)
+}
+
+func TestDispatchRetriesTimeouts(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	repository := newFakeRepository(server.URL)
+	d, _ := New(repository, WithTimeout(50*time.Millisecond))
+
+	dispatch(t, d, 1)
+
+	got := repository.last(t)
+	if got.status != webhook.DeliveryPending || got.statusCode != 0 || got.lastError == "" || got.delay <= 0 {
+		t.Fatalf("recorded %+v, want a retry after a timeout", got)
+	}
+}
+
+func TestDispatchDeadLetters(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repository := newFakeRepository(server.URL)
+	d, _ := New(repository, WithMaxAttempts(3))
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		dispatch(t, d, 1)
+		if got := repository.last(t); got.status != webhook.DeliveryPending {
+			t.Fatalf("attempt %d recorded %+v, want a retry", attempt, got)
+		}
+	}
+
+	dispatch(t, d, 1)
+	if got := repository.last(t); got.status != webhook.DeliveryDead || got.statusCode != http.StatusInternalServerError || got.delay != 0 {
+		t.Fatalf("last attempt recorded %+v, want a dead letter", got)
+	}
+
+	dispatch(t, d, 0)
+	if got := requests.Load(); got != 3 {
+		t.Fatalf("the webhook got %d requests, want 3", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		ceiling time.Duration
+	}{
+		{1, defaultRetryBase},
+		{2, 2 * defaultRetryBase},
+		{5, 16 * defaultRetryBase},
+		{13, defaultRetryMaxDelay},
+		{100, defaultRetryMaxDelay},
+	}
+	for _, tc := range tests {
+		for range 20 {
+			if got := backoff(tc.attempt); got < tc.ceiling/2 || got > tc.ceiling {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tc.attempt, got, tc.ceiling/2, tc.ceiling)
+			}
+		}
+	}
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
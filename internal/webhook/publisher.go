@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+
+	"product/internal/domain/event"
+	"product/internal/domain/webhook"
+)
+
+// Publisher queues a delivery of every event for each active webhook
+// subscribed to it. Run by the outbox relay, the deliveries are written in the
+// same transaction that marks the event as published.
+type Publisher struct {
+	repository webhook.Repository
+}
+
+func NewPublisher(repository webhook.Repository) *Publisher {
+	return &Publisher{repository: repository}
+}
+
+func (p *Publisher) Publish(ctx context.Context, message event.Message) error {
+	webhooks, err := p.repository.Select(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	payload := string(data)
+
+	deliveries := make([]webhook.Delivery, 0)
+	for _, object := range webhooks {
+		if !*object.Active || !webhook.Matches(object.EventTypes, message.Type) {
+			continue
+		}
+
+		deliveries = append(deliveries, webhook.Delivery{
+			WebhookID: &object.ID,
+			EventID:   &message.ID,
+			EventType: &message.Type,
+			Payload:   &payload,
+		})
+	}
+
+	return p.repository.AddDeliveries(ctx, deliveries)
+}
+
+func (p *Publisher) Close() error {
+	return nil
+}
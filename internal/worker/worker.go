@@ -0,0 +1,443 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"product/internal/domain/job"
+	"product/pkg/apperror"
+	"product/pkg/log"
+	"product/pkg/store"
+)
+
+const (
+	defaultConcurrency   = 2
+	defaultPollInterval  = time.Second
+	defaultHeartbeat     = 5 * time.Second
+	defaultRetryBase     = 2 * time.Second
+	defaultRetryMaxDelay = 10 * time.Minute
+)
+
+var (
+	// ErrCanceled is the cause of a job context canceled on request of a user.
+	ErrCanceled = errors.New("worker: job canceled")
+	// ErrShutdown is the cause of a job context canceled because the pool is stopping.
+	ErrShutdown = errors.New("worker: shutting down")
+)
+
+var tracer = otel.Tracer("product/internal/worker")
+
+// Handler runs a single job. Returning an error schedules a retry unless the
+// error is wrapped with Permanent or the job ran out of attempts.
+type Handler func(ctx context.Context, task *Task) error
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// Configuration is an alias for a function that will take in a pointer to a Pool and modify it
+type Configuration func(p *Pool) error
+
+// Pool claims queued jobs from the database and runs them with the registered handlers.
+type Pool struct {
+	id           string
+	repository   job.Repository
+	handlers     map[string]Handler
+	concurrency  int
+	pollInterval time.Duration
+	heartbeat    time.Duration
+	schedules    []schedule
+	logger       *zap.Logger
+
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+}
+
+// New takes a variable amount of Configuration functions and returns a new Pool
+// Each Configuration will be called in the order they are passed in
+func New(repository job.Repository, configs ...Configuration) (p *Pool, err error) {
+	hostname, _ := os.Hostname()
+
+	p = &Pool{
+		id:           fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8]),
+		repository:   repository,
+		handlers:     make(map[string]Handler),
+		concurrency:  defaultConcurrency,
+		pollInterval: defaultPollInterval,
+		heartbeat:    defaultHeartbeat,
+		logger:       zap.NewNop(),
+	}
+
+	for _, cfg := range configs {
+		if err = cfg(p); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// WithHandler registers the handler of a job type.
+func WithHandler(jobType string, handler Handler) Configuration {
+	return func(p *Pool) error {
+		p.handlers[jobType] = handler
+		return nil
+	}
+}
+
+// WithHandlers registers several handlers at once.
+func WithHandlers(handlers map[string]Handler) Configuration {
+	return func(p *Pool) error {
+		for jobType, handler := range handlers {
+			p.handlers[jobType] = handler
+		}
+		return nil
+	}
+}
+
+// schedule is a function run at a fixed interval while the pool is started.
+type schedule struct {
+	name     string
+	interval time.Duration
+	fn       func(ctx context.Context) error
+}
+
+// WithSchedule runs fn every interval, typically to queue a recurring job.
+// Every instance runs its schedules, so fn must tolerate running concurrently
+// elsewhere. A zero interval disables the schedule.
+func WithSchedule(name string, interval time.Duration, fn func(ctx context.Context) error) Configuration {
+	return func(p *Pool) error {
+		if interval > 0 {
+			p.schedules = append(p.schedules, schedule{name: name, interval: interval, fn: fn})
+		}
+		return nil
+	}
+}
+
+// WithConcurrency sets how many jobs run at the same time.
+func WithConcurrency(concurrency int) Configuration {
+	return func(p *Pool) error {
+		if concurrency > 0 {
+			p.concurrency = concurrency
+		}
+		return nil
+	}
+}
+
+// WithPollInterval sets how long an idle worker waits before looking for work again.
+func WithPollInterval(interval time.Duration) Configuration {
+	return func(p *Pool) error {
+		if interval > 0 {
+			p.pollInterval = interval
+		}
+		return nil
+	}
+}
+
+// WithLogger sets the logger of the pool.
+func WithLogger(logger *zap.Logger) Configuration {
+	return func(p *Pool) error {
+		p.logger = logger
+		return nil
+	}
+}
+
+// Start launches the workers in the background.
+func (p *Pool) Start() {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	p.cancel = cancel
+
+	types := make([]string, 0, len(p.handlers))
+	for jobType := range p.handlers {
+		types = append(types, jobType)
+	}
+
+	for i := 0; i < p.concurrency; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.loop(ctx, types)
+		}()
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.reap(ctx)
+	}()
+
+	for _, entry := range p.schedules {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.tick(ctx, entry)
+		}()
+	}
+
+	p.logger.Info("job workers started", zap.String("worker", p.id), zap.Int("concurrency", p.concurrency))
+}
+
+// Stop cancels the running jobs and waits for them to checkpoint and return
+// to the queue, or until ctx is done.
+func (p *Pool) Stop(ctx context.Context) error {
+	if p.cancel == nil {
+		return nil
+	}
+	p.cancel(ErrShutdown)
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Pool) loop(ctx context.Context, types []string) {
+	for {
+		data, err := p.repository.Claim(ctx, p.id, types)
+		if err == nil {
+			p.run(ctx, data)
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		if !apperror.Is(err, apperror.NotFound) {
+			p.logger.Error("ERR_CLAIM_JOB", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(p.pollInterval):
+		}
+	}
+}
+
+// reap puts jobs back in the queue when the instance that ran them stopped
+// refreshing their lock, e.g. because it crashed.
+func (p *Pool) reap(ctx context.Context) {
+	ticker := time.NewTicker(p.heartbeat * 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			count, err := p.repository.RequeueStale(ctx, p.heartbeat*6)
+			if err != nil && ctx.Err() == nil {
+				p.logger.Error("ERR_REQUEUE_JOBS", zap.Error(err))
+			}
+			if count > 0 {
+				p.logger.Warn("stale jobs requeued", zap.Int64("count", count))
+			}
+		}
+	}
+}
+
+func (p *Pool) tick(ctx context.Context, entry schedule) {
+	ticker := time.NewTicker(entry.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := entry.fn(ctx); err != nil && ctx.Err() == nil {
+				p.logger.Error("ERR_RUN_SCHEDULE", zap.String("schedule", entry.name), zap.Error(err))
+			}
+		}
+	}
+}
+
+func (p *Pool) run(parent context.Context, data job.Entity) {
+	parent, span := tracer.Start(parent, "job "+*data.Type, trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("job.id", data.ID), attribute.Int("job.attempt", *data.Attempts)))
+	defer span.End()
+
+	logger := p.logger.With(zap.String("job", data.ID), zap.String("type", *data.Type)).With(log.TraceFields(parent)...)
+
+	// A job reads its own writes like a request does, see store.WithSession.
+	ctx, cancel := context.WithCancelCause(store.WithSession(parent))
+	defer cancel(nil)
+
+	go p.watch(ctx, cancel, data.ID)
+
+	task := &Task{Entity: data, worker: p.id, repository: p.repository}
+	err := p.call(ctx, task)
+
+	// The job context may be canceled already, the bookkeeping must still reach the database.
+	bg := context.WithoutCancel(ctx)
+
+	switch {
+	case context.Cause(ctx) == job.ErrLockLost || errors.Is(err, job.ErrLockLost):
+		// Another worker runs the job now, its outcome is not ours to record.
+		logger.Warn("job lock lost, abandoning the run", zap.Error(err))
+		return
+	case err == nil:
+		err = p.repository.Finish(bg, data.ID, p.id, job.StatusSucceeded, task.result, nil)
+	case context.Cause(ctx) == ErrShutdown:
+		logger.Info("job released on shutdown")
+		err = p.repository.Release(bg, data.ID, p.id)
+	case context.Cause(ctx) == ErrCanceled:
+		message := ErrCanceled.Error()
+		err = p.repository.Finish(bg, data.ID, p.id, job.StatusCanceled, task.result, &message)
+	default:
+		message := err.Error()
+		var permanent permanentError
+		span.SetStatus(codes.Error, message)
+		if errors.As(err, &permanent) || *data.Attempts >= *data.MaxAttempts {
+			logger.Error("ERR_RUN_JOB", zap.Error(err))
+			err = p.repository.Finish(bg, data.ID, p.id, job.StatusFailed, task.result, &message)
+		} else {
+			delay := backoff(*data.Attempts)
+			logger.Warn("job failed, retrying", zap.Error(err), zap.Duration("delay", delay))
+			err = p.repository.Retry(bg, data.ID, p.id, delay, message)
+		}
+	}
+
+	if errors.Is(err, job.ErrLockLost) {
+		logger.Warn("job lock lost before its outcome was recorded")
+	} else if err != nil {
+		logger.Error("ERR_FINISH_JOB", zap.Error(err))
+	}
+}
+
+func (p *Pool) call(ctx context.Context, task *Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = Permanent(fmt.Errorf("worker: job panicked: %v", r))
+		}
+	}()
+
+	handler, ok := p.handlers[*task.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("worker: no handler for job type %s", *task.Type))
+	}
+
+	return handler(ctx, task)
+}
+
+// watch refreshes the lock of a running job and cancels it when a user asks
+// to or when another worker took the job over.
+func (p *Pool) watch(ctx context.Context, cancel context.CancelCauseFunc, id string) {
+	ticker := time.NewTicker(p.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cancelRequested, err := p.repository.Heartbeat(ctx, id, p.id)
+			if errors.Is(err, job.ErrLockLost) {
+				cancel(job.ErrLockLost)
+				return
+			}
+			if err != nil && ctx.Err() == nil {
+				p.logger.Error("ERR_JOB_HEARTBEAT", zap.String("job", id), zap.Error(err))
+			}
+			if cancelRequested {
+				cancel(ErrCanceled)
+				return
+			}
+		}
+	}
+}
+
+// backoff doubles the retry delay with every attempt and adds some jitter.
+func backoff(attempt int) time.Duration {
+	delay := defaultRetryBase << (attempt - 1)
+	if delay <= 0 || delay > defaultRetryMaxDelay {
+		delay = defaultRetryMaxDelay
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// Task is the job handed to a Handler.
+type Task struct {
+	job.Entity
+
+	worker     string
+	repository job.Repository
+	result     *string
+}
+
+// Decode unmarshals the payload of the job into v.
+func (t *Task) Decode(v any) error {
+	if t.Payload == nil {
+		return nil
+	}
+	return json.Unmarshal([]byte(*t.Payload), v)
+}
+
+// Restore unmarshals the last saved checkpoint into v and reports whether there was one.
+func (t *Task) Restore(v any) (bool, error) {
+	if t.Checkpoint == nil {
+		return false, nil
+	}
+	return true, json.Unmarshal([]byte(*t.Checkpoint), v)
+}
+
+// Report saves the progress of the job and, when checkpoint is not nil, the
+// state it should resume from after a restart.
+func (t *Task) Report(ctx context.Context, progress, total int, checkpoint any) error {
+	var value *string
+	if checkpoint != nil {
+		data, err := json.Marshal(checkpoint)
+		if err != nil {
+			return err
+		}
+		encoded := string(data)
+		value = &encoded
+	}
+	return t.repository.Progress(context.WithoutCancel(ctx), t.ID, t.worker, progress, total, value)
+}
+
+// SaveFile stores content as the file of the job, such as the export it
+// wrote, for clients to download once it finishes.
+func (t *Task) SaveFile(ctx context.Context, content []byte) error {
+	return t.repository.SaveFile(context.WithoutCancel(ctx), t.ID, t.worker, content)
+}
+
+// SetResult stores v as the JSON result of the job once it finishes.
+func (t *Task) SetResult(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	encoded := string(data)
+	t.result = &encoded
+	return nil
+}
@@ -0,0 +1,396 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"product/internal/domain/job"
+	"product/pkg/store"
+)
+
+const testType = "test"
+
+// outcome is how a run ended, as recorded by Finish, Retry or Release.
+type outcome struct {
+	call   string
+	status string
+	result string
+	error  string
+	delay  time.Duration
+}
+
+// fakeRepository queues a single job, which it hands out once, and holds its
+// lock like the postgres store does: the updates of a running job only apply
+// for the worker that claimed it.
+type fakeRepository struct {
+	job.Repository
+
+	mu              sync.Mutex
+	data            job.Entity
+	claimed         bool
+	cancelRequested bool
+	lockLost        bool
+	progress        []int
+	file            []byte
+	outcomes        chan outcome
+}
+
+func newFakeRepository(attempts, maxAttempts int) *fakeRepository {
+	return &fakeRepository{
+		data: job.Entity{
+			ID:          "j1",
+			Type:        ptr(testType),
+			Status:      ptr(job.StatusQueued),
+			Payload:     ptr(`{"n":3}`),
+			Attempts:    ptr(attempts),
+			MaxAttempts: ptr(maxAttempts),
+		},
+		outcomes: make(chan outcome, 1),
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func (r *fakeRepository) Claim(ctx context.Context, worker string, types []string) (dest job.Entity, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.claimed || !slices.Contains(types, *r.data.Type) {
+		return dest, store.ErrorNotFound
+	}
+	r.claimed = true
+
+	r.data.Status = ptr(job.StatusRunning)
+	r.data.Attempts = ptr(*r.data.Attempts + 1)
+	r.data.LockedBy = &worker
+
+	return r.data, nil
+}
+
+// holds reports whether worker still holds the lock of the job. The caller
+// holds r.mu.
+func (r *fakeRepository) holds(id, worker string) bool {
+	return !r.lockLost && id == r.data.ID && *r.data.Status == job.StatusRunning &&
+		r.data.LockedBy != nil && *r.data.LockedBy == worker
+}
+
+func (r *fakeRepository) Heartbeat(ctx context.Context, id, worker string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.holds(id, worker) {
+		return false, job.ErrLockLost
+	}
+	return r.cancelRequested, nil
+}
+
+func (r *fakeRepository) Progress(ctx context.Context, id, worker string, progress, total int, checkpoint *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.holds(id, worker) {
+		return job.ErrLockLost
+	}
+	r.progress = append(r.progress, progress)
+	r.data.Checkpoint = checkpoint
+	return nil
+}
+
+func (r *fakeRepository) SaveFile(ctx context.Context, id, worker string, content []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.holds(id, worker) {
+		return job.ErrLockLost
+	}
+	r.file = content
+	return nil
+}
+
+func (r *fakeRepository) Finish(ctx context.Context, id, worker, status string, result, lastError *string) error {
+	return r.end(id, worker, outcome{call: "Finish", status: status, result: deref(result), error: deref(lastError)})
+}
+
+func (r *fakeRepository) Retry(ctx context.Context, id, worker string, delay time.Duration, lastError string) error {
+	return r.end(id, worker, outcome{call: "Retry", status: job.StatusQueued, error: lastError, delay: delay})
+}
+
+func (r *fakeRepository) Release(ctx context.Context, id, worker string) error {
+	r.mu.Lock()
+	if r.holds(id, worker) {
+		r.data.Attempts = ptr(*r.data.Attempts - 1)
+	}
+	r.mu.Unlock()
+
+	return r.end(id, worker, outcome{call: "Release", status: job.StatusQueued})
+}
+
+func (r *fakeRepository) end(id, worker string, o outcome) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.holds(id, worker) {
+		return job.ErrLockLost
+	}
+	r.data.Status, r.data.LockedBy = &o.status, nil
+	r.outcomes <- o
+	return nil
+}
+
+func (r *fakeRepository) RequeueStale(ctx context.Context, timeout time.Duration) (int64, error) {
+	return 0, nil
+}
+
+// takeOver hands the job to another worker, as RequeueStale and a Claim
+// elsewhere would.
+func (r *fakeRepository) takeOver() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data.LockedBy = ptr("other-worker")
+}
+
+func deref(p *string) string {
+	if p == nil {
+		return ""
+	}
+	return *p
+}
+
+// startPool runs handler for the job of repository with a fast heartbeat and
+// stops the pool when the test ends.
+func startPool(t *testing.T, repository *fakeRepository, handler Handler) *Pool {
+	t.Helper()
+
+	pool, err := New(repository, WithHandler(testType, handler), WithConcurrency(1), WithPollInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool.heartbeat = 5 * time.Millisecond
+
+	pool.Start()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := pool.Stop(ctx); err != nil {
+			t.Errorf("Stop() error = %v", err)
+		}
+	})
+
+	return pool
+}
+
+func waitOutcome(t *testing.T, repository *fakeRepository) outcome {
+	t.Helper()
+
+	select {
+	case o := <-repository.outcomes:
+		return o
+	case <-time.After(5 * time.Second):
+		t.Fatal("the job did not end")
+		return outcome{}
+	}
+}
+
+func TestPoolOutcomes(t *testing.T) {
+	failure := errors.New("upstream unavailable")
+
+	tests := []struct {
+		name        string
+		attempts    int
+		maxAttempts int
+		handler     Handler
+		want        outcome
+	}{
+		{
+			name:        "succeeded",
+			maxAttempts: 3,
+			handler: func(ctx context.Context, task *Task) error {
+				var payload struct{ N int }
+				if err := task.Decode(&payload); err != nil {
+					return err
+				}
+				if err := task.Report(ctx, payload.N, payload.N, map[string]int{"done": payload.N}); err != nil {
+					return err
+				}
+				return task.SetResult(map[string]int{"rows": payload.N})
+			},
+			want: outcome{call: "Finish", status: job.StatusSucceeded, result: `{"rows":3}`},
+		},
+		{
+			name:        "failed with attempts left",
+			maxAttempts: 3,
+			handler: func(ctx context.Context, task *Task) error {
+				return failure
+			},
+			want: outcome{call: "Retry", status: job.StatusQueued, error: failure.Error()},
+		},
+		{
+			name:        "failed on the last attempt",
+			attempts:    2,
+			maxAttempts: 3,
+			handler: func(ctx context.Context, task *Task) error {
+				return failure
+			},
+			want: outcome{call: "Finish", status: job.StatusFailed, error: failure.Error()},
+		},
+		{
+			name:        "failed permanently",
+			maxAttempts: 3,
+			handler: func(ctx context.Context, task *Task) error {
+				return Permanent(failure)
+			},
+			want: outcome{call: "Finish", status: job.StatusFailed, error: failure.Error()},
+		},
+		{
+			name:        "panicked",
+			maxAttempts: 3,
+			handler: func(ctx context.Context, task *Task) error {
+				panic("boom")
+			},
+			want: outcome{call: "Finish", status: job.StatusFailed, error: "worker: job panicked: boom"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repository := newFakeRepository(tt.attempts, tt.maxAttempts)
+			startPool(t, repository, tt.handler)
+
+			got := waitOutcome(t, repository)
+			if tt.want.call == "Retry" {
+				if maxDelay := defaultRetryBase << tt.attempts; got.delay < maxDelay/2 || got.delay > maxDelay {
+					t.Errorf("Retry() delay = %s, want between %s and %s", got.delay, maxDelay/2, maxDelay)
+				}
+				got.delay = 0
+			}
+			if got != tt.want {
+				t.Errorf("outcome = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoolCancel(t *testing.T) {
+	repository := newFakeRepository(0, 3)
+	startPool(t, repository, func(ctx context.Context, task *Task) error {
+		if err := task.Report(ctx, 1, 10, nil); err != nil {
+			return err
+		}
+		repository.mu.Lock()
+		repository.cancelRequested = true
+		repository.mu.Unlock()
+
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	want := outcome{call: "Finish", status: job.StatusCanceled, error: ErrCanceled.Error()}
+	if got := waitOutcome(t, repository); got != want {
+		t.Errorf("outcome = %+v, want %+v", got, want)
+	}
+}
+
+func TestPoolReleasesOnShutdown(t *testing.T) {
+	repository := newFakeRepository(0, 3)
+	started := make(chan struct{})
+	pool := startPool(t, repository, func(ctx context.Context, task *Task) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	<-started
+	if err := pool.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := outcome{call: "Release", status: job.StatusQueued}
+	if got := waitOutcome(t, repository); got != want {
+		t.Errorf("outcome = %+v, want %+v", got, want)
+	}
+	if attempts := *repository.data.Attempts; attempts != 0 {
+		t.Errorf("attempts = %d after release, want 0", attempts)
+	}
+}
+
+func TestPoolAbandonsJobTakenOver(t *testing.T) {
+	repository := newFakeRepository(0, 3)
+	causes := make(chan error, 1)
+	pool := startPool(t, repository, func(ctx context.Context, task *Task) error {
+		repository.takeOver()
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return errors.New("interrupted")
+	})
+
+	select {
+	case cause := <-causes:
+		if !errors.Is(cause, job.ErrLockLost) {
+			t.Errorf("job context cause = %v, want %v", cause, job.ErrLockLost)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the job was not stopped after losing its lock")
+	}
+
+	if err := pool.Stop(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case o := <-repository.outcomes:
+		t.Errorf("outcome %+v recorded for a job run by another worker", o)
+	default:
+	}
+	if owner := *repository.data.LockedBy; owner != "other-worker" {
+		t.Errorf("job locked by %q, want it left to other-worker", owner)
+	}
+}
+
+func TestLateUpdatesLoseTheLock(t *testing.T) {
+	repository := newFakeRepository(0, 3)
+	startPool(t, repository, func(ctx context.Context, task *Task) error {
+		repository.mu.Lock()
+		repository.lockLost = true
+		repository.mu.Unlock()
+
+		if err := task.SaveFile(ctx, []byte("late")); !errors.Is(err, job.ErrLockLost) {
+			t.Errorf("SaveFile() error = %v after the lock was lost, want %v", err, job.ErrLockLost)
+		}
+		return task.Report(ctx, 1, 1, nil)
+	})
+
+	// The run ends without an outcome: Report fails with ErrLockLost and the
+	// pool records nothing for a job it no longer holds.
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case o := <-repository.outcomes:
+		t.Errorf("outcome %+v recorded after the lock was lost", o)
+	default:
+	}
+	repository.mu.Lock()
+	defer repository.mu.Unlock()
+	if len(repository.progress) != 0 {
+		t.Errorf("progress %v recorded after the lock was lost", repository.progress)
+	}
+	if repository.file != nil {
+		t.Errorf("file %q saved after the lock was lost", repository.file)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt := 1; attempt <= 20; attempt++ {
+		maxDelay := defaultRetryMaxDelay
+		if attempt < 10 {
+			maxDelay = min(defaultRetryBase<<(attempt-1), defaultRetryMaxDelay)
+		}
+		if delay := backoff(attempt); delay < maxDelay/2 || delay > maxDelay {
+			t.Errorf("backoff(%d) = %s, want between %s and %s", attempt, delay, maxDelay/2, maxDelay)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package main
 
-import "product/internal/app"
+import (
+	"os"
+
+	"product/internal/app"
+)
 
 func main() {
-	app.Run()
+	os.Exit(app.Main(os.Args[1:]))
 }
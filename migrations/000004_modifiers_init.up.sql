@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS modifier_groups
+(
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id           VARCHAR PRIMARY KEY,
+    name         VARCHAR NOT NULL,
+    min_select   INT NOT NULL DEFAULT 0,
+    max_select   INT NOT NULL DEFAULT 1,
+    CHECK (min_select >= 0 AND max_select >= min_select)
+    );
+
+CREATE TABLE IF NOT EXISTS modifier_options
+(
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id           VARCHAR PRIMARY KEY,
+    group_id     VARCHAR NOT NULL,
+    name         VARCHAR NOT NULL,
+    price_delta  INT NOT NULL DEFAULT 0,
+    is_default   BOOLEAN NOT NULL DEFAULT FALSE,
+    position     INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (group_id) REFERENCES modifier_groups (id) ON DELETE CASCADE
+    );
+
+CREATE TABLE IF NOT EXISTS product_modifier_groups
+(
+    product_id   VARCHAR NOT NULL,
+    group_id     VARCHAR NOT NULL,
+    position     INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (product_id, group_id),
+    FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE,
+    FOREIGN KEY (group_id) REFERENCES modifier_groups (id) ON DELETE CASCADE
+    );
@@ -0,0 +1,26 @@
+ALTER TABLE products ADD COLUMN brand_name VARCHAR;
+
+UPDATE products p
+SET brand_name = COALESCE((SELECT b.name FROM brands b WHERE b.id = p.brand_id), '');
+
+ALTER TABLE products ALTER COLUMN brand_name SET NOT NULL;
+
+ALTER TABLE products ALTER COLUMN producer_country TYPE VARCHAR;
+
+UPDATE products p
+SET producer_country = u.value
+FROM unmatched_references u
+WHERE u.product_id = p.id AND u.field = 'producer_country';
+
+UPDATE products p
+SET brand_name = u.value
+FROM unmatched_references u
+WHERE u.product_id = p.id AND u.field = 'brand_name';
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS supplier_id,
+    DROP COLUMN IF EXISTS brand_id;
+
+DROP TABLE IF EXISTS unmatched_references;
+DROP TABLE IF EXISTS suppliers;
+DROP TABLE IF EXISTS brands;
@@ -0,0 +1,520 @@
+CREATE TABLE IF NOT EXISTS brands
+(
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id           VARCHAR PRIMARY KEY,
+    name         VARCHAR NOT NULL,
+    country      VARCHAR(2)
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS brands_name_key ON brands (LOWER(name));
+
+CREATE TABLE IF NOT EXISTS suppliers
+(
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id           VARCHAR PRIMARY KEY,
+    name         VARCHAR NOT NULL,
+    country      VARCHAR(2),
+    email        VARCHAR,
+    phone        VARCHAR
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS suppliers_name_key ON suppliers (LOWER(name));
+
+-- unmatched_references keeps the free-text values that could not be mapped
+-- to an entity so they can be fixed by hand.
+CREATE TABLE IF NOT EXISTS unmatched_references
+(
+    created_at   TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    product_id   VARCHAR NOT NULL,
+    field        VARCHAR NOT NULL,
+    value        VARCHAR NOT NULL,
+    PRIMARY KEY (product_id, field),
+    FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+    );
+
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS brand_id VARCHAR REFERENCES brands (id),
+    ADD COLUMN IF NOT EXISTS supplier_id VARCHAR REFERENCES suppliers (id);
+
+-- Brands: spellings that differ only by case or whitespace are one brand.
+-- Values naming no brand, such as "-" or "n/a", are left unmatched.
+CREATE TEMPORARY TABLE brand_spellings ON COMMIT DROP AS
+SELECT id AS product_id,
+       brand_name AS value,
+       REGEXP_REPLACE(TRIM(brand_name), '\s+', ' ', 'g') AS name
+FROM products
+WHERE TRIM(COALESCE(brand_name, '')) <> '';
+
+INSERT INTO brands (id, name)
+SELECT gen_random_uuid()::TEXT, MIN(name)
+FROM brand_spellings
+WHERE name ~ '[[:alnum:]]'
+  AND LOWER(name) NOT IN ('n/a', 'na', 'none', 'no brand', 'no name', 'unknown', 'null')
+GROUP BY LOWER(name)
+ON CONFLICT DO NOTHING;
+
+UPDATE products p
+SET brand_id = b.id
+FROM brand_spellings s
+JOIN brands b ON LOWER(b.name) = LOWER(s.name)
+WHERE p.id = s.product_id;
+
+INSERT INTO unmatched_references (product_id, field, value)
+SELECT s.product_id, 'brand_name', s.value
+FROM brand_spellings s
+JOIN products p ON p.id = s.product_id
+WHERE p.brand_id IS NULL;
+
+-- Countries: match ISO 3166-1 codes and English names.
+CREATE TEMPORARY TABLE iso_countries
+(
+    alpha2 VARCHAR(2) NOT NULL,
+    alpha3 VARCHAR(3) NOT NULL,
+    name   VARCHAR NOT NULL
+) ON COMMIT DROP;
+
+INSERT INTO iso_countries (alpha2, alpha3, name) VALUES
+    ('AD', 'AND', 'Andorra'),
+    ('AD', 'AND', 'Principality of Andorra'),
+    ('AE', 'ARE', 'United Arab Emirates'),
+    ('AF', 'AFG', 'Afghanistan'),
+    ('AF', 'AFG', 'Islamic Republic of Afghanistan'),
+    ('AG', 'ATG', 'Antigua and Barbuda'),
+    ('AI', 'AIA', 'Anguilla'),
+    ('AL', 'ALB', 'Albania'),
+    ('AL', 'ALB', 'Republic of Albania'),
+    ('AM', 'ARM', 'Armenia'),
+    ('AM', 'ARM', 'Republic of Armenia'),
+    ('AO', 'AGO', 'Angola'),
+    ('AO', 'AGO', 'Republic of Angola'),
+    ('AQ', 'ATA', 'Antarctica'),
+    ('AR', 'ARG', 'Argentina'),
+    ('AR', 'ARG', 'Argentine Republic'),
+    ('AS', 'ASM', 'American Samoa'),
+    ('AT', 'AUT', 'Austria'),
+    ('AT', 'AUT', 'Republic of Austria'),
+    ('AU', 'AUS', 'Australia'),
+    ('AW', 'ABW', 'Aruba'),
+    ('AX', 'ALA', 'Åland Islands'),
+    ('AZ', 'AZE', 'Azerbaijan'),
+    ('AZ', 'AZE', 'Republic of Azerbaijan'),
+    ('BA', 'BIH', 'Bosnia and Herzegovina'),
+    ('BA', 'BIH', 'Republic of Bosnia and Herzegovina'),
+    ('BB', 'BRB', 'Barbados'),
+    ('BD', 'BGD', 'Bangladesh'),
+    ('BD', 'BGD', 'People''s Republic of Bangladesh'),
+    ('BE', 'BEL', 'Belgium'),
+    ('BE', 'BEL', 'Kingdom of Belgium'),
+    ('BF', 'BFA', 'Burkina Faso'),
+    ('BG', 'BGR', 'Bulgaria'),
+    ('BG', 'BGR', 'Republic of Bulgaria'),
+    ('BH', 'BHR', 'Bahrain'),
+    ('BH', 'BHR', 'Kingdom of Bahrain'),
+    ('BI', 'BDI', 'Burundi'),
+    ('BI', 'BDI', 'Republic of Burundi'),
+    ('BJ', 'BEN', 'Benin'),
+    ('BJ', 'BEN', 'Republic of Benin'),
+    ('BL', 'BLM', 'Saint Barthélemy'),
+    ('BM', 'BMU', 'Bermuda'),
+    ('BN', 'BRN', 'Brunei Darussalam'),
+    ('BO', 'BOL', 'Bolivia, Plurinational State of'),
+    ('BO', 'BOL', 'Bolivia'),
+    ('BO', 'BOL', 'Plurinational State of Bolivia'),
+    ('BQ', 'BES', 'Bonaire, Sint Eustatius and Saba'),
+    ('BR', 'BRA', 'Brazil'),
+    ('BR', 'BRA', 'Federative Republic of Brazil'),
+    ('BS', 'BHS', 'Bahamas'),
+    ('BS', 'BHS', 'Commonwealth of the Bahamas'),
+    ('BT', 'BTN', 'Bhutan'),
+    ('BT', 'BTN', 'Kingdom of Bhutan'),
+    ('BV', 'BVT', 'Bouvet Island'),
+    ('BW', 'BWA', 'Botswana'),
+    ('BW', 'BWA', 'Republic of Botswana'),
+    ('BY', 'BLR', 'Belarus'),
+    ('BY', 'BLR', 'Republic of Belarus'),
+    ('BZ', 'BLZ', 'Belize'),
+    ('CA', 'CAN', 'Canada'),
+    ('CC', 'CCK', 'Cocos (Keeling) Islands'),
+    ('CD', 'COD', 'Congo, The Democratic Republic of the'),
+    ('CF', 'CAF', 'Central African Republic'),
+    ('CG', 'COG', 'Congo'),
+    ('CG', 'COG', 'Republic of the Congo'),
+    ('CH', 'CHE', 'Switzerland'),
+    ('CH', 'CHE', 'Swiss Confederation'),
+    ('CI', 'CIV', 'Côte d''Ivoire'),
+    ('CI', 'CIV', 'Republic of Côte d''Ivoire'),
+    ('CK', 'COK', 'Cook Islands'),
+    ('CL', 'CHL', 'Chile'),
+    ('CL', 'CHL', 'Republic of Chile'),
+    ('CM', 'CMR', 'Cameroon'),
+    ('CM', 'CMR', 'Republic of Cameroon'),
+    ('CN', 'CHN', 'China'),
+    ('CN', 'CHN', 'People''s Republic of China'),
+    ('CO', 'COL', 'Colombia'),
+    ('CO', 'COL', 'Republic of Colombia'),
+    ('CR', 'CRI', 'Costa Rica'),
+    ('CR', 'CRI', 'Republic of Costa Rica'),
+    ('CU', 'CUB', 'Cuba'),
+    ('CU', 'CUB', 'Republic of Cuba'),
+    ('CV', 'CPV', 'Cabo Verde'),
+    ('CV', 'CPV', 'Republic of Cabo Verde'),
+    ('CW', 'CUW', 'Curaçao'),
+    ('CX', 'CXR', 'Christmas Island'),
+    ('CY', 'CYP', 'Cyprus'),
+    ('CY', 'CYP', 'Republic of Cyprus'),
+    ('CZ', 'CZE', 'Czechia'),
+    ('CZ', 'CZE', 'Czech Republic'),
+    ('DE', 'DEU', 'Germany'),
+    ('DE', 'DEU', 'Federal Republic of Germany'),
+    ('DJ', 'DJI', 'Djibouti'),
+    ('DJ', 'DJI', 'Republic of Djibouti'),
+    ('DK', 'DNK', 'Denmark'),
+    ('DK', 'DNK', 'Kingdom of Denmark'),
+    ('DM', 'DMA', 'Dominica'),
+    ('DM', 'DMA', 'Commonwealth of Dominica'),
+    ('DO', 'DOM', 'Dominican Republic'),
+    ('DZ', 'DZA', 'Algeria'),
+    ('DZ', 'DZA', 'People''s Democratic Republic of Algeria'),
+    ('EC', 'ECU', 'Ecuador'),
+    ('EC', 'ECU', 'Republic of Ecuador'),
+    ('EE', 'EST', 'Estonia'),
+    ('EE', 'EST', 'Republic of Estonia'),
+    ('EG', 'EGY', 'Egypt'),
+    ('EG', 'EGY', 'Arab Republic of Egypt'),
+    ('EH', 'ESH', 'Western Sahara'),
+    ('ER', 'ERI', 'Eritrea'),
+    ('ER', 'ERI', 'the State of Eritrea'),
+    ('ES', 'ESP', 'Spain'),
+    ('ES', 'ESP', 'Kingdom of Spain'),
+    ('ET', 'ETH', 'Ethiopia'),
+    ('ET', 'ETH', 'Federal Democratic Republic of Ethiopia'),
+    ('FI', 'FIN', 'Finland'),
+    ('FI', 'FIN', 'Republic of Finland'),
+    ('FJ', 'FJI', 'Fiji'),
+    ('FJ', 'FJI', 'Republic of Fiji'),
+    ('FK', 'FLK', 'Falkland Islands (Malvinas)'),
+    ('FM', 'FSM', 'Micronesia, Federated States of'),
+    ('FM', 'FSM', 'Federated States of Micronesia'),
+    ('FO', 'FRO', 'Faroe Islands'),
+    ('FR', 'FRA', 'France'),
+    ('FR', 'FRA', 'French Republic'),
+    ('GA', 'GAB', 'Gabon'),
+    ('GA', 'GAB', 'Gabonese Republic'),
+    ('GB', 'GBR', 'United Kingdom'),
+    ('GB', 'GBR', 'United Kingdom of Great Britain and Northern Ireland'),
+    ('GD', 'GRD', 'Grenada'),
+    ('GE', 'GEO', 'Georgia'),
+    ('GF', 'GUF', 'French Guiana'),
+    ('GG', 'GGY', 'Guernsey'),
+    ('GH', 'GHA', 'Ghana'),
+    ('GH', 'GHA', 'Republic of Ghana'),
+    ('GI', 'GIB', 'Gibraltar'),
+    ('GL', 'GRL', 'Greenland'),
+    ('GM', 'GMB', 'Gambia'),
+    ('GM', 'GMB', 'Republic of the Gambia'),
+    ('GN', 'GIN', 'Guinea'),
+    ('GN', 'GIN', 'Republic of Guinea'),
+    ('GP', 'GLP', 'Guadeloupe'),
+    ('GQ', 'GNQ', 'Equatorial Guinea'),
+    ('GQ', 'GNQ', 'Republic of Equatorial Guinea'),
+    ('GR', 'GRC', 'Greece'),
+    ('GR', 'GRC', 'Hellenic Republic'),
+    ('GS', 'SGS', 'South Georgia and the South Sandwich Islands'),
+    ('GT', 'GTM', 'Guatemala'),
+    ('GT', 'GTM', 'Republic of Guatemala'),
+    ('GU', 'GUM', 'Guam'),
+    ('GW', 'GNB', 'Guinea-Bissau'),
+    ('GW', 'GNB', 'Republic of Guinea-Bissau'),
+    ('GY', 'GUY', 'Guyana'),
+    ('GY', 'GUY', 'Republic of Guyana'),
+    ('HK', 'HKG', 'Hong Kong'),
+    ('HK', 'HKG', 'Hong Kong Special Administrative Region of China'),
+    ('HM', 'HMD', 'Heard Island and McDonald Islands'),
+    ('HN', 'HND', 'Honduras'),
+    ('HN', 'HND', 'Republic of Honduras'),
+    ('HR', 'HRV', 'Croatia'),
+    ('HR', 'HRV', 'Republic of Croatia'),
+    ('HT', 'HTI', 'Haiti'),
+    ('HT', 'HTI', 'Republic of Haiti'),
+    ('HU', 'HUN', 'Hungary'),
+    ('ID', 'IDN', 'Indonesia'),
+    ('ID', 'IDN', 'Republic of Indonesia'),
+    ('IE', 'IRL', 'Ireland'),
+    ('IL', 'ISR', 'Israel'),
+    ('IL', 'ISR', 'State of Israel'),
+    ('IM', 'IMN', 'Isle of Man'),
+    ('IN', 'IND', 'India'),
+    ('IN', 'IND', 'Republic of India'),
+    ('IO', 'IOT', 'British Indian Ocean Territory'),
+    ('IQ', 'IRQ', 'Iraq'),
+    ('IQ', 'IRQ', 'Republic of Iraq'),
+    ('IR', 'IRN', 'Iran, Islamic Republic of'),
+    ('IR', 'IRN', 'Iran'),
+    ('IR', 'IRN', 'Islamic Republic of Iran'),
+    ('IS', 'ISL', 'Iceland'),
+    ('IS', 'ISL', 'Republic of Iceland'),
+    ('IT', 'ITA', 'Italy'),
+    ('IT', 'ITA', 'Italian Republic'),
+    ('JE', 'JEY', 'Jersey'),
+    ('JM', 'JAM', 'Jamaica'),
+    ('JO', 'JOR', 'Jordan'),
+    ('JO', 'JOR', 'Hashemite Kingdom of Jordan'),
+    ('JP', 'JPN', 'Japan'),
+    ('KE', 'KEN', 'Kenya'),
+    ('KE', 'KEN', 'Republic of Kenya'),
+    ('KG', 'KGZ', 'Kyrgyzstan'),
+    ('KG', 'KGZ', 'Kyrgyz Republic'),
+    ('KH', 'KHM', 'Cambodia'),
+    ('KH', 'KHM', 'Kingdom of Cambodia'),
+    ('KI', 'KIR', 'Kiribati'),
+    ('KI', 'KIR', 'Republic of Kiribati'),
+    ('KM', 'COM', 'Comoros'),
+    ('KM', 'COM', 'Union of the Comoros'),
+    ('KN', 'KNA', 'Saint Kitts and Nevis'),
+    ('KP', 'PRK', 'Korea, Democratic People''s Republic of'),
+    ('KP', 'PRK', 'North Korea'),
+    ('KP', 'PRK', 'Democratic People''s Republic of Korea'),
+    ('KR', 'KOR', 'Korea, Republic of'),
+    ('KR', 'KOR', 'South Korea'),
+    ('KW', 'KWT', 'Kuwait'),
+    ('KW', 'KWT', 'State of Kuwait'),
+    ('KY', 'CYM', 'Cayman Islands'),
+    ('KZ', 'KAZ', 'Kazakhstan'),
+    ('KZ', 'KAZ', 'Republic of Kazakhstan'),
+    ('LA', 'LAO', 'Lao People''s Democratic Republic'),
+    ('LA', 'LAO', 'Laos'),
+    ('LB', 'LBN', 'Lebanon'),
+    ('LB', 'LBN', 'Lebanese Republic'),
+    ('LC', 'LCA', 'Saint Lucia'),
+    ('LI', 'LIE', 'Liechtenstein'),
+    ('LI', 'LIE', 'Principality of Liechtenstein'),
+    ('LK', 'LKA', 'Sri Lanka'),
+    ('LK', 'LKA', 'Democratic Socialist Republic of Sri Lanka'),
+    ('LR', 'LBR', 'Liberia'),
+    ('LR', 'LBR', 'Republic of Liberia'),
+    ('LS', 'LSO', 'Lesotho'),
+    ('LS', 'LSO', 'Kingdom of Lesotho'),
+    ('LT', 'LTU', 'Lithuania'),
+    ('LT', 'LTU', 'Republic of Lithuania'),
+    ('LU', 'LUX', 'Luxembourg'),
+    ('LU', 'LUX', 'Grand Duchy of Luxembourg'),
+    ('LV', 'LVA', 'Latvia'),
+    ('LV', 'LVA', 'Republic of Latvia'),
+    ('LY', 'LBY', 'Libya'),
+    ('MA', 'MAR', 'Morocco'),
+    ('MA', 'MAR', 'Kingdom of Morocco'),
+    ('MC', 'MCO', 'Monaco'),
+    ('MC', 'MCO', 'Principality of Monaco'),
+    ('MD', 'MDA', 'Moldova, Republic of'),
+    ('MD', 'MDA', 'Moldova'),
+    ('MD', 'MDA', 'Republic of Moldova'),
+    ('ME', 'MNE', 'Montenegro'),
+    ('MF', 'MAF', 'Saint Martin (French part)'),
+    ('MG', 'MDG', 'Madagascar'),
+    ('MG', 'MDG', 'Republic of Madagascar'),
+    ('MH', 'MHL', 'Marshall Islands'),
+    ('MH', 'MHL', 'Republic of the Marshall Islands'),
+    ('MK', 'MKD', 'North Macedonia'),
+    ('MK', 'MKD', 'Republic of North Macedonia'),
+    ('ML', 'MLI', 'Mali'),
+    ('ML', 'MLI', 'Republic of Mali'),
+    ('MM', 'MMR', 'Myanmar'),
+    ('MM', 'MMR', 'Republic of Myanmar'),
+    ('MN', 'MNG', 'Mongolia'),
+    ('MO', 'MAC', 'Macao'),
+    ('MO', 'MAC', 'Macao Special Administrative Region of China'),
+    ('MP', 'MNP', 'Northern Mariana Islands'),
+    ('MP', 'MNP', 'Commonwealth of the Northern Mariana Islands'),
+    ('MQ', 'MTQ', 'Martinique'),
+    ('MR', 'MRT', 'Mauritania'),
+    ('MR', 'MRT', 'Islamic Republic of Mauritania'),
+    ('MS', 'MSR', 'Montserrat'),
+    ('MT', 'MLT', 'Malta'),
+    ('MT', 'MLT', 'Republic of Malta'),
+    ('MU', 'MUS', 'Mauritius'),
+    ('MU', 'MUS', 'Republic of Mauritius'),
+    ('MV', 'MDV', 'Maldives'),
+    ('MV', 'MDV', 'Republic of Maldives'),
+    ('MW', 'MWI', 'Malawi'),
+    ('MW', 'MWI', 'Republic of Malawi'),
+    ('MX', 'MEX', 'Mexico'),
+    ('MX', 'MEX', 'United Mexican States'),
+    ('MY', 'MYS', 'Malaysia'),
+    ('MZ', 'MOZ', 'Mozambique'),
+    ('MZ', 'MOZ', 'Republic of Mozambique'),
+    ('NA', 'NAM', 'Namibia'),
+    ('NA', 'NAM', 'Republic of Namibia'),
+    ('NC', 'NCL', 'New Caledonia'),
+    ('NE', 'NER', 'Niger'),
+    ('NE', 'NER', 'Republic of the Niger'),
+    ('NF', 'NFK', 'Norfolk Island'),
+    ('NG', 'NGA', 'Nigeria'),
+    ('NG', 'NGA', 'Federal Republic of Nigeria'),
+    ('NI', 'NIC', 'Nicaragua'),
+    ('NI', 'NIC', 'Republic of Nicaragua'),
+    ('NL', 'NLD', 'Netherlands'),
+    ('NL', 'NLD', 'Kingdom of the Netherlands'),
+    ('NO', 'NOR', 'Norway'),
+    ('NO', 'NOR', 'Kingdom of Norway'),
+    ('NP', 'NPL', 'Nepal'),
+    ('NP', 'NPL', 'Federal Democratic Republic of Nepal'),
+    ('NR', 'NRU', 'Nauru'),
+    ('NR', 'NRU', 'Republic of Nauru'),
+    ('NU', 'NIU', 'Niue'),
+    ('NZ', 'NZL', 'New Zealand'),
+    ('OM', 'OMN', 'Oman'),
+    ('OM', 'OMN', 'Sultanate of Oman'),
+    ('PA', 'PAN', 'Panama'),
+    ('PA', 'PAN', 'Republic of Panama'),
+    ('PE', 'PER', 'Peru'),
+    ('PE', 'PER', 'Republic of Peru'),
+    ('PF', 'PYF', 'French Polynesia'),
+    ('PG', 'PNG', 'Papua New Guinea'),
+    ('PG', 'PNG', 'Independent State of Papua New Guinea'),
+    ('PH', 'PHL', 'Philippines'),
+    ('PH', 'PHL', 'Republic of the Philippines'),
+    ('PK', 'PAK', 'Pakistan'),
+    ('PK', 'PAK', 'Islamic Republic of Pakistan'),
+    ('PL', 'POL', 'Poland'),
+    ('PL', 'POL', 'Republic of Poland'),
+    ('PM', 'SPM', 'Saint Pierre and Miquelon'),
+    ('PN', 'PCN', 'Pitcairn'),
+    ('PR', 'PRI', 'Puerto Rico'),
+    ('PS', 'PSE', 'Palestine, State of'),
+    ('PS', 'PSE', 'the State of Palestine'),
+    ('PT', 'PRT', 'Portugal'),
+    ('PT', 'PRT', 'Portuguese Republic'),
+    ('PW', 'PLW', 'Palau'),
+    ('PW', 'PLW', 'Republic of Palau'),
+    ('PY', 'PRY', 'Paraguay'),
+    ('PY', 'PRY', 'Republic of Paraguay'),
+    ('QA', 'QAT', 'Qatar'),
+    ('QA', 'QAT', 'State of Qatar'),
+    ('RE', 'REU', 'Réunion'),
+    ('RO', 'ROU', 'Romania'),
+    ('RS', 'SRB', 'Serbia'),
+    ('RS', 'SRB', 'Republic of Serbia'),
+    ('RU', 'RUS', 'Russian Federation'),
+    ('RW', 'RWA', 'Rwanda'),
+    ('RW', 'RWA', 'Rwandese Republic'),
+    ('SA', 'SAU', 'Saudi Arabia'),
+    ('SA', 'SAU', 'Kingdom of Saudi Arabia'),
+    ('SB', 'SLB', 'Solomon Islands'),
+    ('SC', 'SYC', 'Seychelles'),
+    ('SC', 'SYC', 'Republic of Seychelles'),
+    ('SD', 'SDN', 'Sudan'),
+    ('SD', 'SDN', 'Republic of the Sudan'),
+    ('SE', 'SWE', 'Sweden'),
+    ('SE', 'SWE', 'Kingdom of Sweden'),
+    ('SG', 'SGP', 'Singapore'),
+    ('SG', 'SGP', 'Republic of Singapore'),
+    ('SH', 'SHN', 'Saint Helena, Ascension and Tristan da Cunha'),
+    ('SI', 'SVN', 'Slovenia'),
+    ('SI', 'SVN', 'Republic of Slovenia'),
+    ('SJ', 'SJM', 'Svalbard and Jan Mayen'),
+    ('SK', 'SVK', 'Slovakia'),
+    ('SK', 'SVK', 'Slovak Republic'),
+    ('SL', 'SLE', 'Sierra Leone'),
+    ('SL', 'SLE', 'Republic of Sierra Leone'),
+    ('SM', 'SMR', 'San Marino'),
+    ('SM', 'SMR', 'Republic of San Marino'),
+    ('SN', 'SEN', 'Senegal'),
+    ('SN', 'SEN', 'Republic of Senegal'),
+    ('SO', 'SOM', 'Somalia'),
+    ('SO', 'SOM', 'Federal Republic of Somalia'),
+    ('SR', 'SUR', 'Suriname'),
+    ('SR', 'SUR', 'Republic of Suriname'),
+    ('SS', 'SSD', 'South Sudan'),
+    ('SS', 'SSD', 'Republic of South Sudan'),
+    ('ST', 'STP', 'Sao Tome and Principe'),
+    ('ST', 'STP', 'Democratic Republic of Sao Tome and Principe'),
+    ('SV', 'SLV', 'El Salvador'),
+    ('SV', 'SLV', 'Republic of El Salvador'),
+    ('SX', 'SXM', 'Sint Maarten (Dutch part)'),
+    ('SY', 'SYR', 'Syrian Arab Republic'),
+    ('SY', 'SYR', 'Syria'),
+    ('SZ', 'SWZ', 'Eswatini'),
+    ('SZ', 'SWZ', 'Kingdom of Eswatini'),
+    ('TC', 'TCA', 'Turks and Caicos Islands'),
+    ('TD', 'TCD', 'Chad'),
+    ('TD', 'TCD', 'Republic of Chad'),
+    ('TF', 'ATF', 'French Southern Territories'),
+    ('TG', 'TGO', 'Togo'),
+    ('TG', 'TGO', 'Togolese Republic'),
+    ('TH', 'THA', 'Thailand'),
+    ('TH', 'THA', 'Kingdom of Thailand'),
+    ('TJ', 'TJK', 'Tajikistan'),
+    ('TJ', 'TJK', 'Republic of Tajikistan'),
+    ('TK', 'TKL', 'Tokelau'),
+    ('TL', 'TLS', 'Timor-Leste'),
+    ('TL', 'TLS', 'Democratic Republic of Timor-Leste'),
+    ('TM', 'TKM', 'Turkmenistan'),
+    ('TN', 'TUN', 'Tunisia'),
+    ('TN', 'TUN', 'Republic of Tunisia'),
+    ('TO', 'TON', 'Tonga'),
+    ('TO', 'TON', 'Kingdom of Tonga'),
+    ('TR', 'TUR', 'Türkiye'),
+    ('TR', 'TUR', 'Republic of Türkiye'),
+    ('TT', 'TTO', 'Trinidad and Tobago'),
+    ('TT', 'TTO', 'Republic of Trinidad and Tobago'),
+    ('TV', 'TUV', 'Tuvalu'),
+    ('TW', 'TWN', 'Taiwan, Province of China'),
+    ('TW', 'TWN', 'Taiwan'),
+    ('TZ', 'TZA', 'Tanzania, United Republic of'),
+    ('TZ', 'TZA', 'Tanzania'),
+    ('TZ', 'TZA', 'United Republic of Tanzania'),
+    ('UA', 'UKR', 'Ukraine'),
+    ('UG', 'UGA', 'Uganda'),
+    ('UG', 'UGA', 'Republic of Uganda'),
+    ('UM', 'UMI', 'United States Minor Outlying Islands'),
+    ('US', 'USA', 'United States'),
+    ('US', 'USA', 'United States of America'),
+    ('UY', 'URY', 'Uruguay'),
+    ('UY', 'URY', 'Eastern Republic of Uruguay'),
+    ('UZ', 'UZB', 'Uzbekistan'),
+    ('UZ', 'UZB', 'Republic of Uzbekistan'),
+    ('VA', 'VAT', 'Holy See (Vatican City State)'),
+    ('VC', 'VCT', 'Saint Vincent and the Grenadines'),
+    ('VE', 'VEN', 'Venezuela, Bolivarian Republic of'),
+    ('VE', 'VEN', 'Venezuela'),
+    ('VE', 'VEN', 'Bolivarian Republic of Venezuela'),
+    ('VG', 'VGB', 'Virgin Islands, British'),
+    ('VG', 'VGB', 'British Virgin Islands'),
+    ('VI', 'VIR', 'Virgin Islands, U.S.'),
+    ('VI', 'VIR', 'Virgin Islands of the United States'),
+    ('VN', 'VNM', 'Viet Nam'),
+    ('VN', 'VNM', 'Vietnam'),
+    ('VN', 'VNM', 'Socialist Republic of Viet Nam'),
+    ('VU', 'VUT', 'Vanuatu'),
+    ('VU', 'VUT', 'Republic of Vanuatu'),
+    ('WF', 'WLF', 'Wallis and Futuna'),
+    ('WS', 'WSM', 'Samoa'),
+    ('WS', 'WSM', 'Independent State of Samoa'),
+    ('YE', 'YEM', 'Yemen'),
+    ('YE', 'YEM', 'Republic of Yemen'),
+    ('YT', 'MYT', 'Mayotte'),
+    ('ZA', 'ZAF', 'South Africa'),
+    ('ZA', 'ZAF', 'Republic of South Africa'),
+    ('ZM', 'ZMB', 'Zambia'),
+    ('ZM', 'ZMB', 'Republic of Zambia'),
+    ('ZW', 'ZWE', 'Zimbabwe'),
+    ('ZW', 'ZWE', 'Republic of Zimbabwe');
+
+ALTER TABLE products ADD COLUMN country_code VARCHAR(2);
+
+UPDATE products p
+SET country_code = c.alpha2
+FROM iso_countries c
+WHERE UPPER(TRIM(p.producer_country)) IN (c.alpha2, c.alpha3)
+   OR LOWER(TRIM(p.producer_country)) = LOWER(c.name);
+
+INSERT INTO unmatched_references (product_id, field, value)
+SELECT id, 'producer_country', producer_country
+FROM products
+WHERE country_code IS NULL AND TRIM(COALESCE(producer_country, '')) <> '';
+
+ALTER TABLE products DROP COLUMN producer_country;
+ALTER TABLE products RENAME COLUMN country_code TO producer_country;
+ALTER TABLE products DROP COLUMN brand_name;
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS imports
+(
+    created_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at      TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id              VARCHAR PRIMARY KEY,
+    status          VARCHAR NOT NULL,
+    filename        VARCHAR NOT NULL,
+    format          VARCHAR NOT NULL,
+    mapping         JSONB NOT NULL DEFAULT '{}',
+    dry_run         BOOLEAN NOT NULL DEFAULT FALSE,
+    batch_size      INT NOT NULL,
+    total_rows      INT NOT NULL DEFAULT 0,
+    processed_rows  INT NOT NULL DEFAULT 0,
+    upserted_rows   INT NOT NULL DEFAULT 0,
+    failed_rows     INT NOT NULL DEFAULT 0,
+    error           TEXT,
+    file            BYTEA NOT NULL
+    );
+
+CREATE TABLE IF NOT EXISTS import_errors
+(
+    import_id   VARCHAR NOT NULL,
+    row         INT NOT NULL,
+    field       VARCHAR NOT NULL,
+    message     TEXT NOT NULL,
+    PRIMARY KEY (import_id, row, field),
+    FOREIGN KEY (import_id) REFERENCES imports (id) ON DELETE CASCADE
+    );
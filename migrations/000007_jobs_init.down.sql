@@ -0,0 +1,3 @@
+ALTER TABLE imports DROP COLUMN IF EXISTS job_id;
+
+DROP TABLE IF EXISTS jobs;
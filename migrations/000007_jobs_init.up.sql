@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS jobs
+(
+    created_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at        TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    id                VARCHAR PRIMARY KEY,
+    type              VARCHAR NOT NULL,
+    status            VARCHAR NOT NULL,
+    payload           JSONB NOT NULL DEFAULT '{}',
+    checkpoint        JSONB,
+    result            JSONB,
+    progress          INT NOT NULL DEFAULT 0,
+    total             INT NOT NULL DEFAULT 0,
+    attempts          INT NOT NULL DEFAULT 0,
+    max_attempts      INT NOT NULL DEFAULT 5,
+    run_at            TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error        TEXT,
+    cancel_requested  BOOLEAN NOT NULL DEFAULT FALSE,
+    locked_by         VARCHAR,
+    locked_at         TIMESTAMP,
+    started_at        TIMESTAMP,
+    finished_at       TIMESTAMP
+    );
+
+CREATE INDEX IF NOT EXISTS jobs_queued_idx ON jobs (run_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS jobs_running_idx ON jobs (locked_at) WHERE status = 'running';
+
+ALTER TABLE imports ADD COLUMN IF NOT EXISTS job_id VARCHAR REFERENCES jobs (id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS request_id;
+ALTER TABLE jobs DROP COLUMN IF EXISTS actor;
+
+DROP TABLE IF EXISTS audit_log;
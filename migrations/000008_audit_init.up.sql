@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS audit_log
+(
+    id           BIGSERIAL PRIMARY KEY,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor        VARCHAR NOT NULL,
+    request_id   VARCHAR,
+    entity_type  VARCHAR NOT NULL,
+    entity_id    VARCHAR NOT NULL,
+    operation    VARCHAR NOT NULL,
+    changes      JSONB NOT NULL DEFAULT '{}'
+    );
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_idx ON audit_log (entity_type, entity_id, id);
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor, created_at);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx ON audit_log (created_at);
+
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS actor VARCHAR;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS request_id VARCHAR;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS revisions
+(
+    entity_type  VARCHAR NOT NULL,
+    entity_id    VARCHAR NOT NULL,
+    revision     INT NOT NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    actor        VARCHAR NOT NULL,
+    request_id   VARCHAR,
+    operation    VARCHAR NOT NULL,
+    snapshot     JSONB,
+    PRIMARY KEY (entity_type, entity_id, revision)
+    );
+
+-- The current state of the catalog becomes the first revision of every entity.
+INSERT INTO revisions (entity_type, entity_id, revision, actor, operation, snapshot)
+SELECT 'product', p.id, 1, 'system', 'create', jsonb_strip_nulls(jsonb_build_object(
+    'id', p.id,
+    'category_id', p.category_id,
+    'barcode', p.barcode,
+    'name', p.name,
+    'measure', COALESCE(p.measure, ''),
+    'cost', COALESCE(p.cost, 0),
+    'producer_country', COALESCE(p.producer_country, ''),
+    'brand_id', COALESCE(p.brand_id, ''),
+    'brand_name', b.name,
+    'supplier_id', COALESCE(p.supplier_id, ''),
+    'description', p.description,
+    'image', p.image,
+    'is_weighted', p.is_weighted))
+FROM products p
+LEFT JOIN brands b ON b.id = p.brand_id
+ON CONFLICT DO NOTHING;
+
+INSERT INTO revisions (entity_type, entity_id, revision, actor, operation, snapshot)
+SELECT 'category', c.id, 1, 'system', 'create', jsonb_build_object(
+    'id', c.id,
+    'name', c.name,
+    'parent_id', c.parent_id,
+    'childs', NULL)
+FROM categories c
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox
+(
+    id              BIGSERIAL PRIMARY KEY,
+    created_at      TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    event_type      VARCHAR NOT NULL,
+    aggregate_type  VARCHAR NOT NULL,
+    aggregate_id    VARCHAR NOT NULL,
+    payload         JSONB NOT NULL,
+    published_at    TIMESTAMP,
+    attempts        INT NOT NULL DEFAULT 0,
+    last_error      TEXT
+    );
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (id) WHERE published_at IS NULL;
+CREATE INDEX IF NOT EXISTS outbox_aggregate_idx ON outbox (aggregate_type, aggregate_id, id);
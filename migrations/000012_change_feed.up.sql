@@ -0,0 +1,23 @@
+-- The change feed orders events by the transaction that wrote them. Events of
+-- transactions still running are held back, so a sync token never skips an
+-- event committed after it was handed out.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS txid BIGINT NOT NULL DEFAULT txid_current();
+
+CREATE INDEX IF NOT EXISTS outbox_feed_idx ON outbox (txid, id);
+
+-- Entities left untouched since the outbox was introduced get a creation
+-- event, already published, so that a kiosk syncing from scratch sees them.
+INSERT INTO outbox (event_type, aggregate_type, aggregate_id, payload, published_at)
+SELECT r.entity_type || '.created', r.entity_type, r.entity_id,
+       jsonb_build_object('actor', 'system', 'data', r.snapshot), CURRENT_TIMESTAMP
+FROM (
+    SELECT DISTINCT ON (entity_type, entity_id) entity_type, entity_id, snapshot
+    FROM revisions
+    WHERE entity_type IN ('product', 'category')
+    ORDER BY entity_type, entity_id, revision DESC
+    ) r
+WHERE r.snapshot IS NOT NULL
+  AND NOT EXISTS (
+    SELECT 1
+    FROM outbox o
+    WHERE o.aggregate_type = r.entity_type AND o.aggregate_id = r.entity_id);
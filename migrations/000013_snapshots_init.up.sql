@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS snapshots
+(
+    version          BIGSERIAL PRIMARY KEY,
+    created_at       TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    hash             VARCHAR NOT NULL,
+    size             BIGINT NOT NULL,
+    compressed_size  BIGINT NOT NULL,
+    sync_token       VARCHAR NOT NULL,
+    categories       INT NOT NULL,
+    products         INT NOT NULL
+    );
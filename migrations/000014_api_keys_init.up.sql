@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS api_keys
+(
+    id           VARCHAR PRIMARY KEY,
+    created_at   TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name         VARCHAR NOT NULL,
+    prefix       VARCHAR NOT NULL UNIQUE,
+    hash         VARCHAR NOT NULL,
+    scopes       TEXT[] NOT NULL DEFAULT '{}',
+    store_id     VARCHAR,
+    expires_at   TIMESTAMP,
+    last_used_at TIMESTAMP,
+    revoked_at   TIMESTAMP,
+    rotated_from VARCHAR REFERENCES api_keys (id) ON DELETE SET NULL
+    );
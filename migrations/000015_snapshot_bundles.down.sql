@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS snapshot_deltas;
+
+ALTER TABLE snapshots DROP COLUMN IF EXISTS bundle;
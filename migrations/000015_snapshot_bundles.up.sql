@@ -0,0 +1,15 @@
+-- Bundles and the deltas between them are kept in the database, so that
+-- every instance can serve them. Snapshots written to a local directory
+-- before are dropped; the next snapshot job writes a new one.
+DELETE FROM snapshots;
+
+ALTER TABLE snapshots ADD COLUMN IF NOT EXISTS bundle BYTEA NOT NULL;
+
+CREATE TABLE IF NOT EXISTS snapshot_deltas
+(
+    from_version  BIGINT NOT NULL REFERENCES snapshots (version) ON DELETE CASCADE,
+    to_version    BIGINT NOT NULL REFERENCES snapshots (version) ON DELETE CASCADE,
+    created_at    TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    patch         BYTEA NOT NULL,
+    PRIMARY KEY (from_version, to_version)
+    );
@@ -0,0 +1,9 @@
+-- Files written by jobs, such as exports, are kept in the database so that
+-- every instance can serve them. Exports written to a local directory before
+-- are no longer found and have to be queued again.
+CREATE TABLE IF NOT EXISTS job_files
+(
+    created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    job_id      VARCHAR PRIMARY KEY REFERENCES jobs (id) ON DELETE CASCADE,
+    content     BYTEA NOT NULL
+    );
@@ -0,0 +1,10 @@
+// Package migrations embeds the golang-migrate files of the database schema,
+// so the binary migrates from wherever it is started.
+package migrations
+
+import "embed"
+
+// FS holds every <version>_<name>.up.sql and .down.sql file of this directory.
+//
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"product/pkg/store"
+)
+
+func TestEveryMigrationCanBeReverted(t *testing.T) {
+	ups, err := fs.Glob(FS, "*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ups) == 0 {
+		t.Fatal("no migration is embedded")
+	}
+
+	for _, up := range ups {
+		down := strings.TrimSuffix(up, ".up.sql") + ".down.sql"
+		if _, err := fs.Stat(FS, down); err != nil {
+			t.Errorf("%s has no %s", up, down)
+		}
+	}
+}
+
+func TestLatestMigration(t *testing.T) {
+	ups, err := fs.Glob(FS, "*.up.sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	latest, err := store.LatestMigration(FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(latest) != len(ups) {
+		t.Errorf("latest migration is %d, want %d, one per up file", latest, len(ups))
+	}
+}
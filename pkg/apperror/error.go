@@ -0,0 +1,134 @@
+package apperror
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Kind classifies an error by what the caller can do about it.
+type Kind string
+
+const (
+	NotFound           Kind = "not_found"
+	Conflict           Kind = "conflict"
+	Validation         Kind = "validation"
+	PreconditionFailed Kind = "precondition_failed"
+	Unauthorized       Kind = "unauthorized"
+	Forbidden          Kind = "forbidden"
+	Internal           Kind = "internal"
+)
+
+// kinds maps every kind to its HTTP and gRPC status codes.
+var kinds = map[Kind]struct {
+	http int
+	grpc codes.Code
+}{
+	NotFound:           {http.StatusNotFound, codes.NotFound},
+	Conflict:           {http.StatusConflict, codes.Aborted},
+	Validation:         {http.StatusBadRequest, codes.InvalidArgument},
+	PreconditionFailed: {http.StatusPreconditionFailed, codes.FailedPrecondition},
+	Unauthorized:       {http.StatusUnauthorized, codes.Unauthenticated},
+	Forbidden:          {http.StatusForbidden, codes.PermissionDenied},
+	Internal:           {http.StatusInternalServerError, codes.Internal},
+}
+
+// Error is an error of a known kind whose message is safe to show to clients.
+type Error struct {
+	Kind    Kind
+	Message string
+	// Details are sent to clients along with the message, e.g. the fields
+	// that failed validation.
+	Details any
+	Err     error
+}
+
+// New returns an error of the given kind.
+func New(kind Kind, message string) *Error {
+	return &Error{Kind: kind, Message: message}
+}
+
+// Wrap returns an error of the given kind that shows the message of err,
+// and its details when err is an Error.
+func Wrap(kind Kind, err error) *Error {
+	wrapped := &Error{Kind: kind, Message: err.Error(), Err: err}
+
+	var e *Error
+	if errors.As(err, &e) {
+		wrapped.Details = e.Details
+	}
+
+	return wrapped
+}
+
+// WithDetails sets the details of e and returns it.
+func (e *Error) WithDetails(details any) *Error {
+	e.Details = details
+	return e
+}
+
+func (e *Error) Error() string { return e.Message }
+func (e *Error) Unwrap() error { return e.Err }
+
+// GRPCStatus lets gRPC servers send the error with the status code of its kind.
+func (e *Error) GRPCStatus() *status.Status {
+	return status.New(kinds[KindOf(e)].grpc, Message(e))
+}
+
+// KindOf returns the kind of the first Error in the chain of err, Internal
+// when there is none.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		if _, ok := kinds[e.Kind]; ok {
+			return e.Kind
+		}
+	}
+	return Internal
+}
+
+// Is reports whether err is of the given kind.
+func Is(err error, kind Kind) bool {
+	return err != nil && KindOf(err) == kind
+}
+
+// HTTPStatus returns the HTTP status code of err.
+func HTTPStatus(err error) int {
+	return kinds[KindOf(err)].http
+}
+
+// Message returns what clients may be told about err. The message of an
+// internal error is never shown, it may carry details of the database.
+func Message(err error) string {
+	var e *Error
+	if KindOf(err) == Internal || !errors.As(err, &e) {
+		return http.StatusText(http.StatusInternalServerError)
+	}
+	return e.Message
+}
+
+// Details returns the details of err, if clients may see them.
+func Details(err error) any {
+	var e *Error
+	if KindOf(err) == Internal || !errors.As(err, &e) {
+		return nil
+	}
+	return e.Details
+}
+
+// GRPCStatus converts err into a gRPC status, hiding internal errors.
+func GRPCStatus(err error) *status.Status {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err)
+	}
+	if s, ok := status.FromError(err); ok && s.Code() != codes.Unknown {
+		return s
+	}
+	return status.New(kinds[KindOf(err)].grpc, Message(err))
+}
@@ -0,0 +1,53 @@
+package country
+
+import (
+	"sort"
+	"strings"
+)
+
+// Country is an ISO 3166-1 entry.
+type Country struct {
+	Alpha2 string `json:"alpha2"`
+	Alpha3 string `json:"alpha3"`
+	Name   string `json:"name"`
+}
+
+// All returns a copy of the built-in country table.
+func All() []Country {
+	res := make([]Country, len(countries))
+	copy(res, countries)
+	return res
+}
+
+// Lookup finds a country by its alpha-2 code, ignoring case.
+func Lookup(code string) (Country, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	i := sort.Search(len(countries), func(i int) bool {
+		return countries[i].Alpha2 >= code
+	})
+	if i < len(countries) && countries[i].Alpha2 == code {
+		return countries[i], true
+	}
+	return Country{}, false
+}
+
+// Valid reports whether code is a known ISO 3166-1 alpha-2 code.
+func Valid(code string) bool {
+	_, ok := Lookup(code)
+	return ok
+}
+
+// Resolve matches free text against alpha-2 and alpha-3 codes and English names.
+func Resolve(value string) (Country, bool) {
+	value = strings.TrimSpace(value)
+	if c, ok := Lookup(value); ok {
+		return c, true
+	}
+
+	for _, c := range countries {
+		if strings.EqualFold(c.Alpha3, value) || strings.EqualFold(c.Name, value) {
+			return c, true
+		}
+	}
+	return Country{}, false
+}
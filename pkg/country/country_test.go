@@ -0,0 +1,70 @@
+package country
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestTableIsSorted(t *testing.T) {
+	if !sort.SliceIsSorted(countries, func(i, j int) bool { return countries[i].Alpha2 < countries[j].Alpha2 }) {
+		t.Fatal("countries are not sorted by alpha-2 code, Lookup needs them to be")
+	}
+	for i := 1; i < len(countries); i++ {
+		if countries[i].Alpha2 == countries[i-1].Alpha2 {
+			t.Errorf("%s is listed twice", countries[i].Alpha2)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+		ok   bool
+	}{
+		{"DE", "DEU", true},
+		{"de", "DEU", true},
+		{" ad ", "AND", true},
+		{"ZW", "ZWE", true},
+		{"DEU", "", false},
+		{"ZZ", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Lookup(tt.code)
+		if ok != tt.ok || got.Alpha3 != tt.want {
+			t.Errorf("Lookup(%q) = %q, %t, want %q, %t", tt.code, got.Alpha3, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+		ok    bool
+	}{
+		{"DE", "DE", true},
+		{"deu", "DE", true},
+		{" Germany ", "DE", true},
+		{"germany", "DE", true},
+		{"Deutschland", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Resolve(tt.value)
+		if ok != tt.ok || got.Alpha2 != tt.want {
+			t.Errorf("Resolve(%q) = %q, %t, want %q, %t", tt.value, got.Alpha2, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAllReturnsACopy(t *testing.T) {
+	all := All()
+	all[0].Name = "changed"
+	if countries[0].Name == "changed" {
+		t.Error("All() shares the built-in table")
+	}
+}
@@ -0,0 +1,153 @@
+// Package delta computes binary diffs: a delta rebuilds a target from a base
+// by copying ranges of the base and inserting the bytes it lacks.
+package delta
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	magic     = "DLT1"
+	blockSize = 32
+
+	opCopy   byte = 0
+	opInsert byte = 1
+
+	hashBase = 257
+)
+
+var ErrCorrupt = errors.New("delta: corrupt or mismatched delta")
+
+// Diff returns a delta that turns base into target.
+func Diff(base, target []byte) []byte {
+	out := bytes.NewBufferString(magic)
+	writeUvarint(out, uint64(len(target)))
+
+	index := indexBlocks(base)
+
+	var pow uint32 = 1
+	for i := 1; i < blockSize; i++ {
+		pow *= hashBase
+	}
+
+	literal, p := 0, 0
+	var h uint32
+	rolled := false
+	for p+blockSize <= len(target) {
+		if !rolled {
+			h = hashBlock(target[p : p+blockSize])
+			rolled = true
+		}
+
+		if offset, ok := index[h]; ok && bytes.Equal(base[offset:offset+blockSize], target[p:p+blockSize]) {
+			// Grow the match backwards over the pending literal, then forwards.
+			start, from := p, offset
+			for start > literal && from > 0 && base[from-1] == target[start-1] {
+				start--
+				from--
+			}
+			end := p + blockSize
+			for end < len(target) && from+(end-start) < len(base) && base[from+(end-start)] == target[end] {
+				end++
+			}
+
+			writeInsert(out, target[literal:start])
+			out.WriteByte(opCopy)
+			writeUvarint(out, uint64(from))
+			writeUvarint(out, uint64(end-start))
+
+			literal, p, rolled = end, end, false
+			continue
+		}
+
+		if p+blockSize < len(target) {
+			h = (h-uint32(target[p])*pow)*hashBase + uint32(target[p+blockSize])
+		}
+		p++
+	}
+	writeInsert(out, target[literal:])
+
+	return out.Bytes()
+}
+
+// Patch applies a delta produced by Diff to base and returns the target.
+func Patch(base, delta []byte) ([]byte, error) {
+	if !bytes.HasPrefix(delta, []byte(magic)) {
+		return nil, ErrCorrupt
+	}
+	r := bytes.NewReader(delta[len(magic):])
+
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, ErrCorrupt
+	}
+	target := make([]byte, 0, min(size, uint64(len(base)+len(delta))))
+
+	for r.Len() > 0 {
+		op, _ := r.ReadByte()
+		switch op {
+		case opCopy:
+			offset, err1 := binary.ReadUvarint(r)
+			length, err2 := binary.ReadUvarint(r)
+			if err1 != nil || err2 != nil || offset > uint64(len(base)) || length > uint64(len(base))-offset {
+				return nil, ErrCorrupt
+			}
+			target = append(target, base[offset:offset+length]...)
+		case opInsert:
+			length, err := binary.ReadUvarint(r)
+			if err != nil || length > uint64(r.Len()) {
+				return nil, ErrCorrupt
+			}
+			chunk := make([]byte, length)
+			r.Read(chunk)
+			target = append(target, chunk...)
+		default:
+			return nil, ErrCorrupt
+		}
+
+		if uint64(len(target)) > size {
+			return nil, ErrCorrupt
+		}
+	}
+
+	if uint64(len(target)) != size {
+		return nil, ErrCorrupt
+	}
+
+	return target, nil
+}
+
+// indexBlocks maps the hash of every aligned block of base to its offset.
+func indexBlocks(base []byte) map[uint32]int {
+	index := make(map[uint32]int, len(base)/blockSize)
+	for offset := 0; offset+blockSize <= len(base); offset += blockSize {
+		h := hashBlock(base[offset : offset+blockSize])
+		if _, ok := index[h]; !ok {
+			index[h] = offset
+		}
+	}
+	return index
+}
+
+func hashBlock(block []byte) (h uint32) {
+	for _, b := range block {
+		h = h*hashBase + uint32(b)
+	}
+	return
+}
+
+func writeInsert(out *bytes.Buffer, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	out.WriteByte(opInsert)
+	writeUvarint(out, uint64(len(data)))
+	out.Write(data)
+}
+
+func writeUvarint(out *bytes.Buffer, v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	out.Write(buf[:binary.PutUvarint(buf[:], v)])
+}
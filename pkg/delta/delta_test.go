@@ -0,0 +1,115 @@
+package delta
+
+import (
+	"bytes"
+	"errors"
+	"math/rand/v2"
+	"testing"
+)
+
+// random returns n bytes that repeat no block, so matches only come from
+// what a test copies on purpose.
+func random(seed uint64, n int) []byte {
+	r := rand.New(rand.NewPCG(seed, seed))
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(r.UintN(256))
+	}
+	return data
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestRoundTrip(t *testing.T) {
+	base := random(1, 8*blockSize)
+	extra := random(2, 3*blockSize)
+
+	tests := []struct {
+		name   string
+		base   []byte
+		target []byte
+	}{
+		{"empty base and target", nil, nil},
+		{"empty base", nil, base},
+		{"empty target", base, nil},
+		{"identical", base, base},
+		{"shorter than a block", base[:blockSize-1], base[1 : blockSize-1]},
+		{"target shorter than a block", base, base[:blockSize/2]},
+		{"base shorter than a block", base[:blockSize/2], base},
+		{"insert at a block boundary", base, join(base[:2*blockSize], extra, base[2*blockSize:])},
+		{"insert inside a block", base, join(base[:2*blockSize+5], extra[:7], base[2*blockSize+5:])},
+		{"insert at the start", base, join(extra[:blockSize], base)},
+		{"append at the end", base, join(base, extra[:5])},
+		{"delete a block", base, join(base[:3*blockSize], base[4*blockSize:])},
+		{"delete across a boundary", base, join(base[:3*blockSize-4], base[4*blockSize+4:])},
+		{"delete the first block", base, base[blockSize:]},
+		{"delete the last byte", base, base[:len(base)-1]},
+		{"reorder blocks", base, join(base[4*blockSize:], base[:4*blockSize])},
+		{"repeat a block", base, join(base[:blockSize], base[:blockSize], base[blockSize:])},
+		{"unrelated", base, extra},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Patch(tc.base, Diff(tc.base, tc.target))
+			if err != nil {
+				t.Fatalf("Patch: %v", err)
+			}
+			if !bytes.Equal(got, tc.target) {
+				t.Fatalf("Patch(base, Diff(base, target)) = %x, want %x", got, tc.target)
+			}
+		})
+	}
+}
+
+func TestDiffCopiesSharedBlocks(t *testing.T) {
+	base := random(1, 64*blockSize)
+	target := join(base[:32*blockSize], []byte("changed"), base[32*blockSize:])
+
+	if d := Diff(base, target); len(d) > 4*blockSize {
+		t.Fatalf("delta of a one-word insert is %d bytes long", len(d))
+	}
+}
+
+func TestPatchRejectsCorruptDeltas(t *testing.T) {
+	base := random(1, 8*blockSize)
+	target := join(base[:4*blockSize], random(2, 10), base[4*blockSize:])
+	valid := Diff(base, target)
+
+	tests := []struct {
+		name  string
+		base  []byte
+		delta []byte
+	}{
+		{"empty", base, nil},
+		{"bad magic", base, join([]byte("XXXX"), valid[len(magic):])},
+		{"missing size", base, []byte(magic)},
+		{"truncated", base, valid[:len(valid)-1]},
+		{"truncated to the header", base, valid[:len(magic)+1]},
+		{"unknown op", base, join(valid, []byte{7})},
+		{"trailing op", base, join(valid, []byte{opInsert, 1, 'x'})},
+		{"copy past the base", base[:2*blockSize], valid},
+		{"insert past the end", base, join([]byte(magic), []byte{5, opInsert, 10, 'a', 'b'})},
+		{"wrong size", base, join([]byte(magic), []byte{3, opInsert, 2, 'a', 'b'})},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := Patch(tc.base, tc.delta); !errors.Is(err, ErrCorrupt) {
+				t.Fatalf("Patch = %v, want ErrCorrupt", err)
+			}
+		})
+	}
+}
+
+func TestPatchRejectsEveryTruncation(t *testing.T) {
+	base := random(1, 8*blockSize)
+	target := join(random(3, 5), base[:4*blockSize], random(2, 10), base[5*blockSize:])
+	valid := Diff(base, target)
+
+	for n := range len(valid) {
+		if _, err := Patch(base, valid[:n]); !errors.Is(err, ErrCorrupt) {
+			t.Fatalf("Patch of the first %d of %d bytes = %v, want ErrCorrupt", n, len(valid), err)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// Requests logs every request once it is served, with the trace_id and
+// span_id of its span. It must run inside the tracing middleware so the span
+// is already in the request context.
+func Requests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+
+		defer func() {
+			code := ww.Status()
+			if code == 0 {
+				code = http.StatusOK
+			}
+
+			fields := append(TraceFields(r.Context()),
+				zap.String("method", r.Method),
+				zap.String("path", r.URL.Path),
+				zap.Int("status", code),
+				zap.Int("bytes", ww.BytesWritten()),
+				zap.Duration("duration", time.Since(start)),
+				zap.String("request_id", middleware.GetReqID(r.Context())),
+				zap.String("remote_addr", r.RemoteAddr))
+			zap.L().Info("HTTP_REQUEST", fields...)
+		}()
+
+		next.ServeHTTP(ww, r)
+	})
+}
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// observe replaces the global logger for the duration of the test and
+// returns what is logged.
+func observe(t *testing.T) *observer.ObservedLogs {
+	core, logs := observer.New(zapcore.InfoLevel)
+	t.Cleanup(zap.ReplaceGlobals(zap.New(core)))
+	return logs
+}
+
+func TestRequests(t *testing.T) {
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1},
+		SpanID:  trace.SpanID{2},
+	})
+	handler := Requests(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte("short"))
+	}))
+
+	cases := []struct {
+		name   string
+		span   bool
+		fields map[string]any
+	}{
+		{"with a span", true, map[string]any{
+			"trace_id": spanContext.TraceID().String(),
+			"span_id":  spanContext.SpanID().String(),
+		}},
+		{"without a span", false, map[string]any{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			logs := observe(t)
+
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
+			if tc.span {
+				r = r.WithContext(trace.ContextWithSpanContext(r.Context(), spanContext))
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+
+			entries := logs.FilterMessage("HTTP_REQUEST").All()
+			if len(entries) != 1 {
+				t.Fatalf("got %d request lines, want 1", len(entries))
+			}
+			fields := entries[0].ContextMap()
+			if fields["status"] != int64(http.StatusTeapot) || fields["bytes"] != int64(5) || fields["path"] != "/api/v1/products" {
+				t.Errorf("got status %v, bytes %v and path %v", fields["status"], fields["bytes"], fields["path"])
+			}
+			for _, key := range []string{"trace_id", "span_id"} {
+				if fields[key] != tc.fields[key] {
+					t.Errorf("got %s %v, want %v", key, fields[key], tc.fields[key])
+				}
+			}
+		})
+	}
+}
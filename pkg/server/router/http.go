@@ -2,6 +2,8 @@ package router
 
 import (
 	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5/middleware"
@@ -9,7 +11,10 @@ import (
 	"github.com/go-chi/render"
 )
 
-func New() *chi.Mux {
+// New creates the router with the common middleware. Requests are not
+// logged here, see log.Requests. Browsers may call the API cross-origin only
+// from allowedOrigins; without any, CORS is left off.
+func New(allowedOrigins ...string) *chi.Mux {
 	// Init a new router instance
 	r := chi.NewRouter()
 
@@ -17,8 +22,6 @@ func New() *chi.Mux {
 
 	r.Use(middleware.RealIP)
 
-	r.Use(middleware.Logger)
-
 	r.Use(middleware.Recoverer)
 
 	r.Use(middleware.CleanPath)
@@ -27,17 +30,40 @@ func New() *chi.Mux {
 
 	r.Use(middleware.Timeout(time.Second * 60))
 
-	r.Use(middleware.AllowContentType("application/json"))
+	r.Use(middleware.AllowContentType("application/json", "multipart/form-data"))
 
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   []string{"*"},
-		AllowedMethods:   []string{"GET", "PUT", "POST", "DELETE", "HEAD", "OPTIONS"},
-		AllowedHeaders:   []string{"*"},
-		AllowCredentials: true,
-		MaxAge:           300, // Maximum value not ignored by any of major browsers
-	}))
+	// Callers authenticate with a bearer token rather than cookies, so
+	// credentials are never allowed cross-origin.
+	if len(allowedOrigins) > 0 {
+		r.Use(cors.Handler(cors.Options{
+			AllowedOrigins:   allowedOrigins,
+			AllowedMethods:   []string{"GET", "PUT", "POST", "DELETE", "HEAD", "OPTIONS"},
+			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-API-Key", "X-Request-Id", "traceparent", "tracestate"},
+			AllowCredentials: false,
+			MaxAge:           300, // Maximum value not ignored by any of major browsers
+		}))
+	}
 
 	return r
 }
+
+// Pattern returns the chi pattern of the route r matched, e.g.
+// "/api/v1/products/{id}", or "unmatched" when none did. It is only complete
+// once the request has been routed.
+func Pattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return "unmatched"
+	}
+
+	pattern := rctx.RoutePattern()
+	if pattern == "" {
+		return "unmatched"
+	}
+	if pattern != "/" {
+		pattern = strings.TrimSuffix(strings.ReplaceAll(pattern, "/*/", "/"), "/")
+	}
+	return pattern
+}
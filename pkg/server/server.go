@@ -9,10 +9,13 @@ import (
 	"go.uber.org/zap"
 
 	"google.golang.org/grpc"
+
+	"product/pkg/apperror"
 )
 
 type Server struct {
 	http     *http.Server
+	admin    *http.Server
 	grpc     *grpc.Server
 	listener net.Listener
 }
@@ -47,6 +50,15 @@ func (s *Server) Run(logger *zap.Logger) (err error) {
 		logger.Info("http server started on http://localhost" + s.http.Addr)
 	}
 
+	if s.admin != nil {
+		go func() {
+			if err := s.admin.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Error("ERR_INIT_ADMIN", zap.Error(err))
+			}
+		}()
+		logger.Info("admin server started on http://localhost" + s.admin.Addr)
+	}
+
 	if s.grpc != nil {
 		if err = s.grpc.Serve(s.listener); err != nil {
 			return
@@ -64,6 +76,12 @@ func (s *Server) Stop(ctx context.Context) (err error) {
 		}
 	}
 
+	if s.admin != nil {
+		if err = s.admin.Shutdown(ctx); err != nil {
+			return
+		}
+	}
+
 	return
 }
 
@@ -73,7 +91,10 @@ func WithGRPCServer(port string) Configuration {
 		if err != nil {
 			return
 		}
-		s.grpc = &grpc.Server{}
+		s.grpc = grpc.NewServer(
+			grpc.ChainUnaryInterceptor(unaryTracing, unaryErrors),
+			grpc.ChainStreamInterceptor(streamTracing, streamErrors),
+		)
 
 		return
 	}
@@ -88,3 +109,33 @@ func WithHTTPServer(handler http.Handler, port string) Configuration {
 		return
 	}
 }
+
+// WithAdminServer serves handler on its own port, apart from the API, e.g.
+// for metrics that must not be reachable from outside.
+func WithAdminServer(handler http.Handler, port string) Configuration {
+	return func(s *Server) (err error) {
+		s.admin = &http.Server{
+			Handler: handler,
+			Addr:    ":" + port,
+		}
+		return
+	}
+}
+
+// unaryErrors sends the errors of unary handlers with the status code of
+// their apperror kind.
+func unaryErrors(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	res, err := handler(ctx, req)
+	if err != nil {
+		return res, apperror.GRPCStatus(err).Err()
+	}
+	return res, nil
+}
+
+// streamErrors is unaryErrors for streaming handlers.
+func streamErrors(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, ss); err != nil {
+		return apperror.GRPCStatus(err).Err()
+	}
+	return nil
+}
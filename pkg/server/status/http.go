@@ -23,28 +23,3 @@ func OK(data any) Response {
 		Data:    data,
 	}
 }
-
-func BadRequest(err error, data any) Response {
-	return Response{
-		Status:  http.StatusBadRequest,
-		Success: false,
-		Message: err.Error(),
-		Data:    data,
-	}
-}
-
-func NotFound(err error) Response {
-	return Response{
-		Status:  http.StatusNotFound,
-		Success: false,
-		Message: err.Error(),
-	}
-}
-
-func InternalServerError(err error) Response {
-	return Response{
-		Status:  http.StatusInternalServerError,
-		Success: false,
-		Message: err.Error(),
-	}
-}
@@ -0,0 +1,59 @@
+package status
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+
+	"product/pkg/apperror"
+	"product/pkg/log"
+)
+
+// ContentTypeProblem is the media type of error responses, see RFC 7807.
+const ContentTypeProblem = "application/problem+json"
+
+// Problem is the body of an error response, see RFC 7807.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      string `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+	Errors    any    `json:"errors,omitempty"`
+}
+
+// NewProblem describes err with the status code of its kind. Internal errors
+// are described generically.
+func NewProblem(r *http.Request, err error) Problem {
+	code := apperror.HTTPStatus(err)
+
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(code),
+		Status:    code,
+		Detail:    apperror.Message(err),
+		Instance:  r.URL.Path,
+		Code:      string(apperror.KindOf(err)),
+		RequestID: middleware.GetReqID(r.Context()),
+		Errors:    apperror.Details(err),
+	}
+}
+
+// Error writes err as a problem response. Internal errors are logged, since
+// their cause is not sent to the client.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	problem := NewProblem(r, err)
+	if problem.Status >= http.StatusInternalServerError {
+		fields := append(log.TraceFields(r.Context()), zap.String("path", r.URL.Path),
+			zap.String("request_id", problem.RequestID), zap.Error(err))
+		zap.L().Error("ERR_HANDLE_REQUEST", fields...)
+	}
+
+	w.Header().Set("Content-Type", ContentTypeProblem)
+	w.WriteHeader(problem.Status)
+	json.NewEncoder(w).Encode(problem)
+}
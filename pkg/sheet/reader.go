@@ -0,0 +1,92 @@
+package sheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("sheet: unsupported format")
+
+// Reader yields the rows of a tabular file one at a time.
+// Read returns io.EOF after the last row.
+type Reader interface {
+	Read() (row []string, err error)
+	Close() error
+}
+
+// NewReader opens data in the given format. For XLSX only the first sheet is read.
+func NewReader(format string, data []byte) (Reader, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		r := csv.NewReader(bytes.NewReader(data))
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+		return &csvReader{r: r}, nil
+	case FormatXLSX:
+		f, err := excelize.OpenReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		rows, err := f.Rows(f.GetSheetName(0))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &xlsxReader{f: f, rows: rows}, nil
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+// FormatFromFilename guesses the format from the file extension.
+func FormatFromFilename(filename string) string {
+	switch {
+	case strings.HasSuffix(strings.ToLower(filename), ".csv"):
+		return FormatCSV
+	case strings.HasSuffix(strings.ToLower(filename), ".xlsx"):
+		return FormatXLSX
+	}
+	return ""
+}
+
+type csvReader struct {
+	r *csv.Reader
+}
+
+func (s *csvReader) Read() ([]string, error) {
+	return s.r.Read()
+}
+
+func (s *csvReader) Close() error {
+	return nil
+}
+
+type xlsxReader struct {
+	f    *excelize.File
+	rows *excelize.Rows
+}
+
+func (s *xlsxReader) Read() ([]string, error) {
+	if !s.rows.Next() {
+		if err := s.rows.Error(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	return s.rows.Columns()
+}
+
+func (s *xlsxReader) Close() error {
+	s.rows.Close()
+	return s.f.Close()
+}
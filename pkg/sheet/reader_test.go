@@ -0,0 +1,90 @@
+package sheet
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var rows = [][]string{
+	{"barcode", "name", "cost"},
+	{"123", "Orange juice, 1l", "250"},
+	{"456", "Apple"},
+}
+
+// readAll reads every row of data in the given format.
+func readAll(t *testing.T, format string, data []byte) (res [][]string) {
+	t.Helper()
+
+	r, err := NewReader(format, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	for {
+		row, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, row)
+	}
+}
+
+func TestReadCSV(t *testing.T) {
+	data := "barcode,name,cost\n123, \"Orange juice, 1l\",250\n456,Apple\n"
+
+	if got := readAll(t, FormatCSV, []byte(data)); !reflect.DeepEqual(got, rows) {
+		t.Errorf("got %q, want %q", got, rows)
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	f := excelize.NewFile()
+	for i, row := range rows {
+		cell, _ := excelize.CoordinatesToCellName(1, i+1)
+		values := make([]any, len(row))
+		for j, value := range row {
+			values[j] = value
+		}
+		if err := f.SetSheetRow(f.GetSheetName(0), cell, &values); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readAll(t, "XLSX", buf.Bytes()); !reflect.DeepEqual(got, rows) {
+		t.Errorf("got %q, want %q", got, rows)
+	}
+}
+
+func TestNewReaderUnsupportedFormat(t *testing.T) {
+	if _, err := NewReader("ods", nil); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("got error %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	tests := map[string]string{
+		"products.csv":  FormatCSV,
+		"PRODUCTS.XLSX": FormatXLSX,
+		"products.xls":  "",
+		"products":      "",
+	}
+
+	for filename, want := range tests {
+		if got := FormatFromFilename(filename); got != want {
+			t.Errorf("FormatFromFilename(%q) = %q, want %q", filename, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package sheet
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Writer appends rows to a tabular file. Close must be called to flush it.
+type Writer interface {
+	Write(row []string) error
+	Close() error
+}
+
+// NewWriter creates a writer producing the given format on w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch strings.ToLower(format) {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter(f.GetSheetName(0))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &xlsxWriter{f: f, sw: sw, w: w}, nil
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func (s *csvWriter) Write(row []string) error {
+	return s.w.Write(row)
+}
+
+func (s *csvWriter) Close() error {
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// xlsxWriter relies on the excelize stream writer, which spills rows to a
+// temporary file once they no longer fit its in-memory buffer.
+type xlsxWriter struct {
+	f    *excelize.File
+	sw   *excelize.StreamWriter
+	w    io.Writer
+	rows int
+}
+
+func (s *xlsxWriter) Write(row []string) error {
+	s.rows++
+	cell, err := excelize.CoordinatesToCellName(1, s.rows)
+	if err != nil {
+		return err
+	}
+
+	values := make([]any, len(row))
+	for i, value := range row {
+		values[i] = value
+	}
+	return s.sw.SetRow(cell, values)
+}
+
+func (s *xlsxWriter) Close() error {
+	defer s.f.Close()
+
+	if err := s.sw.Flush(); err != nil {
+		return err
+	}
+	return s.f.Write(s.w)
+}
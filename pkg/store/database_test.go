@@ -0,0 +1,34 @@
+package store
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLatestMigration(t *testing.T) {
+	migrations := fstest.MapFS{
+		"000001_init.up.sql":      {},
+		"000001_init.down.sql":    {},
+		"000002_products.up.sql":  {},
+		"000010_imports.up.sql":   {},
+		"000010_imports.down.sql": {},
+	}
+
+	version, err := LatestMigration(migrations)
+	if err != nil || version != 10 {
+		t.Errorf("LatestMigration() = %d, %v, want 10", version, err)
+	}
+
+	if _, err = LatestMigration(fstest.MapFS{}); err == nil {
+		t.Error("LatestMigration() of no migration did not fail")
+	}
+}
+
+func TestMigrationLockKey(t *testing.T) {
+	if migrationLockKey("public") != migrationLockKey("public") {
+		t.Error("the lock of a schema changes between calls")
+	}
+	if migrationLockKey("public") == migrationLockKey("tenant") {
+		t.Error("two schemas share a migration lock")
+	}
+}
@@ -2,6 +2,67 @@ package store
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"product/pkg/apperror"
 )
 
-var ErrorNotFound = errors.New("store: no rows in result set")
+var ErrorNotFound = apperror.New(apperror.NotFound, "store: no rows in result set")
+
+// keyColumns finds the columns in the detail of a constraint violation,
+// e.g. "Key (barcode)=(123) already exists."
+var keyColumns = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+
+// Translate turns a Postgres error into an apperror.Error of the matching
+// kind, so clients learn what was wrong without seeing the raw message. Other
+// errors are returned as they are.
+func Translate(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	var kind apperror.Kind
+	var message string
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		kind, message = apperror.Conflict, fmt.Sprintf("%s: a record with the same %s already exists", pqErr.Table, column(pqErr))
+	case "23503": // foreign_key_violation
+		// The same code is raised by deleting a row others still point at.
+		if strings.Contains(pqErr.Detail, "is still referenced") {
+			kind, message = apperror.Conflict, fmt.Sprintf("the record is still referenced by %s", pqErr.Table)
+		} else {
+			kind, message = apperror.Validation, fmt.Sprintf("%s: the referenced record does not exist", column(pqErr))
+		}
+	case "23502": // not_null_violation
+		kind, message = apperror.Validation, fmt.Sprintf("%s: cannot be blank", column(pqErr))
+	case "23514": // check_violation
+		kind, message = apperror.Validation, fmt.Sprintf("%s: violates the %s constraint", pqErr.Table, pqErr.Constraint)
+	case "22001", "22003", "22007", "22P02": // string too long, out of range, bad datetime, bad text representation
+		kind, message = apperror.Validation, "invalid input value"
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		kind, message = apperror.Conflict, "the request conflicted with a concurrent one, try again"
+	default:
+		return err
+	}
+
+	return &apperror.Error{Kind: kind, Message: message, Err: err}
+}
+
+// column names the columns a violation is about, as precisely as Postgres tells.
+func column(err *pq.Error) string {
+	if err.Column != "" {
+		return err.Column
+	}
+	if match := keyColumns.FindStringSubmatch(err.Detail); match != nil {
+		return match[1]
+	}
+	if err.Constraint != "" {
+		return err.Constraint
+	}
+	return "value"
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+
+	"product/pkg/apperror"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     *pq.Error
+		kind    apperror.Kind
+		message string
+	}{
+		{"unique violation",
+			&pq.Error{Code: "23505", Table: "products", Detail: "Key (barcode)=(123) already exists."},
+			apperror.Conflict, "products: a record with the same barcode already exists"},
+		{"missing reference",
+			&pq.Error{Code: "23503", Table: "products", Detail: `Key (category_id)=(1) is not present in table "categories".`},
+			apperror.Validation, "category_id: the referenced record does not exist"},
+		{"row still referenced",
+			&pq.Error{Code: "23503", Table: "products", Detail: `Key (id)=(1) is still referenced from table "products".`},
+			apperror.Conflict, "the record is still referenced by products"},
+		{"not null violation",
+			&pq.Error{Code: "23502", Table: "products", Column: "name"},
+			apperror.Validation, "name: cannot be blank"},
+		{"check violation",
+			&pq.Error{Code: "23514", Table: "products", Constraint: "products_cost_check"},
+			apperror.Validation, "products: violates the products_cost_check constraint"},
+		{"string too long", &pq.Error{Code: "22001"}, apperror.Validation, "invalid input value"},
+		{"out of range", &pq.Error{Code: "22003"}, apperror.Validation, "invalid input value"},
+		{"bad datetime", &pq.Error{Code: "22007"}, apperror.Validation, "invalid input value"},
+		{"bad text representation", &pq.Error{Code: "22P02"}, apperror.Validation, "invalid input value"},
+		{"serialization failure", &pq.Error{Code: "40001"},
+			apperror.Conflict, "the request conflicted with a concurrent one, try again"},
+		{"deadlock", &pq.Error{Code: "40P01"},
+			apperror.Conflict, "the request conflicted with a concurrent one, try again"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Translate(fmt.Errorf("query: %w", tt.err))
+			if !apperror.Is(err, tt.kind) {
+				t.Errorf("got kind %s, want %s", apperror.KindOf(err), tt.kind)
+			}
+			if got := apperror.Message(err); got != tt.message {
+				t.Errorf("got message %q, want %q", got, tt.message)
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("the Postgres error is not wrapped")
+			}
+		})
+	}
+}
+
+func TestTranslatePassesOtherErrorsThrough(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"unknown code", &pq.Error{Code: "XX000"}},
+		{"not a Postgres error", errors.New("connection refused")},
+		{"no error", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Translate(tt.err); got != tt.err {
+				t.Errorf("Translate(%v) = %v, want it unchanged", tt.err, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// QueryObserver is told how long every query took and how it ended. name is
+// the repository method that ran it, e.g. "ProductRepository.Get".
+type QueryObserver func(name string, duration time.Duration, err error)
+
+var observer QueryObserver
+
+var tracer = otel.Tracer("product/pkg/store")
+
+// ObserveQueries makes every query run through Conn reported to fn. It must
+// be called before the first query.
+func ObserveQueries(fn QueryObserver) {
+	observer = fn
+}
+
+// observation times a single query and traces it as a child of the span
+// carried by the context it runs with.
+type observation struct {
+	name  string
+	start time.Time
+	span  trace.Span
+}
+
+func observe(ctx context.Context, query string) observation {
+	_, span := tracer.Start(ctx, "query", trace.WithSpanKind(trace.SpanKindClient))
+	if observer == nil && !span.IsRecording() {
+		return observation{span: span}
+	}
+
+	o := observation{name: caller(), start: time.Now(), span: span}
+	span.SetName(o.name)
+	span.SetAttributes(semconv.DBSystemNamePostgreSQL, semconv.DBQuerySummary(o.name), semconv.DBQueryText(query))
+
+	return o
+}
+
+func (o observation) done(err error) {
+	if observer != nil {
+		observer(o.name, time.Since(o.start), err)
+	}
+
+	if err != nil && err != sql.ErrNoRows && err != context.Canceled {
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	}
+	o.span.End()
+}
+
+// caller names the first function outside this package on the stack.
+func caller() string {
+	pcs := make([]uintptr, 8)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "product/pkg/store.") {
+			return shortName(frame.Function)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// shortName turns "product/internal/repository/postgres.(*ProductRepository).Get.func1"
+// into "ProductRepository.Get".
+func shortName(function string) string {
+	if i := strings.LastIndex(function, "/"); i >= 0 {
+		function = function[i+1:]
+	}
+	if _, rest, ok := strings.Cut(function, "."); ok {
+		function = rest
+	}
+	function = strings.NewReplacer("(*", "", ")", "").Replace(function)
+
+	parts := strings.Split(function, ".")
+	for len(parts) > 1 && strings.HasPrefix(parts[len(parts)-1], "func") {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, ".")
+}
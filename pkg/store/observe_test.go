@@ -0,0 +1,18 @@
+package store
+
+import "testing"
+
+func TestShortName(t *testing.T) {
+	tests := map[string]string{
+		"product/internal/repository/postgres.(*ProductRepository).Get":             "ProductRepository.Get",
+		"product/internal/repository/postgres.(*ProductRepository).Get.func1":       "ProductRepository.Get",
+		"product/internal/repository/postgres.(*EventRepository).SelectSince.func1": "EventRepository.SelectSince",
+		"product/internal/repository/postgres.migrate":                              "migrate",
+	}
+
+	for function, want := range tests {
+		if got := shortName(function); got != want {
+			t.Errorf("shortName(%q) = %q, want %q", function, got, want)
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	defaultReplicaCheck = 5 * time.Second
+	// replicaCheckTimeout bounds a single health check of a replica.
+	replicaCheckTimeout = 2 * time.Second
+)
+
+// replicaLagQuery returns how far behind the primary a replica is, in
+// seconds. A replica that has replayed everything it received is not behind,
+// however long ago the last write on the primary was.
+const replicaLagQuery = `
+	SELECT COALESCE(EXTRACT(EPOCH FROM CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN INTERVAL '0'
+		ELSE now() - pg_last_xact_replay_timestamp() END), 0)`
+
+// replica is a read-only copy of the primary. Reads are sent to it while its
+// last health check passed.
+type replica struct {
+	client  *sqlx.DB
+	healthy atomic.Bool
+}
+
+// WithReplicas sends reads to the replicas at dataSourceNames, see Read. Every
+// checkInterval each replica is pinged and, when maxLag is set, fails the
+// check while it lags further behind the primary.
+func WithReplicas(dataSourceNames []string, checkInterval, maxLag time.Duration) Configuration {
+	return func(d *Database) error {
+		d.replicaSources = dataSourceNames
+		if checkInterval > 0 {
+			d.replicaCheck = checkInterval
+		}
+		d.replicaMaxLag = maxLag
+		return nil
+	}
+}
+
+// connectReplicas opens the pools of the replicas, checks them once and keeps
+// checking them in the background until Close. A replica that is down does
+// not fail the database: reads go to the primary until it is back.
+func (s *Database) connectReplicas() (err error) {
+	if len(s.replicaSources) == 0 {
+		return
+	}
+
+	for _, source := range s.replicaSources {
+		driverName, dataSourceName, err := s.parseDSN(source)
+		if err != nil {
+			return err
+		}
+		if driverName != s.driverName {
+			return fmt.Errorf("store: replica driver %s differs from the primary's %s", driverName, s.driverName)
+		}
+
+		client, err := sqlx.Open(driverName, s.withStatementTimeout(dataSourceName))
+		if err != nil {
+			return err
+		}
+		s.pool.apply(client)
+		s.replicas = append(s.replicas, &replica{client: client})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stop, s.stopped = cancel, make(chan struct{})
+	s.checkReplicas(ctx)
+
+	go func() {
+		defer close(s.stopped)
+
+		ticker := time.NewTicker(s.replicaCheck)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkReplicas(ctx)
+			}
+		}
+	}()
+
+	return
+}
+
+func (s *Database) checkReplicas(ctx context.Context) {
+	for _, r := range s.replicas {
+		r.healthy.Store(r.check(ctx, s.replicaMaxLag) == nil)
+	}
+}
+
+func (r *replica) check(ctx context.Context, maxLag time.Duration) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, replicaCheckTimeout)
+	defer cancel()
+
+	var lag float64
+	if err = r.client.QueryRowContext(ctx, replicaLagQuery).Scan(&lag); err != nil {
+		return
+	}
+
+	if behind := time.Duration(lag * float64(time.Second)); maxLag > 0 && behind > maxLag {
+		return fmt.Errorf("store: replica is %s behind the primary", behind)
+	}
+
+	return
+}
+
+// Replicas returns the clients of the replicas, e.g. to watch their pools.
+func (s *Database) Replicas() []*sqlx.DB {
+	clients := make([]*sqlx.DB, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		clients = append(clients, r.client)
+	}
+	return clients
+}
+
+// ReadClient returns the client reads from ctx should go to: the next
+// healthy replica in turn, or the primary when none is healthy or the
+// session of ctx has written.
+func (s *Database) ReadClient(ctx context.Context) *sqlx.DB {
+	if r := s.readReplica(ctx); r != nil {
+		return r.client
+	}
+	return s.Client
+}
+
+// readReplica returns the replica reads from ctx should go to, or nil when
+// they should go to the primary, see ReadClient.
+func (s *Database) readReplica(ctx context.Context) *replica {
+	if len(s.replicas) == 0 || written(ctx) {
+		return nil
+	}
+
+	start := s.next.Add(1)
+	for i := range uint64(len(s.replicas)) {
+		if r := s.replicas[(start+i)%uint64(len(s.replicas))]; r.healthy.Load() {
+			return r
+		}
+	}
+
+	return nil
+}
+
+// failover reads from a replica and, when the replica cannot be reached,
+// reads from the primary instead. The replica is left out of reads until its
+// next health check passes.
+type failover struct {
+	replica *replica
+	primary *sqlx.DB
+}
+
+func (f failover) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	result, err := f.replica.client.ExecContext(ctx, query, args...)
+	if f.lost(ctx, err) {
+		return f.primary.ExecContext(ctx, query, args...)
+	}
+	return result, err
+}
+
+func (f failover) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	row := f.replica.client.QueryRowContext(ctx, query, args...)
+	if f.lost(ctx, row.Err()) {
+		return f.primary.QueryRowContext(ctx, query, args...)
+	}
+	return row
+}
+
+func (f failover) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	err := f.replica.client.GetContext(ctx, dest, query, args...)
+	if f.lost(ctx, err) {
+		return f.primary.GetContext(ctx, dest, query, args...)
+	}
+	return err
+}
+
+func (f failover) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	err := f.replica.client.SelectContext(ctx, dest, query, args...)
+	if f.lost(ctx, err) {
+		return f.primary.SelectContext(ctx, dest, query, args...)
+	}
+	return err
+}
+
+// lost reports whether err means the replica could not be reached, in which
+// case it is marked unhealthy. Reads whose context is done are not retried.
+func (f failover) lost(ctx context.Context, err error) bool {
+	if !connectionError(err) || ctx.Err() != nil {
+		return false
+	}
+	f.replica.healthy.Store(false)
+	return true
+}
+
+// connectionError reports whether err is a failure to reach the server or a
+// connection it dropped, rather than an error of the statement.
+func connectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// connection_exception, and the server shutting down or starting up.
+		return pqErr.Code.Class() == "08" || pqErr.Code == "57P01" || pqErr.Code == "57P02" || pqErr.Code == "57P03"
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNREFUSED)
+}
+
+type sessionKey struct{}
+
+// session remembers whether a unit of work such as a request has written.
+type session struct {
+	written atomic.Bool
+}
+
+// WithSession starts a session of read-your-writes consistency, e.g. for a
+// request. Once the session writes, through Conn or a transaction, its reads
+// go to the primary rather than to replicas that may not have the write yet.
+// Without a session reads always go to replicas.
+func WithSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, &session{})
+}
+
+// WithoutSession leaves ctx out of its session, so that bookkeeping writes,
+// such as recording when an API key was last used, do not send the reads of
+// the session to the primary.
+func WithoutSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, (*session)(nil))
+}
+
+func markWritten(ctx context.Context) {
+	if s, ok := ctx.Value(sessionKey{}).(*session); ok && s != nil {
+		s.written.Store(true)
+	}
+}
+
+func written(ctx context.Context) bool {
+	s, ok := ctx.Value(sessionKey{}).(*session)
+	return ok && s != nil && s.written.Load()
+}
@@ -0,0 +1,323 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// server is a database/sql connector that answers every query with its name,
+// or fails with err.
+type server struct {
+	name         string
+	err          error
+	queries      atomic.Int64
+	transactions atomic.Int64
+}
+
+func (s *server) Connect(ctx context.Context) (driver.Conn, error) { return conn{s}, nil }
+func (s *server) Driver() driver.Driver                            { return nil }
+
+func (s *server) client() *sqlx.DB {
+	return sqlx.NewDb(sql.OpenDB(s), "postgres")
+}
+
+type conn struct {
+	server *server
+}
+
+func (c conn) Prepare(query string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c conn) Close() error                              { return nil }
+func (c conn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+func (c conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if c.server.err != nil {
+		return nil, c.server.err
+	}
+	c.server.transactions.Add(1)
+	return tx{}, nil
+}
+
+type tx struct{}
+
+func (tx) Commit() error   { return nil }
+func (tx) Rollback() error { return nil }
+
+func (c conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.server.queries.Add(1)
+	if c.server.err != nil {
+		return nil, c.server.err
+	}
+	return &rows{value: c.server.name}, nil
+}
+
+func (c conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.server.queries.Add(1)
+	if c.server.err != nil {
+		return nil, c.server.err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type rows struct {
+	value string
+	done  bool
+}
+
+func (r *rows) Columns() []string { return []string{"name"} }
+func (r *rows) Close() error      { return nil }
+
+func (r *rows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+// newReplicatedDatabase returns a database with a primary and a healthy
+// replica for each of the servers.
+func newReplicatedDatabase(t *testing.T, primary *server, replicas ...*server) *Database {
+	t.Helper()
+
+	db := &Database{Client: primary.client()}
+	t.Cleanup(func() { db.Client.Close() })
+	for _, s := range replicas {
+		r := &replica{client: s.client()}
+		r.healthy.Store(true)
+		db.replicas = append(db.replicas, r)
+		t.Cleanup(func() { r.client.Close() })
+	}
+	return db
+}
+
+func (s *Database) readFrom(t *testing.T, ctx context.Context) string {
+	t.Helper()
+
+	for _, r := range s.replicas {
+		if s.ReadClient(ctx) == r.client {
+			return "replica"
+		}
+	}
+	if s.ReadClient(ctx) == s.Client {
+		return "primary"
+	}
+	t.Fatal("ReadClient() returned an unknown client")
+	return ""
+}
+
+func TestReadClient(t *testing.T) {
+	primary, first, second := &server{name: "primary"}, &server{name: "first"}, &server{name: "second"}
+
+	t.Run("without replicas", func(t *testing.T) {
+		db := newReplicatedDatabase(t, primary)
+		if db.ReadClient(context.Background()) != db.Client {
+			t.Error("ReadClient() is not the primary without replicas")
+		}
+	})
+
+	t.Run("replicas in turn", func(t *testing.T) {
+		db := newReplicatedDatabase(t, primary, first, second)
+		seen := map[*sqlx.DB]int{}
+		for range 4 {
+			seen[db.ReadClient(context.Background())]++
+		}
+		if seen[db.replicas[0].client] != 2 || seen[db.replicas[1].client] != 2 {
+			t.Errorf("reads spread %v over the replicas, want 2 each", seen)
+		}
+	})
+
+	t.Run("unhealthy replica left out", func(t *testing.T) {
+		db := newReplicatedDatabase(t, primary, first, second)
+		db.replicas[0].healthy.Store(false)
+		for range 4 {
+			if client := db.ReadClient(context.Background()); client != db.replicas[1].client {
+				t.Fatal("ReadClient() returned a client other than the healthy replica")
+			}
+		}
+
+		db.replicas[1].healthy.Store(false)
+		if db.ReadClient(context.Background()) != db.Client {
+			t.Error("ReadClient() is not the primary without a healthy replica")
+		}
+	})
+
+	t.Run("session sticks to the primary once it wrote", func(t *testing.T) {
+		db := newReplicatedDatabase(t, primary, first)
+		ctx := WithSession(context.Background())
+
+		if got := db.readFrom(t, ctx); got != "replica" {
+			t.Errorf("session reads from the %s before writing, want the replica", got)
+		}
+
+		// Bookkeeping outside the session does not make it stick.
+		Conn(WithoutSession(ctx), db)
+		if got := db.readFrom(t, ctx); got != "replica" {
+			t.Errorf("session reads from the %s after a write outside it, want the replica", got)
+		}
+
+		Conn(ctx, db)
+		for range 3 {
+			if got := db.readFrom(t, ctx); got != "primary" {
+				t.Errorf("session reads from the %s after writing, want the primary", got)
+			}
+		}
+		if got := db.readFrom(t, context.Background()); got != "replica" {
+			t.Errorf("other reads go to the %s, want the replica", got)
+		}
+	})
+}
+
+func TestReadFailsOverToPrimary(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	tests := []struct {
+		name string
+		err  error
+		// want is the server that answered, or empty when the read failed.
+		want string
+	}{
+		{"refused", refused, "primary"},
+		{"bad connection", driver.ErrBadConn, "primary"},
+		{"dropped", io.ErrUnexpectedEOF, "primary"},
+		{"shutting down", &pq.Error{Code: "57P01"}, "primary"},
+		{"connection failure", &pq.Error{Code: "08006"}, "primary"},
+		{"statement error", &pq.Error{Code: "42P01"}, ""},
+		{"other error", errors.New("boom"), ""},
+	}
+
+	reads := map[string]func(ctx context.Context, e Executor) (string, error){
+		"GetContext": func(ctx context.Context, e Executor) (name string, err error) {
+			err = e.GetContext(ctx, &name, "SELECT name")
+			return
+		},
+		"SelectContext": func(ctx context.Context, e Executor) (string, error) {
+			var names []string
+			if err := e.SelectContext(ctx, &names, "SELECT name"); err != nil || len(names) != 1 {
+				return "", err
+			}
+			return names[0], nil
+		},
+		"QueryRowContext": func(ctx context.Context, e Executor) (name string, err error) {
+			err = e.QueryRowContext(ctx, "SELECT name").Scan(&name)
+			return
+		},
+	}
+
+	for _, tt := range tests {
+		for method, read := range reads {
+			t.Run(tt.name+"/"+method, func(t *testing.T) {
+				primary, down := &server{name: "primary"}, &server{name: "replica", err: tt.err}
+				db := newReplicatedDatabase(t, primary, down)
+
+				got, err := read(context.Background(), Read(context.Background(), db))
+				if got != tt.want {
+					t.Errorf("read from %q (error %v), want %q", got, err, tt.want)
+				}
+				if tt.want == "" && err == nil {
+					t.Error("read did not fail")
+				}
+				if healthy := db.replicas[0].healthy.Load(); healthy != (tt.want == "") {
+					t.Errorf("replica healthy = %t after the read", healthy)
+				}
+				if tt.want == "" && primary.queries.Load() != 0 {
+					t.Error("a failed statement was run again on the primary")
+				}
+			})
+		}
+	}
+}
+
+func TestReadDoesNotFailOverWhenCanceled(t *testing.T) {
+	primary, down := &server{name: "primary"}, &server{name: "replica", err: driver.ErrBadConn}
+	db := newReplicatedDatabase(t, primary, down)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e := Read(ctx, db)
+	cancel()
+
+	var name string
+	if err := e.GetContext(ctx, &name, "SELECT name"); err == nil {
+		t.Errorf("read %q with a canceled context", name)
+	}
+	if primary.queries.Load() != 0 {
+		t.Error("a canceled read was run again on the primary")
+	}
+}
+
+func TestWithReadTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		replica error
+		want    string
+	}{
+		{"on the replica", nil, "replica"},
+		{"on the primary when the replica is down", driver.ErrBadConn, "primary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, replica := &server{name: "primary"}, &server{name: "replica", err: tt.replica}
+			db := newReplicatedDatabase(t, primary, replica)
+
+			var got string
+			committed := false
+			err := WithReadTransaction(context.Background(), db, func(ctx context.Context) error {
+				if !InTransaction(ctx) {
+					t.Error("fn runs outside a transaction")
+				}
+				AfterCommit(ctx, func() { committed = true })
+				return Read(ctx, db).GetContext(ctx, &got, "SELECT name")
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("read from the %s, want the %s", got, tt.want)
+			}
+			if want := map[string]*server{"primary": primary, "replica": replica}[tt.want]; want.transactions.Load() != 1 {
+				t.Errorf("no transaction begun on the %s", tt.want)
+			}
+			if !committed {
+				t.Error("hook did not run after the commit")
+			}
+		})
+	}
+}
+
+func TestConnectionError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, true},
+		{fmt.Errorf("query: %w", syscall.ECONNREFUSED), true},
+		{driver.ErrBadConn, true},
+		{io.EOF, true},
+		{&pq.Error{Code: "08001"}, true},
+		{&pq.Error{Code: "57P03"}, true},
+		{&pq.Error{Code: "57014"}, false},
+		{&pq.Error{Code: "23505"}, false},
+		{sql.ErrNoRows, false},
+		{context.Canceled, false},
+		{nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := connectionError(tt.err); got != tt.want {
+			t.Errorf("connectionError(%v) = %t, want %t", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RetryPolicy sets how a transaction that failed on a serialization failure
+// or a deadlock is run again. Other errors are never retried.
+type RetryPolicy struct {
+	// Attempts is how many times the transaction runs at most, 1 to never retry.
+	Attempts int
+	// Backoff is the wait before the first retry, doubled after every other
+	// one up to MaxBackoff. Waits are jittered so that the transactions that
+	// conflicted do not meet again.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy is the policy of a new Database.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:   3,
+	Backoff:    20 * time.Millisecond,
+	MaxBackoff: time.Second,
+}
+
+// backoff returns how long to wait before the attempt after the given one.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	wait := p.Backoff
+	for i := 1; i < attempt; i++ {
+		if wait *= 2; p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			wait = p.MaxBackoff
+			break
+		}
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	return wait/2 + rand.N(wait/2+1)
+}
+
+// retryable reports whether err is a serialization failure or a deadlock,
+// after which the transaction may succeed when run again.
+func retryable(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
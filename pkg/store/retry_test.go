@@ -0,0 +1,64 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pq.Error{Code: "40001"}, true},
+		{"deadlock", &pq.Error{Code: "40P01"}, true},
+		{"wrapped serialization failure", fmt.Errorf("update: %w", &pq.Error{Code: "40001"}), true},
+		{"translated deadlock", Translate(&pq.Error{Code: "40P01"}), true},
+		{"unique violation", &pq.Error{Code: "23505"}, false},
+		{"other error", errors.New("connection refused"), false},
+		{"no error", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := retryable(tt.err); got != tt.want {
+				t.Errorf("retryable(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{Attempts: 10, Backoff: 20 * time.Millisecond, MaxBackoff: 100 * time.Millisecond}
+
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{1, 20 * time.Millisecond},
+		{2, 40 * time.Millisecond},
+		{3, 80 * time.Millisecond},
+		{4, 100 * time.Millisecond},
+		{50, 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		for range 100 {
+			if wait := policy.backoff(tt.attempt); wait < tt.max/2 || wait > tt.max {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempt, wait, tt.max/2, tt.max)
+			}
+		}
+	}
+
+	if wait := (RetryPolicy{Attempts: 3}).backoff(1); wait != 0 {
+		t.Errorf("backoff() = %s without a backoff, want 0", wait)
+	}
+	if wait := (RetryPolicy{Attempts: 10, Backoff: time.Millisecond}).backoff(5); wait < 8*time.Millisecond || wait > 16*time.Millisecond {
+		t.Errorf("backoff(5) = %s without a maximum, want between 8ms and 16ms", wait)
+	}
+}
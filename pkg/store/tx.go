@@ -0,0 +1,273 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Executor is the part of *sqlx.DB and *sqlx.Tx that repositories use. Its
+// errors are passed through Translate.
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) Row
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+// Row is the result of Executor.QueryRowContext.
+type Row interface {
+	Scan(dest ...any) error
+}
+
+// queryer is implemented by both *sqlx.DB and *sqlx.Tx.
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+type executor struct {
+	queryer
+}
+
+func (e executor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	o := observe(ctx, query)
+	result, err := e.queryer.ExecContext(ctx, query, args...)
+	o.done(err)
+	return result, Translate(err)
+}
+
+func (e executor) QueryRowContext(ctx context.Context, query string, args ...any) Row {
+	o := observe(ctx, query)
+	return row{Row: e.queryer.QueryRowContext(ctx, query, args...), observation: o}
+}
+
+func (e executor) GetContext(ctx context.Context, dest any, query string, args ...any) error {
+	o := observe(ctx, query)
+	err := e.queryer.GetContext(ctx, dest, query, args...)
+	o.done(err)
+	return Translate(err)
+}
+
+func (e executor) SelectContext(ctx context.Context, dest any, query string, args ...any) error {
+	o := observe(ctx, query)
+	err := e.queryer.SelectContext(ctx, dest, query, args...)
+	o.done(err)
+	return Translate(err)
+}
+
+// row reports its query once it is scanned, since errors only surface then.
+type row struct {
+	*sql.Row
+	observation observation
+}
+
+func (r row) Scan(dest ...any) error {
+	err := r.Row.Scan(dest...)
+	r.observation.done(err)
+	return Translate(err)
+}
+
+// Transactor runs a function inside a single database transaction. Called
+// with a context that already carries a transaction, fn runs in a savepoint
+// of it instead: an error undoes the work of fn alone and the enclosing
+// transaction may go on.
+type Transactor interface {
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// transaction is the state of the transaction carried by a context.
+type transaction struct {
+	tx         *sqlx.Tx
+	hooks      []func()
+	savepoints int
+}
+
+type txKey struct{}
+
+type isolationKey struct{}
+
+func current(ctx context.Context) *transaction {
+	t, _ := ctx.Value(txKey{}).(*transaction)
+	return t
+}
+
+// Conn returns the transaction carried by ctx, or the primary of db when
+// there is none. Whatever goes through it counts as a write of the session
+// of ctx, see WithSession.
+func Conn(ctx context.Context, db *Database) Executor {
+	if t := current(ctx); t != nil {
+		return executor{t.tx}
+	}
+	markWritten(ctx)
+	return executor{db.Client}
+}
+
+// Read returns the transaction carried by ctx, or the client of db chosen by
+// ReadClient. Read-only methods whose results may lag behind the primary for
+// a moment use it instead of Conn. A read that cannot reach its replica is
+// sent to the primary.
+func Read(ctx context.Context, db *Database) Executor {
+	if t := current(ctx); t != nil {
+		return executor{t.tx}
+	}
+	if r := db.readReplica(ctx); r != nil {
+		return executor{failover{replica: r, primary: db.Client}}
+	}
+	return executor{db.Client}
+}
+
+// InTransaction reports whether ctx carries a transaction.
+func InTransaction(ctx context.Context) bool {
+	return current(ctx) != nil
+}
+
+// AfterCommit runs fn once the transaction carried by ctx is committed, and
+// never if it is rolled back, be it whole or to the savepoint fn was
+// registered in. Without a transaction fn runs right away.
+func AfterCommit(ctx context.Context, fn func()) {
+	if t := current(ctx); t != nil {
+		t.hooks = append(t.hooks, fn)
+		return
+	}
+	fn()
+}
+
+// WithIsolation makes the transactions begun from ctx run at level, e.g.
+// sql.LevelSerializable for a read-modify-write that must not interleave
+// with another. Savepoints keep the level of their transaction.
+func WithIsolation(ctx context.Context, level sql.IsolationLevel) context.Context {
+	return context.WithValue(ctx, isolationKey{}, level)
+}
+
+// WithTransaction calls fn with a context carrying a transaction on the
+// primary of db. The transaction is committed when fn returns nil and rolled
+// back otherwise, and run again under the retry policy of db when it fails
+// on a serialization failure or a deadlock, so fn should only have effects
+// through ctx or AfterCommit. When ctx already carries a transaction fn runs
+// in a savepoint of it.
+func WithTransaction(ctx context.Context, db *Database, fn func(ctx context.Context) error) (err error) {
+	if t := current(ctx); t != nil {
+		return t.savepoint(ctx, fn)
+	}
+	markWritten(ctx)
+
+	for attempt := 1; ; attempt++ {
+		if err = run(ctx, db.Client, fn); err == nil || attempt >= db.retry.Attempts || !retryable(err) {
+			return
+		}
+
+		trace.SpanFromContext(ctx).AddEvent("transaction retried", trace.WithAttributes(
+			attribute.Int("attempt", attempt), attribute.String("error", err.Error())))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(db.retry.backoff(attempt)):
+		}
+	}
+}
+
+// run runs fn in a transaction of its own.
+func run(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context) error) (err error) {
+	level, _ := ctx.Value(isolationKey{}).(sql.IsolationLevel)
+	tx, err := db.BeginTxx(ctx, &sql.TxOptions{Isolation: level})
+	if err != nil {
+		return
+	}
+	defer tx.Rollback()
+
+	t := &transaction{tx: tx}
+	if err = fn(context.WithValue(ctx, txKey{}, t)); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return Translate(err)
+	}
+
+	for _, hook := range t.hooks {
+		hook()
+	}
+
+	return
+}
+
+// WithReadTransaction calls fn with a context carrying a read-only
+// transaction on the client of db chosen by ReadClient, e.g. to read through
+// a cursor. When the replica cannot be reached the transaction is begun on
+// the primary instead. When ctx already carries a transaction fn runs in it.
+func WithReadTransaction(ctx context.Context, db *Database, fn func(ctx context.Context) error) (err error) {
+	if current(ctx) != nil {
+		return fn(ctx)
+	}
+
+	options := &sql.TxOptions{ReadOnly: true}
+	var tx *sqlx.Tx
+	if r := db.readReplica(ctx); r != nil {
+		tx, err = r.client.BeginTxx(ctx, options)
+		if (failover{replica: r, primary: db.Client}).lost(ctx, err) {
+			tx, err = db.Client.BeginTxx(ctx, options)
+		}
+	} else {
+		tx, err = db.Client.BeginTxx(ctx, options)
+	}
+	if err != nil {
+		return Translate(err)
+	}
+	defer tx.Rollback()
+
+	t := &transaction{tx: tx}
+	if err = fn(context.WithValue(ctx, txKey{}, t)); err != nil {
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		return Translate(err)
+	}
+
+	for _, hook := range t.hooks {
+		hook()
+	}
+
+	return
+}
+
+// savepoint runs fn in a savepoint of t, rolled back to when fn fails.
+func (t *transaction) savepoint(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	t.savepoints++
+	name := fmt.Sprintf("savepoint_%d", t.savepoints)
+	conn := executor{t.tx}
+
+	if _, err = conn.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return
+	}
+
+	hooks := len(t.hooks)
+	if err = fn(ctx); err != nil {
+		// The savepoint must be rolled back even when ctx is canceled, or the
+		// enclosing transaction is left aborted.
+		if _, rollbackErr := conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+name); rollbackErr != nil {
+			return errors.Join(err, rollbackErr)
+		}
+		t.hooks = t.hooks[:hooks]
+		return
+	}
+
+	_, err = conn.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+
+	return
+}
+
+// Transaction implements Transactor on the database, see WithTransaction.
+func (s *Database) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return WithTransaction(ctx, s, fn)
+}
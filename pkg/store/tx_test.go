@@ -0,0 +1,200 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+
+	"product/pkg/apperror"
+)
+
+// openTestDatabase connects to the scratch database at TEST_POSTGRES_DSN with
+// an empty table of numbers, and skips the test when the variable is not set.
+func openTestDatabase(t *testing.T, configs ...Configuration) *Database {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := NewDatabase("public", dsn, configs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for _, query := range []string{
+		"CREATE TABLE IF NOT EXISTS store_tx_test (n INT NOT NULL)",
+		"TRUNCATE store_tx_test",
+	} {
+		if _, err = db.Client.Exec(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { db.Client.Exec("DROP TABLE IF EXISTS store_tx_test") })
+
+	return db
+}
+
+func insert(ctx context.Context, db *Database, n int) error {
+	_, err := Conn(ctx, db).ExecContext(ctx, "INSERT INTO store_tx_test (n) VALUES ($1)", n)
+	return err
+}
+
+func numbers(t *testing.T, db *Database) (res []int) {
+	t.Helper()
+
+	if err := db.Client.Select(&res, "SELECT n FROM store_tx_test ORDER BY n"); err != nil {
+		t.Fatal(err)
+	}
+	return
+}
+
+var errFailed = errors.New("failed")
+
+func TestSavepoint(t *testing.T) {
+	db := openTestDatabase(t)
+	ctx := context.Background()
+
+	var ran []string
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		if err := insert(ctx, db, 1); err != nil {
+			return err
+		}
+		AfterCommit(ctx, func() { ran = append(ran, "outer") })
+
+		err := db.Transaction(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = append(ran, "failed savepoint") })
+			if err := insert(ctx, db, 2); err != nil {
+				return err
+			}
+			return errFailed
+		})
+		if !errors.Is(err, errFailed) {
+			t.Errorf("savepoint error = %v, want %v", err, errFailed)
+		}
+
+		// A failed statement aborts the transaction; rolling back to the
+		// savepoint lets it go on.
+		err = db.Transaction(ctx, func(ctx context.Context) error {
+			_, err := Conn(ctx, db).ExecContext(ctx, "INSERT INTO store_tx_test (n) VALUES (NULL)")
+			return err
+		})
+		if !apperror.Is(err, apperror.Validation) {
+			t.Errorf("savepoint error = %v, want the not null violation", err)
+		}
+
+		// A savepoint rolled back after its context was canceled leaves the
+		// enclosing transaction usable too.
+		canceled, cancel := context.WithCancel(ctx)
+		err = db.Transaction(canceled, func(ctx context.Context) error {
+			cancel()
+			return ctx.Err()
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("savepoint error = %v, want %v", err, context.Canceled)
+		}
+
+		return db.Transaction(ctx, func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = append(ran, "released savepoint") })
+			return insert(ctx, db, 3)
+		})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := numbers(t, db), []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("committed %v, want %v", got, want)
+	}
+	if want := []string{"outer", "released savepoint"}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("hooks ran = %v, want %v", ran, want)
+	}
+}
+
+func TestAfterCommitOnRollback(t *testing.T) {
+	db := openTestDatabase(t)
+	ctx := context.Background()
+
+	ran := false
+	err := db.Transaction(ctx, func(ctx context.Context) error {
+		AfterCommit(ctx, func() { ran = true })
+		if err := insert(ctx, db, 1); err != nil {
+			return err
+		}
+		return errFailed
+	})
+	if !errors.Is(err, errFailed) {
+		t.Fatalf("Transaction() error = %v, want %v", err, errFailed)
+	}
+
+	if ran {
+		t.Error("hook ran for a rolled back transaction")
+	}
+	if got := numbers(t, db); len(got) != 0 {
+		t.Errorf("committed %v after a rollback", got)
+	}
+
+	AfterCommit(ctx, func() { ran = true })
+	if !ran {
+		t.Error("hook did not run at once outside a transaction")
+	}
+}
+
+func TestTransactionRetry(t *testing.T) {
+	db := openTestDatabase(t, WithRetryPolicy(RetryPolicy{Attempts: 3, Backoff: time.Millisecond}))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		failures []error
+		want     error
+		attempts int
+		numbers  []int
+	}{
+		{"serialization failure", []error{&pq.Error{Code: "40001"}}, nil, 2, []int{2}},
+		{"deadlock", []error{&pq.Error{Code: "40P01"}}, nil, 2, []int{2}},
+		{"out of attempts", []error{&pq.Error{Code: "40001"}, &pq.Error{Code: "40001"}, &pq.Error{Code: "40001"}}, &pq.Error{Code: "40001"}, 3, nil},
+		{"not retryable", []error{errFailed}, errFailed, 1, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := db.Client.Exec("TRUNCATE store_tx_test"); err != nil {
+				t.Fatal(err)
+			}
+
+			attempts, hooks := 0, 0
+			err := db.Transaction(ctx, func(ctx context.Context) error {
+				attempts++
+				AfterCommit(ctx, func() { hooks++ })
+				if err := insert(ctx, db, attempts); err != nil {
+					return err
+				}
+				if attempts <= len(tt.failures) {
+					return tt.failures[attempts-1]
+				}
+				return nil
+			})
+
+			if tt.want == nil && err != nil || tt.want != nil && (err == nil || err.Error() != tt.want.Error()) {
+				t.Errorf("Transaction() error = %v, want %v", err, tt.want)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("ran %d times, want %d", attempts, tt.attempts)
+			}
+			if got := numbers(t, db); !reflect.DeepEqual(got, tt.numbers) {
+				t.Errorf("committed %v, want %v", got, tt.numbers)
+			}
+			if want := min(len(tt.numbers), 1); hooks != want {
+				t.Errorf("hooks ran %d times, want %d", hooks, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,313 @@
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"product/pkg/apperror"
+	"product/pkg/country"
+)
+
+// Codes tell clients why a field was rejected. They are stable, unlike the
+// messages that go with them.
+const (
+	CodeRequired  = "required"
+	CodeTooShort  = "too_short"
+	CodeTooLong   = "too_long"
+	CodeTooSmall  = "too_small"
+	CodeTooLarge  = "too_large"
+	CodeUUID      = "invalid_uuid"
+	CodeCountry   = "invalid_country"
+	CodeURL       = "invalid_url"
+	CodeEmail     = "invalid_email"
+	CodeChoice    = "invalid_choice"
+	CodeDuplicate = "duplicate"
+	CodeNotFound  = "not_found"
+	CodeInvalid   = "invalid"
+)
+
+// FieldError describes why a single field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors lists every rejected field of a request.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldError := range e {
+		messages = append(messages, fieldError.Field+": "+fieldError.Message)
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add appends a field error.
+func (e *Errors) Add(field, code, message string) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Prefix returns a copy of e with prefix put in front of every field, e.g. to
+// place the errors of a nested request.
+func (e Errors) Prefix(prefix string) Errors {
+	res := make(Errors, len(e))
+	for i, fieldError := range e {
+		fieldError.Field = prefix + fieldError.Field
+		res[i] = fieldError
+	}
+	return res
+}
+
+// Err returns nil when e is empty and a validation error listing e otherwise.
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return apperror.Wrap(apperror.Validation, e).WithDetails(e)
+}
+
+// Struct checks the fields of the struct v points to against the rules in
+// their `validate` tags and returns every field that breaks one. Fields are
+// named after their JSON keys. The rules are
+//
+//	required	the field cannot be blank or zero
+//	min=N		strings have at least N characters, numbers are at least N
+//	max=N		strings have at most N characters, numbers are at most N
+//	uuid		the field is a UUID
+//	country		the field is an ISO 3166-1 alpha-2 code
+//	url			the field is an absolute http or https URL
+//	email		the field is an email address
+//	oneof=a|b	the field is one of the listed values
+//
+// Rules other than required and the numeric bounds skip blank fields, and
+// only the first broken rule of a field is reported. Struct panics when a tag
+// is malformed; Register finds those at startup instead.
+func Struct(v any) (errs Errors) {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	fields, err := fieldsOf(value.Type())
+	if err != nil {
+		panic(err)
+	}
+	for _, field := range fields {
+		if code, message := field.check(value.FieldByIndex(field.index)); code != "" {
+			errs.Add(field.name, code, message)
+		}
+	}
+	return
+}
+
+// Register parses the tags of the structs values point to, so that a
+// malformed one, such as min=x or an empty oneof, fails when the program
+// starts rather than on the first request. It panics on the first malformed
+// tag.
+func Register(values ...any) {
+	for _, v := range values {
+		if _, err := fieldsOf(reflect.Indirect(reflect.ValueOf(v)).Type()); err != nil {
+			panic(err)
+		}
+	}
+}
+
+type rule struct {
+	name string
+	arg  string
+	// bound is the argument of min and max.
+	bound float64
+	// choices are the values allowed by oneof.
+	choices []string
+}
+
+type field struct {
+	index []int
+	name  string
+	rules []rule
+}
+
+type parsed struct {
+	fields []field
+	err    error
+}
+
+var cache sync.Map
+
+// fieldsOf parses the tags of a struct type once.
+func fieldsOf(t reflect.Type) ([]field, error) {
+	if res, ok := cache.Load(t); ok {
+		return res.(parsed).fields, res.(parsed).err
+	}
+
+	fields, err := parseFields(t)
+	cache.Store(t, parsed{fields: fields, err: err})
+	return fields, err
+}
+
+func parseFields(t reflect.Type) (fields []field, err error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validate: %s is not a struct", t)
+	}
+
+	for _, sf := range reflect.VisibleFields(t) {
+		tag, ok := sf.Tag.Lookup("validate")
+		if !ok || !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "" {
+			name = sf.Name
+		}
+
+		f := field{index: sf.Index, name: name}
+		for _, part := range strings.Split(tag, ",") {
+			r, err := parseRule(strings.TrimSpace(part), sf.Type.Kind())
+			if err != nil {
+				return nil, fmt.Errorf("validate: %s.%s: %w", t, sf.Name, err)
+			}
+			f.rules = append(f.rules, r)
+		}
+		fields = append(fields, f)
+	}
+
+	return
+}
+
+// parseRule parses a rule of a field of the given kind and checks that its
+// argument fits it.
+func parseRule(part string, kind reflect.Kind) (r rule, err error) {
+	r.name, r.arg, _ = strings.Cut(part, "=")
+
+	isString := kind == reflect.String
+	isNumber := kind >= reflect.Int && kind <= reflect.Int64 || kind == reflect.Float32 || kind == reflect.Float64
+
+	switch r.name {
+	case "required", "uuid", "country", "url", "email":
+		if r.arg != "" {
+			return r, fmt.Errorf("rule %s takes no argument, got %q", r.name, r.arg)
+		}
+		if r.name != "required" && !isString {
+			return r, fmt.Errorf("rule %s does not apply to a %s", r.name, kind)
+		}
+	case "min", "max":
+		switch {
+		case isString:
+			n, err := strconv.Atoi(r.arg)
+			if err != nil || n < 0 {
+				return r, fmt.Errorf("rule %s needs a length, got %q", r.name, r.arg)
+			}
+			r.bound = float64(n)
+		case isNumber:
+			if r.bound, err = strconv.ParseFloat(r.arg, 64); err != nil {
+				return r, fmt.Errorf("rule %s needs a number, got %q", r.name, r.arg)
+			}
+		default:
+			return r, fmt.Errorf("rule %s does not apply to a %s", r.name, kind)
+		}
+	case "oneof":
+		if !isString {
+			return r, fmt.Errorf("rule %s does not apply to a %s", r.name, kind)
+		}
+		r.choices = strings.Split(r.arg, "|")
+		if slices.Contains(r.choices, "") {
+			return r, fmt.Errorf("rule oneof needs values, got %q", r.arg)
+		}
+	default:
+		return r, fmt.Errorf("unknown rule %q", part)
+	}
+
+	return
+}
+
+func (f field) check(value reflect.Value) (code, message string) {
+	for _, r := range f.rules {
+		if code, message = r.check(value); code != "" {
+			return
+		}
+	}
+	return
+}
+
+func (r rule) check(value reflect.Value) (code, message string) {
+	switch value.Kind() {
+	case reflect.String:
+		return r.checkString(value.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return r.checkNumber(float64(value.Int()))
+	case reflect.Float32, reflect.Float64:
+		return r.checkNumber(value.Float())
+	default:
+		if r.name == "required" && value.IsZero() {
+			return CodeRequired, "cannot be blank"
+		}
+		return
+	}
+}
+
+func (r rule) checkString(s string) (code, message string) {
+	if strings.TrimSpace(s) == "" {
+		if r.name == "required" {
+			return CodeRequired, "cannot be blank"
+		}
+		return
+	}
+
+	switch r.name {
+	case "min":
+		if n := int(r.bound); utf8.RuneCountInString(s) < n {
+			return CodeTooShort, fmt.Sprintf("must be at least %d characters long", n)
+		}
+	case "max":
+		if n := int(r.bound); utf8.RuneCountInString(s) > n {
+			return CodeTooLong, fmt.Sprintf("must be at most %d characters long", n)
+		}
+	case "uuid":
+		if _, err := uuid.Parse(s); err != nil || len(s) != 36 {
+			return CodeUUID, "must be a UUID"
+		}
+	case "country":
+		if !country.Valid(s) {
+			return CodeCountry, "must be an ISO 3166-1 alpha-2 code"
+		}
+	case "url":
+		if u, err := url.Parse(s); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return CodeURL, "must be an absolute http or https URL"
+		}
+	case "email":
+		if _, err := mail.ParseAddress(s); err != nil {
+			return CodeEmail, "must be an email address"
+		}
+	case "oneof":
+		if !slices.Contains(r.choices, s) {
+			return CodeChoice, "must be one of " + strings.Join(r.choices, ", ")
+		}
+	}
+	return
+}
+
+func (r rule) checkNumber(n float64) (code, message string) {
+	switch r.name {
+	case "required":
+		if n == 0 {
+			return CodeRequired, "cannot be zero"
+		}
+	case "min":
+		if n < r.bound {
+			return CodeTooSmall, "must be at least " + r.arg
+		}
+	case "max":
+		if n > r.bound {
+			return CodeTooLarge, "must be at most " + r.arg
+		}
+	}
+	return
+}
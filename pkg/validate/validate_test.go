@@ -0,0 +1,198 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+
+	"product/pkg/apperror"
+)
+
+type request struct {
+	Name     string  `json:"name" validate:"required,min=2,max=5"`
+	ID       string  `json:"id" validate:"uuid"`
+	Country  string  `json:"country" validate:"country"`
+	Homepage string  `json:"homepage" validate:"url"`
+	Email    string  `json:"email" validate:"email"`
+	Kind     string  `json:"kind" validate:"oneof=a|b"`
+	Count    int     `json:"count" validate:"required,min=1,max=10"`
+	Ratio    float64 `json:"ratio,omitempty" validate:"min=-0.5,max=0.5"`
+	Tags     []int   `json:"tags" validate:"required"`
+	Untagged string  `json:"untagged"`
+	Bare     string  `validate:"max=1"`
+	hidden   string  `validate:"required"`
+}
+
+// valid returns a request that breaks no rule.
+func valid() request {
+	return request{
+		Name:     "Cola",
+		ID:       "7c9e6679-7425-40de-944b-e07fc1f90ae7",
+		Country:  "RU",
+		Homepage: "https://example.com/cola",
+		Email:    "sales@example.com",
+		Kind:     "a",
+		Count:    3,
+		Tags:     []int{1},
+	}
+}
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(r *request)
+		field   string
+		code    string
+		message string
+	}{
+		{"blank required string", func(r *request) { r.Name = "  " }, "name", CodeRequired, "cannot be blank"},
+		{"string too short", func(r *request) { r.Name = "C" }, "name", CodeTooShort, "must be at least 2 characters long"},
+		{"string too long", func(r *request) { r.Name = "Colada" }, "name", CodeTooLong, "must be at most 5 characters long"},
+		{"string length in characters", func(r *request) { r.Name = "Кола" }, "", "", ""},
+		{"invalid uuid", func(r *request) { r.ID = "7c9e6679" }, "id", CodeUUID, "must be a UUID"},
+		{"uuid without dashes", func(r *request) { r.ID = "7c9e6679742540de944be07fc1f90ae7" }, "id", CodeUUID, "must be a UUID"},
+		{"blank uuid", func(r *request) { r.ID = "" }, "", "", ""},
+		{"invalid country", func(r *request) { r.Country = "XX" }, "country", CodeCountry, "must be an ISO 3166-1 alpha-2 code"},
+		{"relative url", func(r *request) { r.Homepage = "/cola" }, "homepage", CodeURL, "must be an absolute http or https URL"},
+		{"url of another scheme", func(r *request) { r.Homepage = "ftp://example.com" }, "homepage", CodeURL, "must be an absolute http or https URL"},
+		{"invalid email", func(r *request) { r.Email = "sales" }, "email", CodeEmail, "must be an email address"},
+		{"unknown choice", func(r *request) { r.Kind = "c" }, "kind", CodeChoice, "must be one of a, b"},
+		{"zero required number", func(r *request) { r.Count = 0 }, "count", CodeRequired, "cannot be zero"},
+		{"number too small", func(r *request) { r.Count = -1 }, "count", CodeTooSmall, "must be at least 1"},
+		{"number too large", func(r *request) { r.Count = 11 }, "count", CodeTooLarge, "must be at most 10"},
+		{"float too small", func(r *request) { r.Ratio = -0.6 }, "ratio", CodeTooSmall, "must be at least -0.5"},
+		{"float too large", func(r *request) { r.Ratio = 0.6 }, "ratio", CodeTooLarge, "must be at most 0.5"},
+		{"empty required slice", func(r *request) { r.Tags = nil }, "tags", CodeRequired, "cannot be blank"},
+		{"field named after the struct field", func(r *request) { r.Bare = "ab" }, "Bare", CodeTooLong, "must be at most 1 characters long"},
+		{"unexported field", func(r *request) { r.hidden = "" }, "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+
+			errs := Struct(&r)
+			if tt.field == "" {
+				if len(errs) != 0 {
+					t.Errorf("Struct() = %v, want no errors", errs)
+				}
+				return
+			}
+
+			want := Errors{{Field: tt.field, Code: tt.code, Message: tt.message}}
+			if len(errs) != 1 || errs[0] != want[0] {
+				t.Errorf("Struct() = %+v, want %+v", errs, want)
+			}
+		})
+	}
+}
+
+func TestStructReportsEveryField(t *testing.T) {
+	r := valid()
+	r.Name, r.ID, r.Count = "", "x", 0
+
+	errs := Struct(r)
+
+	var fields []string
+	for _, fieldError := range errs {
+		fields = append(fields, fieldError.Field+"="+fieldError.Code)
+	}
+	if got, want := strings.Join(fields, " "), "name=required id=invalid_uuid count=required"; got != want {
+		t.Errorf("Struct() fields = %s, want %s", got, want)
+	}
+}
+
+func TestMalformedTags(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"non-numeric length", &struct {
+			Name string `validate:"min=x"`
+		}{}, `rule min needs a length, got "x"`},
+		{"negative length", &struct {
+			Name string `validate:"max=-1"`
+		}{}, `rule max needs a length, got "-1"`},
+		{"non-numeric bound", &struct {
+			Count int `validate:"max=ten"`
+		}{}, `rule max needs a number, got "ten"`},
+		{"bound on a slice", &struct {
+			Tags []string `validate:"min=1"`
+		}{}, "rule min does not apply to a slice"},
+		{"empty oneof", &struct {
+			Kind string `validate:"oneof="`
+		}{}, `rule oneof needs values, got ""`},
+		{"blank oneof value", &struct {
+			Kind string `validate:"oneof=a||b"`
+		}{}, `rule oneof needs values, got "a||b"`},
+		{"string rule on a number", &struct {
+			Count int `validate:"uuid"`
+		}{}, "rule uuid does not apply to a int"},
+		{"argument to a rule without one", &struct {
+			Name string `validate:"required=yes"`
+		}{}, `rule required takes no argument, got "yes"`},
+		{"unknown rule", &struct {
+			Name string `validate:"required,lowercase"`
+		}{}, `unknown rule "lowercase"`},
+		{"trailing comma", &struct {
+			Name string `validate:"required,"`
+		}{}, `unknown rule ""`},
+		{"not a struct", new(string), "is not a struct"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, fn := range map[string]func(){
+				"Register": func() { Register(tt.value) },
+				"Struct":   func() { Struct(tt.value) },
+			} {
+				func() {
+					defer func() {
+						err, _ := recover().(error)
+						if err == nil || !strings.Contains(err.Error(), tt.want) {
+							t.Errorf("%s() panicked with %v, want an error containing %q", name, err, tt.want)
+						}
+					}()
+					fn()
+				}()
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	defer func() {
+		if r := recover(); r != nil {
+			t.Errorf("Register() panicked with %v for well-formed tags", r)
+		}
+	}()
+
+	Register(&request{}, request{})
+}
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	if err := errs.Err(); err != nil {
+		t.Errorf("Err() = %v for no errors, want nil", err)
+	}
+
+	errs.Add("name", CodeRequired, "cannot be blank")
+	errs.Add("options[0].name", CodeTooLong, "must be at most 5 characters long")
+
+	prefixed := errs.Prefix("items[1].")
+	if prefixed[0].Field != "items[1].name" || prefixed[1].Field != "items[1].options[0].name" {
+		t.Errorf("Prefix() = %+v", prefixed)
+	}
+	if errs[0].Field != "name" {
+		t.Errorf("Prefix() changed the receiver to %+v", errs)
+	}
+
+	err := errs.Err()
+	if !apperror.Is(err, apperror.Validation) {
+		t.Errorf("Err() = %v, want a validation error", err)
+	}
+	if want := "name: cannot be blank; options[0].name: must be at most 5 characters long"; err.Error() != want {
+		t.Errorf("Err() = %q, want %q", err.Error(), want)
+	}
+}